@@ -0,0 +1,423 @@
+package main
+
+import (
+	"PRService/pkg/client"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+var errUsage = errors.New("usage")
+
+type command func(ctx context.Context, cfg *config, args []string) error
+
+func run(ctx context.Context, cfg *config, args []string) error {
+	commands := map[string]command{
+		"teams":      teamsCommand,
+		"users":      usersCommand,
+		"prs":        prsCommand,
+		"reassign":   reassignCommand,
+		"deactivate": deactivateCommand,
+		"stats":      statsCommand,
+		"migrations": migrationsCommand,
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return errUsage
+	}
+	return cmd(ctx, cfg, args[1:])
+}
+
+// subcommand выбирает подкоманду из первого аргумента.
+func subcommand(ctx context.Context, cfg *config, args []string, subs map[string]command) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	cmd, ok := subs[args[0]]
+	if !ok {
+		return errUsage
+	}
+	return cmd(ctx, cfg, args[1:])
+}
+
+// parseArgs разбирает флаги команды вперемешку с позиционными аргументами
+// и возвращает позиционные; want < 0 — любое их число.
+func parseArgs(cfg *config, name string, args []string, want int, define func(fs *flag.FlagSet)) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	cfg.register(fs)
+	if define != nil {
+		define(fs)
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if !validOutput(cfg.output) {
+		return nil, fmt.Errorf("unknown output format %q", cfg.output)
+	}
+	if want >= 0 && len(positional) != want {
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+func teamsCommand(ctx context.Context, cfg *config, args []string) error {
+	return subcommand(ctx, cfg, args, map[string]command{
+		"get": func(ctx context.Context, cfg *config, args []string) error {
+			pos, err := parseArgs(cfg, "teams get", args, 1, nil)
+			if err != nil {
+				return err
+			}
+			team, err := newClient(cfg).GetTeam(ctx, pos[0])
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, team, teamTable)
+		},
+		"add": func(ctx context.Context, cfg *config, args []string) error {
+			var file string
+			_, err := parseArgs(cfg, "teams add", args, 0, func(fs *flag.FlagSet) {
+				fs.StringVar(&file, "f", "-", "файл с командой в JSON, - — stdin")
+			})
+			if err != nil {
+				return err
+			}
+			body, err := readInput(file)
+			if err != nil {
+				return err
+			}
+			var team client.Team
+			if err := json.Unmarshal(body, &team); err != nil {
+				return fmt.Errorf("invalid team: %w", err)
+			}
+			team, err = newClient(cfg).CreateTeam(ctx, team)
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, team, teamTable)
+		},
+	})
+}
+
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(file)
+}
+
+func usersCommand(ctx context.Context, cfg *config, args []string) error {
+	return subcommand(ctx, cfg, args, map[string]command{
+		"get": func(ctx context.Context, cfg *config, args []string) error {
+			pos, err := parseArgs(cfg, "users get", args, 1, nil)
+			if err != nil {
+				return err
+			}
+			user, err := newClient(cfg).GetUser(ctx, pos[0])
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, user, userTable)
+		},
+		"list": func(ctx context.Context, cfg *config, args []string) error {
+			var team, prefix, active, cursor string
+			var limit int
+			_, err := parseArgs(cfg, "users list", args, 0, func(fs *flag.FlagSet) {
+				fs.StringVar(&team, "team", "", "команда")
+				fs.StringVar(&prefix, "prefix", "", "начало имени пользователя")
+				fs.StringVar(&active, "active", "", "true или false")
+				fs.IntVar(&limit, "limit", 0, "размер страницы")
+				fs.StringVar(&cursor, "cursor", "", "курсор следующей страницы")
+			})
+			if err != nil {
+				return err
+			}
+			filter := client.UserListFilter{TeamName: team, UsernamePrefix: prefix, Limit: limit}
+			if active != "" {
+				isActive, err := strconv.ParseBool(active)
+				if err != nil {
+					return fmt.Errorf("invalid active %q", active)
+				}
+				filter.IsActive = &isActive
+			}
+			if cursor != "" {
+				if filter.AfterUserID, err = client.DecodeUserCursor(cursor); err != nil {
+					return fmt.Errorf("invalid cursor %q", cursor)
+				}
+			}
+			page, err := newClient(cfg).ListUsers(ctx, filter)
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, page, func(w io.Writer, page client.UserPage) {
+				usersTable(w, page.Users)
+				nextCursor(w, page.NextCursor)
+			})
+		},
+		"set-active": func(ctx context.Context, cfg *config, args []string) error {
+			pos, err := parseArgs(cfg, "users set-active", args, 2, nil)
+			if err != nil {
+				return err
+			}
+			active, err := strconv.ParseBool(pos[1])
+			if err != nil {
+				return fmt.Errorf("invalid is_active %q", pos[1])
+			}
+			user, err := newClient(cfg).SetUserActive(ctx, pos[0], active)
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, user, userTable)
+		},
+	})
+}
+
+func userTable(w io.Writer, user client.User) {
+	usersTable(w, []client.User{user})
+}
+
+func prsCommand(ctx context.Context, cfg *config, args []string) error {
+	return subcommand(ctx, cfg, args, map[string]command{
+		"get": func(ctx context.Context, cfg *config, args []string) error {
+			pos, err := parseArgs(cfg, "prs get", args, 1, nil)
+			if err != nil {
+				return err
+			}
+			pr, err := newClient(cfg).GetPR(ctx, pos[0])
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, pr, prTable)
+		},
+		"list": func(ctx context.Context, cfg *config, args []string) error {
+			var author, reviewer, team, status, cursor string
+			var limit int
+			_, err := parseArgs(cfg, "prs list", args, 0, func(fs *flag.FlagSet) {
+				fs.StringVar(&author, "author", "", "автор")
+				fs.StringVar(&reviewer, "reviewer", "", "назначенный ревьювер")
+				fs.StringVar(&team, "team", "", "команда автора")
+				fs.StringVar(&status, "status", "", "OPEN или MERGED")
+				fs.IntVar(&limit, "limit", 0, "размер страницы")
+				fs.StringVar(&cursor, "cursor", "", "курсор следующей страницы")
+			})
+			if err != nil {
+				return err
+			}
+			filter := client.PRListFilter{
+				AuthorID:   author,
+				ReviewerID: reviewer,
+				TeamName:   team,
+				Status:     client.PullRequestStatus(status),
+				Limit:      limit,
+			}
+			if cursor != "" {
+				if filter.Cursor, err = client.DecodePRCursor(cursor); err != nil {
+					return fmt.Errorf("invalid cursor %q", cursor)
+				}
+				filter.SortBy, filter.Desc = filter.Cursor.SortBy, filter.Cursor.Desc
+			}
+			page, err := newClient(cfg).ListPRs(ctx, filter)
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, page, func(w io.Writer, page client.PRPage) {
+				prsTable(w, page.PullRequests)
+				nextCursor(w, page.NextCursor)
+			})
+		},
+		"create": func(ctx context.Context, cfg *config, args []string) error {
+			var id, name, author string
+			_, err := parseArgs(cfg, "prs create", args, 0, func(fs *flag.FlagSet) {
+				fs.StringVar(&id, "id", "", "pull_request_id")
+				fs.StringVar(&name, "name", "", "pull_request_name")
+				fs.StringVar(&author, "author", "", "author_id")
+			})
+			if err != nil {
+				return err
+			}
+			if id == "" || name == "" || author == "" {
+				return errUsage
+			}
+			pr, err := newClient(cfg).CreatePR(ctx, client.CreatePRRequest{
+				PullRequestID:   id,
+				PullRequestName: name,
+				AuthorID:        author,
+			})
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, pr, prTable)
+		},
+		"merge": func(ctx context.Context, cfg *config, args []string) error {
+			var version int64
+			pos, err := parseArgs(cfg, "prs merge", args, 1, func(fs *flag.FlagSet) {
+				fs.Int64Var(&version, "if-match", 0, "ожидаемая версия PR")
+			})
+			if err != nil {
+				return err
+			}
+			pr, err := newClient(cfg).MergePR(ctx, pos[0], version)
+			if err != nil {
+				return err
+			}
+			return printResult(os.Stdout, cfg.output, pr, prTable)
+		},
+	})
+}
+
+func prTable(w io.Writer, pr client.PullRequest) {
+	prsTable(w, []client.PullRequest{pr})
+}
+
+func reassignCommand(ctx context.Context, cfg *config, args []string) error {
+	var to string
+	var version int64
+	pos, err := parseArgs(cfg, "reassign", args, 2, func(fs *flag.FlagSet) {
+		fs.StringVar(&to, "to", "", "новый ревьювер; без него выбирается автоматически")
+		fs.Int64Var(&version, "if-match", 0, "ожидаемая версия PR")
+	})
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		PR         client.PullRequest `json:"pr"`
+		ReplacedBy string             `json:"replaced_by"`
+	}
+	c := newClient(cfg)
+	res := result{ReplacedBy: to}
+	if to == "" {
+		res.PR, res.ReplacedBy, err = c.ReassignReviewer(ctx, pos[0], pos[1], version)
+	} else {
+		res.PR, err = c.ReassignReviewerTo(ctx, pos[0], pos[1], to, version)
+	}
+	if err != nil {
+		return err
+	}
+	return printResult(os.Stdout, cfg.output, res, func(w io.Writer, res result) {
+		prsTable(w, []client.PullRequest{res.PR})
+		fmt.Fprintf(w, "\n%s replaced by %s\n", pos[1], res.ReplacedBy)
+	})
+}
+
+func deactivateCommand(ctx context.Context, cfg *config, args []string) error {
+	userIDs, err := parseArgs(cfg, "deactivate", args, -1, nil)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return errUsage
+	}
+
+	results, err := newClient(cfg).DeactivateUsers(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	return printResult(os.Stdout, cfg.output, map[string]map[string]string{"results": results}, func(w io.Writer, resp map[string]map[string]string) {
+		resultsTable(w, resp["results"])
+	})
+}
+
+func statsCommand(ctx context.Context, cfg *config, args []string) error {
+	var team, status, from, to string
+	pos, err := parseArgs(cfg, "stats", args, -1, func(fs *flag.FlagSet) {
+		fs.StringVar(&team, "team", "", "команда автора")
+		fs.StringVar(&status, "status", "", "OPEN или MERGED")
+		fs.StringVar(&from, "from", "", "начало периода, RFC 3339 или YYYY-MM-DD")
+		fs.StringVar(&to, "to", "", "конец периода, RFC 3339 или YYYY-MM-DD")
+	})
+	if err != nil {
+		return err
+	}
+
+	filter := client.StatsFilter{TeamName: team, Status: client.PullRequestStatus(status)}
+	if filter.From, err = parseTime(from); err != nil {
+		return fmt.Errorf("invalid from %q", from)
+	}
+	if filter.To, err = parseTime(to); err != nil {
+		return fmt.Errorf("invalid to %q", to)
+	}
+
+	report := "summary"
+	if len(pos) > 0 {
+		report = pos[0]
+	}
+	if len(pos) > 1 {
+		return errUsage
+	}
+
+	c := newClient(cfg)
+	switch report {
+	case "summary":
+		stats, err := c.GetStats(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return printResult(os.Stdout, cfg.output, stats, statsTable)
+	case "cycle-time":
+		stats, err := c.GetCycleTimeStats(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return printResult(os.Stdout, cfg.output, stats, nil)
+	case "fairness":
+		report, err := c.GetFairnessReport(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return printResult(os.Stdout, cfg.output, report, nil)
+	}
+	return errUsage
+}
+
+// parseTime принимает RFC 3339 или дату YYYY-MM-DD (полночь UTC), как и API.
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, s); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func migrationsCommand(ctx context.Context, cfg *config, args []string) error {
+	pos, err := parseArgs(cfg, "migrations", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	c := newClient(cfg)
+	var status client.MigrationStatus
+	switch pos[0] {
+	case "status":
+		status, err = c.MigrationStatus(ctx)
+	case "up":
+		status, err = c.MigrateUp(ctx)
+	default:
+		return errUsage
+	}
+	if err != nil {
+		return err
+	}
+	return printResult(os.Stdout, cfg.output, status, migrationTable)
+}
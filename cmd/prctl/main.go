@@ -0,0 +1,103 @@
+// prctl — утилита оператора сервиса: обёртка над HTTP API с выводом в таблицу, JSON или YAML.
+package main
+
+import (
+	"PRService/pkg/client"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+const usageText = `usage: prctl [flags] <command> [args]
+
+commands:
+  teams get <team_name>
+  teams add [-f file]                   команда в формате /team/add (по умолчанию из stdin)
+  users get <user_id>
+  users list [-team t] [-prefix p] [-active true|false] [-limit n] [-cursor c]
+  users set-active <user_id> <true|false>
+  prs get <pull_request_id>
+  prs list [-author u] [-reviewer u] [-team t] [-status s] [-limit n] [-cursor c]
+  prs create -id <id> -name <name> -author <user_id>
+  prs merge [-if-match version] <pull_request_id>
+  reassign [-to user_id] [-if-match version] <pull_request_id> <old_reviewer_id>
+  deactivate <user_id>...
+  stats [summary|cycle-time|fairness] [-team t] [-status s] [-from date] [-to date]
+  migrations status|up                  требует ADMIN_TOKEN сервера в -token
+
+flags (можно указывать и после команды):
+`
+
+type config struct {
+	url     string
+	token   string
+	output  string
+	timeout time.Duration
+}
+
+// register добавляет общие флаги в fs; значения по умолчанию берутся из уже заполненного cfg,
+// поэтому флаги команды не сбрасывают заданные до неё.
+func (c *config) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.url, "url", c.url, "базовый URL сервиса (PRCTL_URL)")
+	fs.StringVar(&c.token, "token", c.token, "токен для Authorization: Bearer (PRCTL_TOKEN)")
+	fs.StringVar(&c.output, "o", c.output, "формат вывода: table, json или yaml")
+	fs.DurationVar(&c.timeout, "timeout", c.timeout, "таймаут запроса")
+}
+
+func main() {
+	cfg := &config{
+		url:     envOr("PRCTL_URL", "http://localhost:8080"),
+		token:   os.Getenv("PRCTL_TOKEN"),
+		output:  "table",
+		timeout: 30 * time.Second,
+	}
+
+	fs := flag.NewFlagSet("prctl", flag.ExitOnError)
+	cfg.register(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usageText)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(os.Args[1:])
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	err := run(context.Background(), cfg, fs.Args())
+	if errors.Is(err, errUsage) {
+		fs.Usage()
+		os.Exit(2)
+	}
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		code := apiErr.Code
+		if code == "" {
+			code = http.StatusText(apiErr.StatusCode)
+		}
+		fmt.Fprintf(os.Stderr, "prctl: %s (%d)\n", code, apiErr.StatusCode)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "prctl:", err)
+		os.Exit(1)
+	}
+}
+
+func newClient(cfg *config) *client.Client {
+	return client.New(cfg.url,
+		client.WithToken(cfg.token),
+		client.WithHTTPClient(&http.Client{Timeout: cfg.timeout}),
+	)
+}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
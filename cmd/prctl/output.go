@@ -0,0 +1,150 @@
+package main
+
+import (
+	"PRService/pkg/client"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+func validOutput(format string) bool {
+	switch format {
+	case "table", "json", "yaml":
+		return true
+	}
+	return false
+}
+
+// printResult выводит результат в выбранном формате. JSON и YAML повторяют поля ответа API,
+// без table вместо таблицы выводится YAML.
+func printResult[T any](w io.Writer, format string, v T, table func(w io.Writer, v T)) error {
+	switch {
+	case format == "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case format == "yaml" || table == nil:
+		// через JSON, чтобы имена полей совпадали с json-тегами
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var data interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(data); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	table(tw, v)
+	return tw.Flush()
+}
+
+func row(w io.Writer, cols ...interface{}) {
+	for i, c := range cols {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, c)
+	}
+	fmt.Fprintln(w)
+}
+
+func optInt(v *int) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(*v)
+}
+
+func teamTable(w io.Writer, team client.Team) {
+	fmt.Fprintf(w, "team: %s\n\n", team.TeamName)
+	row(w, "USER_ID", "USERNAME", "ACTIVE", "AVAILABLE", "MAX_OPEN_REVIEWS")
+	for _, m := range team.Members {
+		row(w, m.UserID, m.Username, m.IsActive, m.IsAvailable, optInt(m.MaxOpenReviews))
+	}
+}
+
+func usersTable(w io.Writer, users []client.User) {
+	row(w, "USER_ID", "USERNAME", "TEAM", "ACTIVE", "MAX_OPEN_REVIEWS")
+	for _, u := range users {
+		row(w, u.UserID, u.Username, u.TeamName, u.IsActive, optInt(u.MaxOpenReviews))
+	}
+}
+
+func prsTable(w io.Writer, prs []client.PullRequest) {
+	row(w, "PULL_REQUEST_ID", "NAME", "AUTHOR", "STATUS", "VERSION", "REVIEWERS")
+	for _, pr := range prs {
+		row(w, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.Version, strings.Join(pr.AssignedReviewers, ","))
+	}
+}
+
+func nextCursor(w io.Writer, cursor string) {
+	if cursor != "" {
+		fmt.Fprintf(w, "\nnext cursor: %s\n", cursor)
+	}
+}
+
+func resultsTable(w io.Writer, results map[string]string) {
+	ids := make([]string, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	row(w, "USER_ID", "RESULT")
+	for _, id := range ids {
+		row(w, id, results[id])
+	}
+}
+
+func statsTable(w io.Writer, stats client.Stats) {
+	teams := make([]string, 0, len(stats.Teams))
+	for name := range stats.Teams {
+		teams = append(teams, name)
+	}
+	sort.Strings(teams)
+
+	row(w, "TEAM", "PRS", "OPEN", "MERGED", "ASSIGNMENTS", "OPEN_ASSIGNMENTS", "REVIEWERS")
+	for _, name := range teams {
+		t := stats.Teams[name]
+		row(w, name, t.PullRequests, t.OpenPullRequests, t.MergedPullRequests, t.Assignments, t.OpenAssignments, t.Reviewers)
+	}
+
+	reviewers := make([]string, 0, len(stats.ReviewerAssignments))
+	for id := range stats.ReviewerAssignments {
+		reviewers = append(reviewers, id)
+	}
+	sort.Strings(reviewers)
+
+	fmt.Fprintln(w)
+	row(w, "REVIEWER", "ASSIGNMENTS", "OPEN")
+	for _, id := range reviewers {
+		row(w, id, stats.ReviewerAssignments[id], stats.ReviewerOpenAssignments[id])
+	}
+}
+
+func migrationTable(w io.Writer, status client.MigrationStatus) {
+	state := "up to date"
+	switch {
+	case status.Dirty:
+		state = "dirty"
+	case status.Version < status.Latest:
+		state = "pending"
+	case status.Version > status.Latest:
+		state = "ahead of service"
+	}
+	row(w, "VERSION", "LATEST", "STATE")
+	row(w, status.Version, status.Latest, state)
+}
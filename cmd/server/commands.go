@@ -0,0 +1,127 @@
+package main
+
+import (
+	"PRService/internal/adapters/postgres"
+	"PRService/internal/domain"
+	"PRService/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// runCommand выполняет служебную команду вместо запуска HTTP-сервера:
+//
+//	server export [-o file]
+//	server import [-on-conflict skip|overwrite|fail] [file]
+//	server migrate up|down N|goto VERSION|force VERSION|status
+func runCommand(ctx context.Context, repo *postgres.Repo, name string, args []string) error {
+	service := services.NewService(repo)
+
+	switch name {
+	case "export":
+		fs := flag.NewFlagSet("export", flag.ExitOnError)
+		out := fs.String("o", "", "файл выгрузки (по умолчанию stdout)")
+		_ = fs.Parse(args)
+
+		if *out == "" {
+			return service.Export(ctx, os.Stdout)
+		}
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		if err := service.Export(ctx, f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+
+	case "import":
+		fs := flag.NewFlagSet("import", flag.ExitOnError)
+		policy := fs.String("on-conflict", string(domain.ConflictSkip), "skip, overwrite или fail")
+		_ = fs.Parse(args)
+		if !domain.ConflictPolicy(*policy).Valid() {
+			return fmt.Errorf("invalid -on-conflict %q", *policy)
+		}
+
+		r := io.Reader(os.Stdin)
+		if fs.NArg() > 0 {
+			f, err := os.Open(fs.Arg(0))
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+		stats, err := service.Import(ctx, r, domain.ConflictPolicy(*policy))
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+
+	case "migrate":
+		return migrateCommand(ctx, repo, args)
+
+	default:
+		return fmt.Errorf("unknown command %q (expected export, import or migrate)", name)
+	}
+}
+
+func migrateCommand(ctx context.Context, repo *postgres.Repo, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down N|goto VERSION|force VERSION|status")
+	}
+
+	var arg int
+	switch args[0] {
+	case "down", "goto", "force":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: migrate %s N", args[0])
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid %s argument %q", args[0], args[1])
+		}
+		arg = n
+	default:
+		if len(args) != 1 {
+			return fmt.Errorf("migrate %s takes no arguments", args[0])
+		}
+	}
+
+	var err error
+	switch args[0] {
+	case "up":
+		err = repo.Migrate()
+	case "down":
+		err = repo.MigrateDown(arg)
+	case "goto":
+		if arg < 0 {
+			return fmt.Errorf("invalid version %d", arg)
+		}
+		err = repo.MigrateTo(uint(arg))
+	case "force":
+		err = repo.ForceMigrationVersion(arg)
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := repo.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(status)
+}
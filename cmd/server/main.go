@@ -1,15 +1,30 @@
 package main
 
 import (
+	"PRService/internal/adapters/events"
+	"PRService/internal/adapters/github"
+	"PRService/internal/adapters/graphql"
+	grpcadapter "PRService/internal/adapters/grpc"
 	httphandler "PRService/internal/adapters/http"
 	"PRService/internal/adapters/postgres"
+	"PRService/internal/domain"
+	"PRService/internal/ports"
 	"PRService/internal/services"
+	prservicev1 "PRService/pkg/pb/prservice/v1"
+	"context"
+	"errors"
 	"log"
+	"math"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -31,34 +46,203 @@ func main() {
 		}
 	}()
 
-	service := services.NewService(repo)
+	if len(os.Args) > 1 {
+		if err := runCommand(context.Background(), repo, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	handler := &httphandler.Handler{
-		S: service,
+	if boolEnv("AUTO_MIGRATE") {
+		if err := repo.Migrate(); err != nil {
+			log.Fatalf("migration failed: %v", err)
+		}
+	}
+	checkSchema(repo)
+
+	// broker раздаёт события назначений подписчикам gRPC-стрима
+	broker := events.NewBroker(64)
+	opts := []services.Option{
+		services.WithAssignmentMode(domain.AssignmentMode(os.Getenv("ASSIGNMENT_MODE"))),
+		services.WithEventPublisher(events.Multi{events.LogPublisher{}, broker}),
 	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		opts = append(opts, services.WithCodeHost(github.NewClient(os.Getenv("GITHUB_API_URL"), token)))
+	}
+
+	service := services.NewService(repo, opts...)
+	defer service.Close()
 
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	// SIGINT/SIGTERM останавливают фоновые задачи и запускают плавную остановку серверов
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-	r.Post("/team/add", handler.CreateTeam)
-	r.Get("/team/get", handler.GetTeam)
+	go service.RunUnavailabilityWatcher(ctx, durationEnv("UNAVAILABILITY_CHECK_INTERVAL", time.Minute))
+	go service.RunSLAScheduler(ctx, durationEnv("SLA_CHECK_INTERVAL", time.Minute))
+	go httphandler.PurgeIdempotencyKeys(ctx, repo, time.Hour)
 
-	r.Post("/users/setIsActive", handler.SetUserActive)
-	r.Post("/users/deactivate", handler.DeactivateUsersHandler) // безопасная массовая деактивация
-	r.Get("/users/getReview", handler.GetUserPRs)
+	var rateLimits ports.RateLimitStore = httphandler.NewMemoryRateLimitStore()
+	switch store := os.Getenv("RATE_LIMIT_STORE"); store {
+	case "", "memory":
+	case "postgres":
+		rateLimits = repo
+	default:
+		log.Fatalf("invalid RATE_LIMIT_STORE: %q", store)
+	}
+	go httphandler.PurgeRateLimitBuckets(ctx, rateLimits, time.Minute)
 
-	r.Post("/pullRequest/create", handler.CreatePR)
-	r.Post("/pullRequest/merge", handler.MergePR)
-	r.Post("/pullRequest/reassign", handler.ReassignReviewer)
+	rateLimitToken, rateLimitIP := rateLimitEnv("RATE_LIMIT_TOKEN"), rateLimitEnv("RATE_LIMIT_IP")
 
-	r.Get("/stats", handler.GetStats)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcadapter.RecoveryUnary(), grpcadapter.RateLimitUnary(rateLimits, rateLimitToken, rateLimitIP)),
+		grpc.ChainStreamInterceptor(grpcadapter.RecoveryStream(), grpcadapter.RateLimitStream(rateLimits, rateLimitToken, rateLimitIP)),
+	)
+	prservicev1.RegisterPRServiceServer(grpcServer, &grpcadapter.Server{S: service, Events: broker})
+	go serveGRPC(grpcServer, envOr("GRPC_ADDR", ":9090"))
 
-	port := ":8080"
-	log.Printf("Server listening on port %s", port)
-	err := http.ListenAndServe(port, r)
-	if err != nil {
+	handler := &httphandler.Handler{
+		S:          service,
+		Migrations: repo,
+	}
+
+	r := httphandler.NewRouter(handler, httphandler.RouterConfig{
+		RateLimits: rateLimits,
+		RateLimit: httphandler.RateLimitConfig{
+			PerToken: rateLimitToken,
+			PerIP:    rateLimitIP,
+		},
+		MaxBodyBytes:   intEnv("MAX_REQUEST_BODY_BYTES", 1<<20),
+		Idempotency:    repo,
+		IdempotencyTTL: durationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		AdminToken:     os.Getenv("ADMIN_TOKEN"),
+		GraphQL:        graphql.NewHandler(service, repo),
+	})
+
+	srv := &http.Server{Addr: ":8080", Handler: r}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdown(srv, grpcServer, durationEnv("SHUTDOWN_TIMEOUT", 30*time.Second))
+	}()
+
+	log.Printf("Server listening on port %s", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Printf("server failed: %v", err)
 		return
 	}
+	<-stopped
+}
+
+// serveGRPC поднимает gRPC API на отдельном порту рядом с HTTP.
+func serveGRPC(s *grpc.Server, addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("grpc listen on %s: %v", addr, err)
+	}
+	log.Printf("gRPC server listening on %s", addr)
+	if err := s.Serve(lis); err != nil {
+		log.Printf("grpc server failed: %v", err)
+	}
+}
+
+// shutdown дожидается завершения начатых запросов HTTP и gRPC, но не дольше timeout:
+// стримы событий сами не заканчиваются, поэтому по истечении срока gRPC останавливается жёстко.
+func shutdown(srv *http.Server, grpcServer *grpc.Server, timeout time.Duration) {
+	log.Printf("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("http shutdown: %v", err)
+	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+	}
+}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return d
+}
+
+func boolEnv(name string) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return b
+}
+
+// checkSchema не даёт запустить сервер на схеме, отставшей от встроенных миграций
+// или оставшейся после упавшей миграции. Более новая схема допустима: её мог применить
+// следующий релиз, пока этот ещё обслуживает запросы.
+func checkSchema(repo *postgres.Repo) {
+	status, err := repo.MigrationStatus(context.Background())
+	if err != nil {
+		log.Fatalf("failed to check schema version: %v", err)
+	}
+	switch {
+	case status.Dirty:
+		log.Fatalf("schema version %d is dirty; repair it manually and run `migrate force VERSION`", status.Version)
+	case status.Version < status.Latest:
+		log.Fatalf("schema version %d is behind %d; run `migrate up` or set AUTO_MIGRATE=true", status.Version, status.Latest)
+	case status.Version > status.Latest:
+		log.Printf("schema version %d is ahead of %d known to this build", status.Version, status.Latest)
+	}
+}
+
+func intEnv(name string, def int64) int64 {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		log.Fatalf("invalid %s: %q", name, v)
+	}
+	return n
+}
+
+// rateLimitEnv читает <prefix>_RPS и <prefix>_BURST; без <prefix>_RPS ограничение выключено,
+// а ёмкость корзины по умолчанию равна числу запросов за секунду.
+func rateLimitEnv(prefix string) domain.RateLimit {
+	v := os.Getenv(prefix + "_RPS")
+	if v == "" {
+		return domain.RateLimit{}
+	}
+	rps, err := strconv.ParseFloat(v, 64)
+	if err != nil || rps <= 0 {
+		log.Fatalf("invalid %s_RPS: %q", prefix, v)
+	}
+	return domain.RateLimit{
+		PerSecond: rps,
+		Burst:     int(intEnv(prefix+"_BURST", int64(math.Max(1, math.Ceil(rps))))),
+	}
 }
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"log"
+	"sync"
+)
+
+// Broker раздаёт события подписчикам внутри процесса. Подписчик, который не успевает
+// читать, теряет события: публикация не должна задерживать изменение PR.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[chan domain.Event]struct{}
+	buffer int
+}
+
+func NewBroker(buffer int) *Broker {
+	return &Broker{subs: make(map[chan domain.Event]struct{}), buffer: buffer}
+}
+
+func (b *Broker) Publish(_ context.Context, event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("event %s for PR %s dropped: subscriber is too slow", event.Type, event.PullRequestID)
+		}
+	}
+}
+
+// Subscribe возвращает канал событий, опубликованных после подписки; канал закрывается
+// после отмены ctx.
+func (b *Broker) Subscribe(ctx context.Context) <-chan domain.Event {
+	ch := make(chan domain.Event, b.buffer)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch
+}
+
+// Multi передаёт каждое событие всем публикаторам по очереди.
+type Multi []ports.EventPublisher
+
+func (m Multi) Publish(ctx context.Context, event domain.Event) {
+	for _, p := range m {
+		p.Publish(ctx, event)
+	}
+}
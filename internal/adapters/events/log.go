@@ -0,0 +1,20 @@
+package events
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// LogPublisher пишет события в лог сервиса.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(_ context.Context, event domain.Event) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("event %s: %v", event.Type, err)
+		return
+	}
+	log.Printf("event: %s", b)
+}
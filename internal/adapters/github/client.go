@@ -0,0 +1,84 @@
+package github
+
+import (
+	"PRService/internal/ports"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.github.com"
+
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) RequestReviewers(ctx context.Context, repository string, number int, logins []string) error {
+	return c.requestedReviewers(ctx, http.MethodPost, repository, number, logins)
+}
+
+func (c *Client) RemoveReviewers(ctx context.Context, repository string, number int, logins []string) error {
+	return c.requestedReviewers(ctx, http.MethodDelete, repository, number, logins)
+}
+
+func (c *Client) requestedReviewers(ctx context.Context, method, repository string, number int, logins []string) error {
+	if len(logins) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string][]string{"reviewers": logins})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.baseURL, repository, number)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		if err := Body.Close(); err != nil {
+			log.Printf("github: failed to close body: %v", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("github: %s %s: status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(msg)))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %w", ports.ErrCodeHostRejected, err)
+		}
+		return err
+	}
+	return nil
+}
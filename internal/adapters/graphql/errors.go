@@ -0,0 +1,41 @@
+package graphql
+
+import (
+	"PRService/internal/domain"
+	"errors"
+	"log"
+)
+
+// domainErrors — ошибки, которые отдаются клиенту как есть; код ошибки попадает
+// в extensions.code, как в теле ответов HTTP API.
+var domainErrors = []error{
+	domain.ErrTeamNotFound, domain.ErrNotFound,
+	domain.ErrTeamExists, domain.ErrUserExists, domain.ErrPrExists,
+	domain.ErrPrMerged, domain.ErrNotAssigned, domain.ErrNoCandidate, domain.ErrAlreadyAssigned,
+	domain.ErrNotTeamMember, domain.ErrUserInactive, domain.ErrReviewerLimit, domain.ErrVersionMismatch,
+	domain.ErrReviewerIsAuthor, domain.ErrConcurrentUpdate, domain.ErrInvalidBulkInput,
+	domain.ErrInvalidCursor, domain.ErrInvalidPRFilter, domain.ErrInvalidStatsFilter, domain.ErrInvalidVerdict,
+	domain.ErrInvalidUser, domain.ErrInvalidLimits, domain.ErrInvalidSLA, domain.ErrInvalidCodeOwners, domain.ErrInvalidUnavailability,
+}
+
+type queryError struct {
+	message string
+	code    string
+}
+
+func (e *queryError) Error() string { return e.message }
+
+func (e *queryError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// resolverError прячет внутренние ошибки от клиента и пишет их в лог.
+func resolverError(err error) error {
+	for _, target := range domainErrors {
+		if errors.Is(err, target) {
+			return &queryError{message: err.Error(), code: target.Error()}
+		}
+	}
+	log.Printf("graphql: internal error: %v", err)
+	return &queryError{message: "internal error", code: "INTERNAL"}
+}
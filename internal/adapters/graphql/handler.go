@@ -0,0 +1,30 @@
+// Package graphql отдаёт данные сервиса одной схемой GraphQL для дашбордов.
+package graphql
+
+import (
+	"PRService/internal/ports"
+	"PRService/internal/services"
+	_ "embed"
+	"net/http"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxParallelism ограничивает число резолверов, выполняемых одновременно. Оно же задаёт
+// размер пакета даталоадера для элементов списка, поэтому значение больше умолчания.
+const maxParallelism = 100
+
+// NewHandler возвращает обработчик POST /graphql. Даталоадеры создаются на каждый запрос.
+func NewHandler(s *services.Service, repo ports.Repository) http.Handler {
+	h := &relay.Handler{Schema: gql.MustParseSchema(schema, &resolver{s: s},
+		gql.MaxParallelism(maxParallelism),
+		gql.MaxDepth(10),
+	)}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(withLoaders(r.Context(), newLoaders(repo))))
+	})
+}
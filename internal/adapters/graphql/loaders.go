@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaders собирают обращения резолверов одного запроса в пакетные запросы к репозиторию,
+// чтобы вложенные поля не превращались в N+1 запросов. Кэш живёт до конца запроса.
+type loaders struct {
+	users       *dataloader.Loader[string, domain.User]
+	teams       *dataloader.Loader[string, domain.Team]
+	teamMembers *dataloader.Loader[string, []domain.User]
+	prs         *dataloader.Loader[string, domain.PullRequest]
+	openReviews *dataloader.Loader[string, []domain.PullRequest]
+}
+
+// loaderWait — сколько загрузчик ждёт ключи от соседних резолверов перед запросом.
+const loaderWait = 2 * time.Millisecond
+
+func newLoaders(repo ports.Repository) *loaders {
+	return &loaders{
+		users: newLoader(func(ctx context.Context, ids []string) (map[string]domain.User, error) {
+			users, err := repo.GetUsersByIDs(ctx, ids)
+			return byKey(users, func(u domain.User) string { return u.UserID }), err
+		}),
+		teams: newLoader(func(ctx context.Context, names []string) (map[string]domain.Team, error) {
+			teams, err := repo.GetTeamsByNames(ctx, names)
+			return byKey(teams, func(t domain.Team) string { return t.TeamName }), err
+		}),
+		teamMembers: newListLoader(func(ctx context.Context, names []string) (map[string][]domain.User, error) {
+			users, err := repo.ListUsersByTeams(ctx, names)
+			members := make(map[string][]domain.User)
+			for _, u := range users {
+				members[u.TeamName] = append(members[u.TeamName], u)
+			}
+			return members, err
+		}),
+		prs: newLoader(func(ctx context.Context, ids []string) (map[string]domain.PullRequest, error) {
+			prs, err := repo.GetPRsByIDs(ctx, ids)
+			return byKey(prs, func(pr domain.PullRequest) string { return pr.PullRequestID }), err
+		}),
+		openReviews: newListLoader(func(ctx context.Context, ids []string) (map[string][]domain.PullRequest, error) {
+			prs, err := repo.ListOpenPRsByReviewers(ctx, ids)
+			reviews := make(map[string][]domain.PullRequest)
+			for _, pr := range prs {
+				for _, reviewer := range pr.AssignedReviewers {
+					reviews[reviewer] = append(reviews[reviewer], pr)
+				}
+			}
+			return reviews, err
+		}),
+	}
+}
+
+// newLoader возвращает загрузчик одиночных значений; отсутствующий ключ даёт domain.ErrNotFound.
+func newLoader[V any](fetch func(ctx context.Context, keys []string) (map[string]V, error)) *dataloader.Loader[string, V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []string) []*dataloader.Result[V] {
+		found, err := fetch(ctx, keys)
+		results := make([]*dataloader.Result[V], len(keys))
+		for i, key := range keys {
+			v, ok := found[key]
+			switch {
+			case err != nil:
+				results[i] = &dataloader.Result[V]{Error: err}
+			case !ok:
+				results[i] = &dataloader.Result[V]{Error: domain.ErrNotFound}
+			default:
+				results[i] = &dataloader.Result[V]{Data: v}
+			}
+		}
+		return results
+	}, dataloader.WithWait[string, V](loaderWait))
+}
+
+// newListLoader возвращает загрузчик списков; для отсутствующего ключа список пуст.
+func newListLoader[V any](fetch func(ctx context.Context, keys []string) (map[string][]V, error)) *dataloader.Loader[string, []V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, keys []string) []*dataloader.Result[[]V] {
+		found, err := fetch(ctx, keys)
+		results := make([]*dataloader.Result[[]V], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[[]V]{Data: found[key], Error: err}
+		}
+		return results
+	}, dataloader.WithWait[string, []V](loaderWait))
+}
+
+func byKey[V any](values []V, key func(V) string) map[string]V {
+	m := make(map[string]V, len(values))
+	for _, v := range values {
+		m[key(v)] = v
+	}
+	return m
+}
+
+// primeUser и primePR заменяют закэшированное значение результатом мутации, чтобы
+// последующие поля того же запроса не видели старые данные.
+func (l *loaders) primeUser(ctx context.Context, u domain.User) {
+	l.users.Clear(ctx, u.UserID).Prime(ctx, u.UserID, u)
+	l.teamMembers.Clear(ctx, u.TeamName)
+}
+
+func (l *loaders) primePR(ctx context.Context, pr domain.PullRequest) {
+	l.prs.Clear(ctx, pr.PullRequestID).Prime(ctx, pr.PullRequestID, pr)
+	l.openReviews.ClearAll()
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,212 @@
+package graphql
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/services"
+	"context"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+type teamMemberInput struct {
+	UserID         gql.ID
+	Username       string
+	IsActive       bool
+	MaxOpenReviews *int32
+}
+
+type teamInput struct {
+	TeamName string
+	Members  []teamMemberInput
+}
+
+func (r *resolver) CreateTeam(ctx context.Context, args struct{ Team teamInput }) (*teamResolver, error) {
+	team := domain.Team{TeamName: args.Team.TeamName}
+	for _, m := range args.Team.Members {
+		team.Members = append(team.Members, domain.TeamMember{
+			UserID:         string(m.UserID),
+			Username:       m.Username,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: intPtr(m.MaxOpenReviews),
+		})
+	}
+	if err := r.s.CreateTeam(ctx, team); err != nil {
+		return nil, resolverError(err)
+	}
+	return loadTeam(ctx, team.TeamName)
+}
+
+func (r *resolver) SetUserActive(ctx context.Context, args struct {
+	UserID   gql.ID
+	IsActive bool
+}) (*userResolver, error) {
+	user, err := r.s.SetActive(ctx, string(args.UserID), args.IsActive)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	loadersFrom(ctx).primeUser(ctx, user)
+	return &userResolver{user}, nil
+}
+
+func (r *resolver) SetUserMaxOpenReviews(ctx context.Context, args struct {
+	UserID         gql.ID
+	MaxOpenReviews *int32
+}) (*userResolver, error) {
+	user, err := r.s.SetMaxOpenReviews(ctx, string(args.UserID), intPtr(args.MaxOpenReviews))
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	loadersFrom(ctx).primeUser(ctx, user)
+	return &userResolver{user}, nil
+}
+
+type deactivationResolver struct {
+	userID string
+	result string
+}
+
+func (r *deactivationResolver) UserID() gql.ID { return gql.ID(r.userID) }
+func (r *deactivationResolver) Result() string { return r.result }
+
+func (r *resolver) DeactivateUsers(ctx context.Context, args struct{ UserIDs []gql.ID }) ([]*deactivationResolver, error) {
+	ids := make([]string, 0, len(args.UserIDs))
+	for _, id := range args.UserIDs {
+		ids = append(ids, string(id))
+	}
+	results, err := r.s.DeactivateUsers(ctx, ids)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	// пользователи и их ревью могли измениться
+	l := loadersFrom(ctx)
+	l.users.ClearAll()
+	l.teamMembers.ClearAll()
+	l.prs.ClearAll()
+	l.openReviews.ClearAll()
+
+	rs := make([]*deactivationResolver, 0, len(results))
+	for _, id := range sortedKeys(results) {
+		rs = append(rs, &deactivationResolver{userID: id, result: results[id]})
+	}
+	return rs, nil
+}
+
+func (r *resolver) CreatePullRequest(ctx context.Context, args struct {
+	PullRequestID   gql.ID
+	PullRequestName string
+	AuthorID        gql.ID
+	ChangedFiles    *[]string
+}) (*prResolver, error) {
+	var opts services.CreatePROptions
+	if args.ChangedFiles != nil {
+		opts.ChangedFiles = *args.ChangedFiles
+	}
+	pr, err := r.s.CreatePR(ctx, domain.PullRequest{
+		PullRequestID:   string(args.PullRequestID),
+		PullRequestName: args.PullRequestName,
+		AuthorID:        string(args.AuthorID),
+	}, opts)
+	return r.prResult(ctx, pr, err)
+}
+
+func (r *resolver) MergePullRequest(ctx context.Context, args struct {
+	PullRequestID   gql.ID
+	ExpectedVersion *int32
+}) (*prResolver, error) {
+	pr, err := r.s.MergePR(ctx, string(args.PullRequestID), expectedVersion(args.ExpectedVersion))
+	return r.prResult(ctx, pr, err)
+}
+
+type reassignmentResolver struct {
+	pr         domain.PullRequest
+	replacedBy string
+}
+
+func (r *reassignmentResolver) PullRequest() *prResolver { return &prResolver{r.pr} }
+
+func (r *reassignmentResolver) ReplacedBy(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.replacedBy)
+}
+
+func (r *resolver) ReassignReviewer(ctx context.Context, args struct {
+	PullRequestID   gql.ID
+	OldReviewerID   gql.ID
+	NewReviewerID   *gql.ID
+	ExpectedVersion *int32
+}) (*reassignmentResolver, error) {
+	var (
+		pr         domain.PullRequest
+		replacedBy = derefID(args.NewReviewerID)
+		err        error
+	)
+	if replacedBy != "" {
+		pr, err = r.s.ReassignReviewerTo(ctx, string(args.PullRequestID), string(args.OldReviewerID), replacedBy, expectedVersion(args.ExpectedVersion))
+	} else {
+		pr, replacedBy, err = r.s.ReassignReviewer(ctx, string(args.PullRequestID), string(args.OldReviewerID), expectedVersion(args.ExpectedVersion))
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	loadersFrom(ctx).primePR(ctx, pr)
+	return &reassignmentResolver{pr: pr, replacedBy: replacedBy}, nil
+}
+
+func (r *resolver) AddReviewer(ctx context.Context, args struct {
+	PullRequestID   gql.ID
+	UserID          gql.ID
+	ExpectedVersion *int32
+}) (*prResolver, error) {
+	pr, err := r.s.AddReviewer(ctx, string(args.PullRequestID), string(args.UserID), expectedVersion(args.ExpectedVersion))
+	return r.prResult(ctx, pr, err)
+}
+
+func (r *resolver) RemoveReviewer(ctx context.Context, args struct {
+	PullRequestID   gql.ID
+	UserID          gql.ID
+	ExpectedVersion *int32
+}) (*prResolver, error) {
+	pr, err := r.s.RemoveReviewer(ctx, string(args.PullRequestID), string(args.UserID), expectedVersion(args.ExpectedVersion))
+	return r.prResult(ctx, pr, err)
+}
+
+type reviewResolver struct {
+	r domain.Review
+}
+
+func (r *reviewResolver) PullRequestID() gql.ID { return gql.ID(r.r.PullRequestID) }
+func (r *reviewResolver) Verdict() string       { return string(r.r.Verdict) }
+func (r *reviewResolver) SubmittedAt() gql.Time { return gql.Time{Time: r.r.SubmittedAt} }
+
+func (r *reviewResolver) Reviewer(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.r.UserID)
+}
+
+func (r *resolver) SubmitReview(ctx context.Context, args struct {
+	PullRequestID gql.ID
+	UserID        gql.ID
+	Verdict       string
+}) (*reviewResolver, error) {
+	review, err := r.s.SubmitReview(ctx, string(args.PullRequestID), string(args.UserID), domain.ReviewVerdict(args.Verdict))
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	loadersFrom(ctx).prs.Clear(ctx, review.PullRequestID)
+	return &reviewResolver{review}, nil
+}
+
+func (r *resolver) prResult(ctx context.Context, pr domain.PullRequest, err error) (*prResolver, error) {
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	loadersFrom(ctx).primePR(ctx, pr)
+	return &prResolver{pr}, nil
+}
+
+// expectedVersion: без аргумента версия PR не проверяется.
+func expectedVersion(v *int32) int64 {
+	if v == nil {
+		return 0
+	}
+	return int64(*v)
+}
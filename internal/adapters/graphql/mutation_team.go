@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+func (r *resolver) SetTeamMaxOpenReviews(ctx context.Context, args struct {
+	TeamName       string
+	MaxOpenReviews *int32
+}) (*teamResolver, error) {
+	team, err := r.s.SetTeamMaxOpenReviews(ctx, args.TeamName, intPtr(args.MaxOpenReviews))
+	return r.teamResult(ctx, team, err)
+}
+
+func (r *resolver) SetTeamReviewerLimits(ctx context.Context, args struct {
+	TeamName     string
+	MinReviewers *int32
+	MaxReviewers *int32
+}) (*teamResolver, error) {
+	team, err := r.s.SetTeamReviewerLimits(ctx, args.TeamName, intPtr(args.MinReviewers), intPtr(args.MaxReviewers))
+	return r.teamResult(ctx, team, err)
+}
+
+type reviewSLAInput struct {
+	ReminderAfter string
+	ReassignAfter *string
+}
+
+func (r *resolver) SetTeamReviewSLA(ctx context.Context, args struct {
+	TeamName string
+	SLA      *reviewSLAInput
+}) (*teamResolver, error) {
+	sla, err := parseReviewSLA(args.SLA)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	team, err := r.s.SetTeamReviewSLA(ctx, args.TeamName, sla)
+	return r.teamResult(ctx, team, err)
+}
+
+// parseReviewSLA проверяет длительности так же, как ReviewSLA.UnmarshalJSON в HTTP API.
+func parseReviewSLA(in *reviewSLAInput) (*domain.ReviewSLA, error) {
+	if in == nil {
+		return nil, nil
+	}
+	reminder, err := time.ParseDuration(in.ReminderAfter)
+	if err != nil || reminder <= 0 {
+		return nil, fmt.Errorf("%w: invalid reminderAfter %q", domain.ErrInvalidSLA, in.ReminderAfter)
+	}
+	sla := &domain.ReviewSLA{ReminderAfter: reminder}
+	if reassignAfter := deref(in.ReassignAfter); reassignAfter != "" {
+		reassign, err := time.ParseDuration(reassignAfter)
+		if err != nil || reassign <= 0 {
+			return nil, fmt.Errorf("%w: invalid reassignAfter %q", domain.ErrInvalidSLA, reassignAfter)
+		}
+		sla.ReassignAfter = reassign
+	}
+	return sla, nil
+}
+
+func (r *resolver) SetTeamFallback(ctx context.Context, args struct {
+	TeamName     string
+	PartnerTeams []string
+	Pools        []string
+}) (*teamResolver, error) {
+	team, err := r.s.SetTeamFallback(ctx, args.TeamName, args.PartnerTeams, args.Pools)
+	return r.teamResult(ctx, team, err)
+}
+
+func (r *resolver) teamResult(ctx context.Context, team domain.Team, err error) (*teamResolver, error) {
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	loadersFrom(ctx).teams.Clear(ctx, team.TeamName).Prime(ctx, team.TeamName, team)
+	return &teamResolver{team}, nil
+}
+
+type codeOwnersRuleResolver struct {
+	r domain.CodeOwnersRule
+}
+
+func (r *codeOwnersRuleResolver) Pattern() string  { return r.r.Pattern }
+func (r *codeOwnersRuleResolver) Owners() []string { return nonNil(r.r.Owners) }
+
+func (r *resolver) UploadCodeOwners(ctx context.Context, args struct {
+	TeamName *string
+	Content  string
+}) ([]*codeOwnersRuleResolver, error) {
+	co, err := r.s.UploadCodeOwners(ctx, domain.CodeOwnersFile{TeamName: deref(args.TeamName), Content: args.Content})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	rs := make([]*codeOwnersRuleResolver, 0, len(co.Rules))
+	for _, rule := range co.Rules {
+		rs = append(rs, &codeOwnersRuleResolver{rule})
+	}
+	return rs, nil
+}
+
+type unavailabilityResolver struct {
+	u domain.Unavailability
+}
+
+func (r *unavailabilityResolver) ID() gql.ID         { return gql.ID(strconv.FormatInt(r.u.ID, 10)) }
+func (r *unavailabilityResolver) UserID() gql.ID     { return gql.ID(r.u.UserID) }
+func (r *unavailabilityResolver) Kind() string       { return string(r.u.Kind) }
+func (r *unavailabilityResolver) StartsAt() gql.Time { return gql.Time{Time: r.u.StartsAt} }
+func (r *unavailabilityResolver) EndsAt() gql.Time   { return gql.Time{Time: r.u.EndsAt} }
+func (r *unavailabilityResolver) Reason() *string    { return optional(r.u.Reason) }
+
+func (r *unavailabilityResolver) User(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.u.UserID)
+}
+
+func (r *resolver) AddUnavailability(ctx context.Context, args struct {
+	UserID   gql.ID
+	Kind     *string
+	StartsAt gql.Time
+	EndsAt   gql.Time
+	Reason   *string
+}) (*unavailabilityResolver, error) {
+	u, err := r.s.AddUnavailability(ctx, domain.Unavailability{
+		UserID:   string(args.UserID),
+		Kind:     domain.UnavailabilityKind(deref(args.Kind)),
+		StartsAt: args.StartsAt.Time,
+		EndsAt:   args.EndsAt.Time,
+		Reason:   deref(args.Reason),
+	})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &unavailabilityResolver{u}, nil
+}
+
+func (r *resolver) RemoveUnavailability(ctx context.Context, args struct{ ID gql.ID }) (bool, error) {
+	id, err := strconv.ParseInt(string(args.ID), 10, 64)
+	if err != nil {
+		return false, resolverError(domain.ErrNotFound)
+	}
+	if err := r.s.DeleteUnavailability(ctx, id); err != nil {
+		return false, resolverError(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,191 @@
+package graphql
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/services"
+	"context"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// resolver — корень схемы: запросы и мутации. Одиночные объекты и вложенные поля читаются
+// через даталоадеры, списки и статистика — через сервис, с его лимитами и проверками.
+type resolver struct {
+	s *services.Service
+}
+
+func (r *resolver) Team(ctx context.Context, args struct{ TeamName string }) (*teamResolver, error) {
+	return loadTeam(ctx, args.TeamName)
+}
+
+func (r *resolver) Teams(ctx context.Context, args struct {
+	First *int32
+	After *string
+}) (*teamPageResolver, error) {
+	filter := domain.TeamListFilter{Limit: int(derefInt(args.First))}
+	if after := deref(args.After); after != "" {
+		name, err := domain.DecodeTeamCursor(after)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		filter.AfterTeamName = name
+	}
+
+	page, err := r.s.ListTeams(ctx, filter)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	l := loadersFrom(ctx)
+	for _, t := range page.Teams {
+		l.teams.Prime(ctx, t.TeamName, t)
+	}
+	return &teamPageResolver{page}, nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ UserID gql.ID }) (*userResolver, error) {
+	return loadUser(ctx, string(args.UserID))
+}
+
+func (r *resolver) Users(ctx context.Context, args struct {
+	TeamName       *string
+	UsernamePrefix *string
+	IsActive       *bool
+	First          *int32
+	After          *string
+}) (*userPageResolver, error) {
+	filter := domain.UserListFilter{
+		TeamName:       deref(args.TeamName),
+		UsernamePrefix: deref(args.UsernamePrefix),
+		IsActive:       args.IsActive,
+		Limit:          int(derefInt(args.First)),
+	}
+	if after := deref(args.After); after != "" {
+		id, err := domain.DecodeUserCursor(after)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		filter.AfterUserID = id
+	}
+
+	page, err := r.s.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &userPageResolver{page}, nil
+}
+
+func (r *resolver) PullRequest(ctx context.Context, args struct{ PullRequestID gql.ID }) (*prResolver, error) {
+	return loadPR(ctx, string(args.PullRequestID))
+}
+
+func (r *resolver) PullRequests(ctx context.Context, args struct {
+	AuthorID   *gql.ID
+	ReviewerID *gql.ID
+	TeamName   *string
+	Status     *string
+	First      *int32
+	After      *string
+}) (*prPageResolver, error) {
+	// порядок тот же, что у GET /pullRequest/list по умолчанию: новые сначала
+	filter := domain.PRListFilter{
+		AuthorID:   derefID(args.AuthorID),
+		ReviewerID: derefID(args.ReviewerID),
+		TeamName:   deref(args.TeamName),
+		Status:     domain.PullRequestStatus(deref(args.Status)),
+		Desc:       true,
+		Limit:      int(derefInt(args.First)),
+	}
+	if after := deref(args.After); after != "" {
+		cursor, err := domain.DecodePRCursor(after)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := r.s.ListPRs(ctx, filter)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &prPageResolver{page}, nil
+}
+
+func (r *resolver) Stats(ctx context.Context, args struct {
+	TeamName *string
+	Status   *string
+	From     *gql.Time
+	To       *gql.Time
+}) (*statsResolver, error) {
+	filter := domain.StatsFilter{
+		TeamName: deref(args.TeamName),
+		Status:   domain.PullRequestStatus(deref(args.Status)),
+	}
+	if args.From != nil {
+		filter.From = &args.From.Time
+	}
+	if args.To != nil {
+		filter.To = &args.To.Time
+	}
+
+	stats, err := r.s.GetStats(ctx, filter)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &statsResolver{stats}, nil
+}
+
+type teamPageResolver struct {
+	p domain.TeamPage
+}
+
+func (r *teamPageResolver) Teams() []*teamResolver {
+	rs := make([]*teamResolver, 0, len(r.p.Teams))
+	for _, t := range r.p.Teams {
+		rs = append(rs, &teamResolver{t})
+	}
+	return rs
+}
+
+func (r *teamPageResolver) NextCursor() *string { return optional(r.p.NextCursor) }
+
+type userPageResolver struct {
+	p domain.UserPage
+}
+
+func (r *userPageResolver) Users() []*userResolver { return userResolvers(r.p.Users) }
+func (r *userPageResolver) NextCursor() *string    { return optional(r.p.NextCursor) }
+
+type prPageResolver struct {
+	p domain.PRPage
+}
+
+func (r *prPageResolver) PullRequests() []*prResolver { return prResolvers(r.p.PullRequests) }
+func (r *prPageResolver) NextCursor() *string         { return optional(r.p.NextCursor) }
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefID(id *gql.ID) string {
+	if id == nil {
+		return ""
+	}
+	return string(*id)
+}
+
+func derefInt(n *int32) int32 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,314 @@
+package graphql
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+type teamResolver struct {
+	t domain.Team
+}
+
+func (r *teamResolver) TeamName() string { return r.t.TeamName }
+
+func (r *teamResolver) Members(ctx context.Context) ([]*userResolver, error) {
+	members, err := loadersFrom(ctx).teamMembers.Load(ctx, r.t.TeamName)()
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return userResolvers(members), nil
+}
+
+func (r *teamResolver) DefaultMaxOpenReviews() *int32 { return int32Ptr(r.t.DefaultMaxOpenReviews) }
+func (r *teamResolver) MinReviewers() *int32          { return int32Ptr(r.t.MinReviewers) }
+func (r *teamResolver) MaxReviewers() *int32          { return int32Ptr(r.t.MaxReviewers) }
+func (r *teamResolver) PartnerTeams() []string        { return nonNil(r.t.PartnerTeams) }
+func (r *teamResolver) FallbackPools() []string       { return nonNil(r.t.FallbackPools) }
+
+func (r *teamResolver) ReviewSLA() *reviewSLAResolver {
+	if r.t.ReviewSLA == nil {
+		return nil
+	}
+	return &reviewSLAResolver{*r.t.ReviewSLA}
+}
+
+type reviewSLAResolver struct {
+	s domain.ReviewSLA
+}
+
+func (r *reviewSLAResolver) ReminderAfter() string { return r.s.ReminderAfter.String() }
+
+func (r *reviewSLAResolver) ReassignAfter() *string {
+	if r.s.ReassignAfter <= 0 {
+		return nil
+	}
+	return optional(r.s.ReassignAfter.String())
+}
+
+type userResolver struct {
+	u domain.User
+}
+
+func userResolvers(users []domain.User) []*userResolver {
+	rs := make([]*userResolver, 0, len(users))
+	for _, u := range users {
+		rs = append(rs, &userResolver{u})
+	}
+	return rs
+}
+
+func (r *userResolver) UserID() gql.ID         { return gql.ID(r.u.UserID) }
+func (r *userResolver) Username() string       { return r.u.Username }
+func (r *userResolver) TeamName() string       { return r.u.TeamName }
+func (r *userResolver) IsActive() bool         { return r.u.IsActive }
+func (r *userResolver) MaxOpenReviews() *int32 { return int32Ptr(r.u.MaxOpenReviews) }
+
+func (r *userResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return loadTeam(ctx, r.u.TeamName)
+}
+
+func (r *userResolver) OpenReviews(ctx context.Context) ([]*prResolver, error) {
+	prs, err := loadersFrom(ctx).openReviews.Load(ctx, r.u.UserID)()
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return prResolvers(prs), nil
+}
+
+type prResolver struct {
+	pr domain.PullRequest
+}
+
+func prResolvers(prs []domain.PullRequest) []*prResolver {
+	rs := make([]*prResolver, 0, len(prs))
+	for _, pr := range prs {
+		rs = append(rs, &prResolver{pr})
+	}
+	return rs
+}
+
+func (r *prResolver) PullRequestID() gql.ID   { return gql.ID(r.pr.PullRequestID) }
+func (r *prResolver) PullRequestName() string { return r.pr.PullRequestName }
+func (r *prResolver) AuthorID() gql.ID        { return gql.ID(r.pr.AuthorID) }
+func (r *prResolver) Status() string          { return string(r.pr.Status) }
+func (r *prResolver) CreatedAt() *gql.Time    { return timePtr(r.pr.CreatedAt) }
+func (r *prResolver) MergedAt() *gql.Time     { return timePtr(r.pr.MergedAt) }
+func (r *prResolver) Version() int32          { return int32(r.pr.Version) }
+
+func (r *prResolver) AuthorTeam() *string {
+	if r.pr.AuthorTeam == "" {
+		return nil
+	}
+	return &r.pr.AuthorTeam
+}
+
+func (r *prResolver) Author(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.pr.AuthorID)
+}
+
+func (r *prResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	users, errs := loadersFrom(ctx).users.LoadMany(ctx, r.pr.AssignedReviewers)()
+	reviewers := make([]*userResolver, 0, len(users))
+	for i, u := range users {
+		if i < len(errs) && errs[i] != nil {
+			if errors.Is(errs[i], domain.ErrNotFound) {
+				continue
+			}
+			return nil, resolverError(errs[i])
+		}
+		reviewers = append(reviewers, &userResolver{u})
+	}
+	return reviewers, nil
+}
+
+func (r *prResolver) Assignments() []*assignmentResolver {
+	rs := make([]*assignmentResolver, 0, len(r.pr.Assignments))
+	for _, a := range r.pr.Assignments {
+		rs = append(rs, &assignmentResolver{a})
+	}
+	return rs
+}
+
+type assignmentResolver struct {
+	a domain.ReviewerAssignment
+}
+
+func (r *assignmentResolver) UserID() gql.ID        { return gql.ID(r.a.UserID) }
+func (r *assignmentResolver) Source() string        { return string(r.a.Source) }
+func (r *assignmentResolver) AssignedAt() *gql.Time { return timePtr(r.a.AssignedAt) }
+func (r *assignmentResolver) ReviewedAt() *gql.Time { return timePtr(r.a.ReviewedAt) }
+
+func (r *assignmentResolver) SourceName() *string {
+	if r.a.SourceName == "" {
+		return nil
+	}
+	return &r.a.SourceName
+}
+
+func (r *assignmentResolver) Verdict() *string {
+	if r.a.Verdict == nil {
+		return nil
+	}
+	v := string(*r.a.Verdict)
+	return &v
+}
+
+func (r *assignmentResolver) Reviewer(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.a.UserID)
+}
+
+type statsResolver struct {
+	s domain.Stats
+}
+
+func (r *statsResolver) ReviewerAssignments() []*reviewerCountResolver {
+	return reviewerCounts(r.s.ReviewerAssignments)
+}
+
+func (r *statsResolver) ReviewerOpenAssignments() []*reviewerCountResolver {
+	return reviewerCounts(r.s.ReviewerOpenAssignments)
+}
+
+func (r *statsResolver) PrAssignments() []*prCountResolver {
+	rs := make([]*prCountResolver, 0, len(r.s.PRAssignments))
+	for _, id := range sortedKeys(r.s.PRAssignments) {
+		rs = append(rs, &prCountResolver{id: id, count: r.s.PRAssignments[id]})
+	}
+	return rs
+}
+
+func (r *statsResolver) Teams() []*teamStatsResolver {
+	rs := make([]*teamStatsResolver, 0, len(r.s.Teams))
+	for _, name := range sortedKeys(r.s.Teams) {
+		rs = append(rs, &teamStatsResolver{name: name, s: r.s.Teams[name]})
+	}
+	return rs
+}
+
+type reviewerCountResolver struct {
+	id    string
+	count int
+}
+
+func reviewerCounts(counts map[string]int) []*reviewerCountResolver {
+	rs := make([]*reviewerCountResolver, 0, len(counts))
+	for _, id := range sortedKeys(counts) {
+		rs = append(rs, &reviewerCountResolver{id: id, count: counts[id]})
+	}
+	return rs
+}
+
+func (r *reviewerCountResolver) UserID() gql.ID { return gql.ID(r.id) }
+func (r *reviewerCountResolver) Count() int32   { return int32(r.count) }
+
+func (r *reviewerCountResolver) User(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, r.id)
+}
+
+type prCountResolver struct {
+	id    string
+	count int
+}
+
+func (r *prCountResolver) PullRequestID() gql.ID { return gql.ID(r.id) }
+func (r *prCountResolver) Count() int32          { return int32(r.count) }
+
+func (r *prCountResolver) PullRequest(ctx context.Context) (*prResolver, error) {
+	return loadPR(ctx, r.id)
+}
+
+type teamStatsResolver struct {
+	name string
+	s    domain.TeamStats
+}
+
+func (r *teamStatsResolver) TeamName() string          { return r.name }
+func (r *teamStatsResolver) PullRequests() int32       { return int32(r.s.PullRequests) }
+func (r *teamStatsResolver) OpenPullRequests() int32   { return int32(r.s.OpenPullRequests) }
+func (r *teamStatsResolver) MergedPullRequests() int32 { return int32(r.s.MergedPullRequests) }
+func (r *teamStatsResolver) Assignments() int32        { return int32(r.s.Assignments) }
+func (r *teamStatsResolver) OpenAssignments() int32    { return int32(r.s.OpenAssignments) }
+func (r *teamStatsResolver) Reviewers() int32          { return int32(r.s.Reviewers) }
+
+func (r *teamStatsResolver) Team(ctx context.Context) (*teamResolver, error) {
+	return loadTeam(ctx, r.name)
+}
+
+// loadUser, loadTeam и loadPR возвращают nil без ошибки для отсутствующих объектов:
+// в схеме такие поля допускают null.
+func loadUser(ctx context.Context, id string) (*userResolver, error) {
+	u, err := loadersFrom(ctx).users.Load(ctx, id)()
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, resolverError(err)
+	}
+	return &userResolver{u}, nil
+}
+
+func loadTeam(ctx context.Context, name string) (*teamResolver, error) {
+	t, err := loadersFrom(ctx).teams.Load(ctx, name)()
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, resolverError(err)
+	}
+	return &teamResolver{t}, nil
+}
+
+func loadPR(ctx context.Context, id string) (*prResolver, error) {
+	pr, err := loadersFrom(ctx).prs.Load(ctx, id)()
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, resolverError(err)
+	}
+	return &prResolver{pr}, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+func timePtr(t *time.Time) *gql.Time {
+	if t == nil {
+		return nil
+	}
+	return &gql.Time{Time: *t}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"PRService/internal/domain"
+	pb "PRService/pkg/pb/prservice/v1"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func teamFromProto(t *pb.Team) domain.Team {
+	team := domain.Team{TeamName: t.GetTeamName()}
+	for _, m := range t.GetMembers() {
+		team.Members = append(team.Members, domain.TeamMember{
+			UserID:         m.GetUserId(),
+			Username:       m.GetUsername(),
+			IsActive:       m.GetIsActive(),
+			MaxOpenReviews: intFromProto(m.MaxOpenReviews),
+		})
+	}
+	return team
+}
+
+func teamToProto(team domain.Team) *pb.Team {
+	t := &pb.Team{TeamName: team.TeamName}
+	for _, m := range team.Members {
+		t.Members = append(t.Members, &pb.TeamMember{
+			UserId:         m.UserID,
+			Username:       m.Username,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: intToProto(m.MaxOpenReviews),
+			IsAvailable:    m.IsAvailable,
+		})
+	}
+	return t
+}
+
+func userToProto(u domain.User) *pb.User {
+	return &pb.User{
+		UserId:         u.UserID,
+		Username:       u.Username,
+		TeamName:       u.TeamName,
+		IsActive:       u.IsActive,
+		MaxOpenReviews: intToProto(u.MaxOpenReviews),
+	}
+}
+
+func prToProto(pr domain.PullRequest) *pb.PullRequest {
+	p := &pb.PullRequest{
+		PullRequestId:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorID,
+		AuthorTeam:        pr.AuthorTeam,
+		Status:            statusToProto(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         timeToProto(pr.CreatedAt),
+		MergedAt:          timeToProto(pr.MergedAt),
+		Version:           pr.Version,
+	}
+	for _, a := range pr.Assignments {
+		p.Assignments = append(p.Assignments, &pb.ReviewerAssignment{
+			UserId:     a.UserID,
+			Source:     string(a.Source),
+			SourceName: a.SourceName,
+			AssignedAt: timeToProto(a.AssignedAt),
+		})
+	}
+	return p
+}
+
+func statusToProto(s domain.PullRequestStatus) pb.PullRequestStatus {
+	switch s {
+	case domain.StatusOpen:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case domain.StatusMerged:
+		return pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	}
+	return pb.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+// statusFromProto возвращает пустой статус для UNSPECIFIED — фильтр по статусу не нужен.
+func statusFromProto(s pb.PullRequestStatus) domain.PullRequestStatus {
+	switch s {
+	case pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN:
+		return domain.StatusOpen
+	case pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED:
+		return domain.StatusMerged
+	}
+	return ""
+}
+
+func statsToProto(stats domain.Stats) *pb.Stats {
+	s := &pb.Stats{
+		ReviewerAssignments:     countsToProto(stats.ReviewerAssignments),
+		ReviewerOpenAssignments: countsToProto(stats.ReviewerOpenAssignments),
+		PrAssignments:           countsToProto(stats.PRAssignments),
+		Teams:                   make(map[string]*pb.TeamStats, len(stats.Teams)),
+	}
+	for name, t := range stats.Teams {
+		s.Teams[name] = &pb.TeamStats{
+			PullRequests:       int32(t.PullRequests),
+			OpenPullRequests:   int32(t.OpenPullRequests),
+			MergedPullRequests: int32(t.MergedPullRequests),
+			Assignments:        int32(t.Assignments),
+			OpenAssignments:    int32(t.OpenAssignments),
+			Reviewers:          int32(t.Reviewers),
+		}
+	}
+	return s
+}
+
+func countsToProto(counts map[string]int) map[string]int32 {
+	m := make(map[string]int32, len(counts))
+	for k, v := range counts {
+		m[k] = int32(v)
+	}
+	return m
+}
+
+func eventToProto(e domain.Event) *pb.AssignmentEvent {
+	t := pb.AssignmentEventType_ASSIGNMENT_EVENT_TYPE_UNSPECIFIED
+	switch e.Type {
+	case domain.EventReviewerAssigned:
+		t = pb.AssignmentEventType_ASSIGNMENT_EVENT_TYPE_ASSIGNED
+	case domain.EventReviewerReassigned:
+		t = pb.AssignmentEventType_ASSIGNMENT_EVENT_TYPE_REASSIGNED
+	case domain.EventReviewerRemoved:
+		t = pb.AssignmentEventType_ASSIGNMENT_EVENT_TYPE_REMOVED
+	}
+	return &pb.AssignmentEvent{
+		Type:          t,
+		PullRequestId: e.PullRequestID,
+		UserId:        e.UserID,
+		TeamName:      e.TeamName,
+		ReplacedBy:    e.ReplacedBy,
+		At:            timestamppb.New(e.At),
+	}
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func intToProto(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
+
+func intFromProto(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"PRService/internal/domain"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError переводит ошибку сервиса в статус gRPC. Сообщение — код ошибки,
+// как в теле ответов HTTP API.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrTeamNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrTeamExists), errors.Is(err, domain.ErrUserExists), errors.Is(err, domain.ErrPrExists),
+		errors.Is(err, domain.ErrAlreadyAssigned):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrPrMerged), errors.Is(err, domain.ErrNotAssigned), errors.Is(err, domain.ErrNoCandidate),
+		errors.Is(err, domain.ErrNotTeamMember), errors.Is(err, domain.ErrReviewerIsAuthor), errors.Is(err, domain.ErrUserInactive),
+		errors.Is(err, domain.ErrReviewerLimit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrVersionMismatch), errors.Is(err, domain.ErrConcurrentUpdate):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrInvalidPRFilter), errors.Is(err, domain.ErrInvalidStatsFilter),
+		errors.Is(err, domain.ErrInvalidSchedule), errors.Is(err, domain.ErrInvalidLimits), errors.Is(err, domain.ErrInvalidSLA),
+		errors.Is(err, domain.ErrInvalidUser), errors.Is(err, domain.ErrInvalidCodeOwners):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	log.Printf("grpc: internal error: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
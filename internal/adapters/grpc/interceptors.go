@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnary превращает панику обработчика в Internal, как middleware.Recoverer в HTTP API:
+// без него паника в одном RPC роняет весь процесс.
+func RecoveryUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStream — то же для стримов.
+func RecoveryStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(method string, p any) error {
+	log.Printf("panic in %s: %v\n%s", method, p, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
+
+// RateLimitUnary применяет к gRPC те же лимиты, что и HTTP API. Корзины общие:
+// ключи совпадают с HTTP, поэтому клиент не получает двойной лимит, переключившись на gRPC.
+// Ошибки хранилища не блокируют вызовы.
+func RateLimitUnary(store ports.RateLimitStore, perToken, perIP domain.RateLimit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := takeRateLimit(ctx, store, perToken, perIP); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStream списывает из корзины открытие стрима, а не отдельные сообщения.
+func RateLimitStream(store ports.RateLimitStore, perToken, perIP domain.RateLimit) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := takeRateLimit(ss.Context(), store, perToken, perIP); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func takeRateLimit(ctx context.Context, store ports.RateLimitStore, perToken, perIP domain.RateLimit) error {
+	now := time.Now()
+
+	buckets := make([]rateLimitBucket, 0, 2)
+	if perIP.Enabled() {
+		buckets = append(buckets, rateLimitBucket{key: "ip:" + peerIP(ctx), limit: perIP})
+	}
+	if token := bearerToken(ctx); token != "" && perToken.Enabled() {
+		sum := sha256.Sum256([]byte(token))
+		buckets = append(buckets, rateLimitBucket{key: "token:" + hex.EncodeToString(sum[:]), limit: perToken})
+	}
+
+	for _, b := range buckets {
+		allowed, retryAfter, err := store.TakeRateLimitToken(ctx, b.key, b.limit, now)
+		if err != nil {
+			log.Printf("error checking rate limit: %v", err)
+			continue
+		}
+		if !allowed {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+			return status.Error(codes.ResourceExhausted, "RATE_LIMITED")
+		}
+	}
+	return nil
+}
+
+type rateLimitBucket struct {
+	key   string
+	limit domain.RateLimit
+}
+
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+			return strings.TrimSpace(auth[7:])
+		}
+	}
+	return ""
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,225 @@
+package grpc
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"PRService/internal/services"
+	pb "PRService/pkg/pb/prservice/v1"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server реализует gRPC API поверх того же services.Service, что и HTTP-обработчики.
+type Server struct {
+	pb.UnimplementedPRServiceServer
+
+	S *services.Service
+	// Events — источник событий для WatchAssignmentEvents.
+	Events ports.EventSubscriber
+}
+
+func (s *Server) CreateTeam(ctx context.Context, req *pb.CreateTeamRequest) (*pb.Team, error) {
+	if req.GetTeam().GetTeamName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "team_name required")
+	}
+	team := teamFromProto(req.GetTeam())
+	if err := s.S.CreateTeam(ctx, team); err != nil {
+		return nil, statusError(err)
+	}
+	return teamToProto(team), nil
+}
+
+func (s *Server) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.Team, error) {
+	if req.GetTeamName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "team_name required")
+	}
+	team, err := s.S.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return teamToProto(team), nil
+}
+
+func (s *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+	user, err := s.S.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return userToProto(user), nil
+}
+
+func (s *Server) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	filter := domain.UserListFilter{
+		TeamName:       req.GetTeamName(),
+		UsernamePrefix: req.GetUsernamePrefix(),
+		IsActive:       req.IsActive,
+		Limit:          int(req.GetLimit()),
+	}
+	if req.GetCursor() != "" {
+		after, err := domain.DecodeUserCursor(req.GetCursor())
+		if err != nil {
+			return nil, statusError(err)
+		}
+		filter.AfterUserID = after
+	}
+
+	page, err := s.S.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.ListUsersResponse{NextCursor: page.NextCursor}
+	for _, u := range page.Users {
+		resp.Users = append(resp.Users, userToProto(u))
+	}
+	return resp, nil
+}
+
+func (s *Server) SetUserActive(ctx context.Context, req *pb.SetUserActiveRequest) (*pb.User, error) {
+	user, err := s.S.SetActive(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return userToProto(user), nil
+}
+
+func (s *Server) DeactivateUsers(ctx context.Context, req *pb.DeactivateUsersRequest) (*pb.DeactivateUsersResponse, error) {
+	if len(req.GetUserIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_ids required")
+	}
+	results, err := s.S.DeactivateUsers(ctx, req.GetUserIds())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.DeactivateUsersResponse{Results: results}, nil
+}
+
+func (s *Server) CreatePullRequest(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.PullRequest, error) {
+	pr, err := s.S.CreatePR(ctx, domain.PullRequest{
+		PullRequestID:   req.GetPullRequestId(),
+		PullRequestName: req.GetPullRequestName(),
+		AuthorID:        req.GetAuthorId(),
+	}, services.CreatePROptions{ChangedFiles: req.GetChangedFiles()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return prToProto(pr), nil
+}
+
+func (s *Server) GetPullRequest(ctx context.Context, req *pb.GetPullRequestRequest) (*pb.PullRequest, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "pull_request_id required")
+	}
+	pr, err := s.S.GetPR(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return prToProto(pr), nil
+}
+
+func (s *Server) ListPullRequests(ctx context.Context, req *pb.ListPullRequestsRequest) (*pb.ListPullRequestsResponse, error) {
+	// порядок по умолчанию тот же, что у GET /pullRequest/list: новые сначала
+	filter := domain.PRListFilter{
+		AuthorID:   req.GetAuthorId(),
+		ReviewerID: req.GetReviewerId(),
+		TeamName:   req.GetTeamName(),
+		Status:     statusFromProto(req.GetStatus()),
+		Desc:       true,
+		Limit:      int(req.GetLimit()),
+	}
+	if req.GetCursor() != "" {
+		cursor, err := domain.DecodePRCursor(req.GetCursor())
+		if err != nil {
+			return nil, statusError(err)
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := s.S.ListPRs(ctx, filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &pb.ListPullRequestsResponse{NextCursor: page.NextCursor}
+	for _, pr := range page.PullRequests {
+		resp.PullRequests = append(resp.PullRequests, prToProto(pr))
+	}
+	return resp, nil
+}
+
+func (s *Server) MergePullRequest(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.PullRequest, error) {
+	pr, err := s.S.MergePR(ctx, req.GetPullRequestId(), req.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return prToProto(pr), nil
+}
+
+func (s *Server) ReassignReviewer(ctx context.Context, req *pb.ReassignReviewerRequest) (*pb.ReassignReviewerResponse, error) {
+	if req.GetNewReviewerId() != "" {
+		pr, err := s.S.ReassignReviewerTo(ctx, req.GetPullRequestId(), req.GetOldReviewerId(), req.GetNewReviewerId(), req.GetExpectedVersion())
+		if err != nil {
+			return nil, statusError(err)
+		}
+		return &pb.ReassignReviewerResponse{PullRequest: prToProto(pr), ReplacedBy: req.GetNewReviewerId()}, nil
+	}
+
+	pr, replacedBy, err := s.S.ReassignReviewer(ctx, req.GetPullRequestId(), req.GetOldReviewerId(), req.GetExpectedVersion())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ReassignReviewerResponse{PullRequest: prToProto(pr), ReplacedBy: replacedBy}, nil
+}
+
+func (s *Server) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.Stats, error) {
+	filter := domain.StatsFilter{
+		TeamName: req.GetTeamName(),
+		Status:   statusFromProto(req.GetStatus()),
+		From:     timeFromProto(req.GetFrom()),
+		To:       timeFromProto(req.GetTo()),
+	}
+	stats, err := s.S.GetStats(ctx, filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return statsToProto(stats), nil
+}
+
+func (s *Server) WatchAssignmentEvents(req *pb.WatchAssignmentEventsRequest, stream pb.PRService_WatchAssignmentEventsServer) error {
+	if s.Events == nil {
+		return status.Error(codes.Unimplemented, "assignment events are not available")
+	}
+
+	ctx := stream.Context()
+	events := s.Events.Subscribe(ctx)
+	// заголовки сообщают клиенту, что подписка оформлена и события не потеряются
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if !event.Type.IsAssignment() {
+				continue
+			}
+			if req.GetTeamName() != "" && event.TeamName != req.GetTeamName() {
+				continue
+			}
+			if req.GetUserId() != "" && event.UserID != req.GetUserId() && event.ReplacedBy != req.GetUserId() {
+				continue
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"PRService/internal/domain"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// AdminOnly пропускает только запросы с заголовком Authorization: Bearer <token>.
+// Пустой token отключает административные эндпоинты.
+func AdminOnly(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				http.NotFound(w, r)
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(token)) != 1 {
+				http.Error(w, "UNAUTHORIZED", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ExportData отдаёт все данные сервиса потоком NDJSON.
+func (h *Handler) ExportData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="prservice-export.ndjson"`)
+	if err := h.S.Export(r.Context(), w); err != nil {
+		// заголовки уже отправлены, клиент увидит оборванный поток
+		log.Printf("error exporting data: %v", err)
+	}
+}
+
+// ImportData загружает выгрузку ExportData; on_conflict — skip (по умолчанию), overwrite или fail.
+func (h *Handler) ImportData(w http.ResponseWriter, r *http.Request) {
+	policy := domain.ConflictPolicy(r.URL.Query().Get("on_conflict"))
+	if policy == "" {
+		policy = domain.ConflictSkip
+	}
+	if !policy.Valid() {
+		http.Error(w, "unknown on_conflict", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.S.Import(r.Context(), r.Body, policy)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			http.Error(w, "REQUEST_TOO_LARGE", http.StatusRequestEntityTooLarge)
+		case errors.Is(err, domain.ErrInvalidExport):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrImportConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			log.Printf("error importing data: %v", err)
+		}
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]domain.ImportStats{"stats": stats})
+	if err != nil {
+		log.Printf("error encoding import stats: %v", err)
+	}
+}
+
+func (h *Handler) GetMigrationStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.Migrations.MigrationStatus(r.Context())
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error getting migration status: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(status)
+	if err != nil {
+		log.Printf("error encoding migration status: %v", err)
+	}
+}
+
+// MigrateUp применяет недостающие миграции и возвращает новое состояние схемы.
+func (h *Handler) MigrateUp(w http.ResponseWriter, r *http.Request) {
+	if err := h.Migrations.Migrate(); err != nil {
+		http.Error(w, "MIGRATION_FAILED", http.StatusInternalServerError)
+		log.Printf("error applying migrations: %v", err)
+		return
+	}
+	h.GetMigrationStatus(w, r)
+}
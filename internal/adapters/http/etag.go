@@ -0,0 +1,39 @@
+package http
+
+import (
+	"PRService/internal/domain"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// prETag — сильный ETag PR, построенный из его версии.
+func prETag(pr domain.PullRequest) string {
+	return `"` + strconv.FormatInt(pr.Version, 10) + `"`
+}
+
+func setPRETag(w http.ResponseWriter, pr domain.PullRequest) {
+	w.Header().Set("ETag", prETag(pr))
+}
+
+// ifMatchVersion разбирает If-Match в ожидаемую версию PR. Без заголовка или с "*"
+// возвращает 0 — изменение выполняется без проверки. ok=false означает, что заголовок
+// не может совпасть ни с одной версией (слабый или некорректный тег, список тегов).
+func ifMatchVersion(r *http.Request) (version int64, ok bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return 0, true
+	}
+	if len(v) < 3 || v[0] != '"' || v[len(v)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(v[1:len(v)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+func writePreconditionFailed(w http.ResponseWriter) {
+	http.Error(w, "PR_VERSION_MISMATCH", http.StatusPreconditionFailed)
+}
@@ -2,23 +2,47 @@ package http
 
 import (
 	"PRService/internal/domain"
+	"PRService/internal/ports"
 	"PRService/internal/services"
 	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	_ "github.com/go-chi/chi/v5"
 )
 
 type Handler struct {
 	S *services.Service
+	// Migrations нужен только административным эндпоинтам миграций.
+	Migrations ports.SchemaMigrator
+}
+
+// decodeJSON читает тело запроса в v; при ошибке отвечает клиенту и возвращает false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeDecodeError(w, err)
+		return false
+	}
+	return true
+}
+
+// writeDecodeError отвечает 413, если тело оборвано MaxBodySize, и 400 в остальных случаях.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "REQUEST_TOO_LARGE", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "bad request", http.StatusBadRequest)
 }
 
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var team domain.Team
-	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+	if !decodeJSON(w, r, &team) {
 		return
 	}
 
@@ -27,6 +51,14 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "TEAM_EXISTS", http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidSchedule) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLimits) {
+			http.Error(w, "INVALID_REVIEWER_LIMITS", http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
@@ -61,183 +93,1075 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetTeamFallback(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserID   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		TeamName     string   `json:"team_name"`
+		PartnerTeams []string `json:"partner_teams"`
+		Pools        []string `json:"pools"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	user, err := h.S.SetActive(r.Context(), req.UserID, req.IsActive)
+	if slices.Contains(req.PartnerTeams, req.TeamName) {
+		http.Error(w, "team cannot be its own partner", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.S.SetTeamFallback(r.Context(), req.TeamName, req.PartnerTeams, req.Pools)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
+			http.Error(w, "TEAM_OR_POOL_NOT_FOUND", http.StatusNotFound)
 			return
 		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error setting team fallback: %v", err)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(map[string]domain.User{"user": user})
+	err = json.NewEncoder(w).Encode(map[string]domain.Team{"team": team})
 	if err != nil {
-		log.Printf("error encoding user: %v", err)
+		log.Printf("failed to encode response: %v", err)
 	}
 }
 
-func (h *Handler) GetUserPRs(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		http.Error(w, "user_id required", http.StatusBadRequest)
+func (h *Handler) SetTeamMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName              string `json:"team_name"`
+		DefaultMaxOpenReviews *int   `json:"default_max_open_reviews"`
+	}
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	prs, err := h.S.GetPRsForReviewer(r.Context(), userID)
+	if req.DefaultMaxOpenReviews != nil && *req.DefaultMaxOpenReviews < 0 {
+		http.Error(w, "default_max_open_reviews must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.S.SetTeamMaxOpenReviews(r.Context(), req.TeamName, req.DefaultMaxOpenReviews)
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
-		log.Printf("error getting PRs for reviewer: %v", err)
 		return
 	}
 
-	if prs == nil {
-		http.Error(w, "PR_NOT_FOUND", http.StatusNotFound)
+	err = json.NewEncoder(w).Encode(map[string]domain.Team{"team": team})
+	if err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+func (h *Handler) SetTeamReviewerLimits(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName     string `json:"team_name"`
+		MinReviewers *int   `json:"min_reviewers"`
+		MaxReviewers *int   `json:"max_reviewers"`
+	}
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	resp := map[string]interface{}{
-		"user_id":       userID,
-		"pull_requests": prs,
+	team, err := h.S.SetTeamReviewerLimits(r.Context(), req.TeamName, req.MinReviewers, req.MaxReviewers)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLimits) {
+			http.Error(w, "INVALID_REVIEWER_LIMITS", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
 	}
-	err = json.NewEncoder(w).Encode(resp)
+
+	err = json.NewEncoder(w).Encode(map[string]domain.Team{"team": team})
 	if err != nil {
-		log.Printf("error writing response: %v", err)
+		log.Printf("failed to encode response: %v", err)
 	}
 }
 
-func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetTeamReviewSLA(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorID        string `json:"author_id"`
+		TeamName  string            `json:"team_name"`
+		ReviewSLA *domain.ReviewSLA `json:"review_sla"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+		if errors.Is(err, domain.ErrInvalidSLA) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeDecodeError(w, err)
 		return
 	}
 
-	pr := domain.PullRequest{
-		PullRequestID:   req.PullRequestID,
-		PullRequestName: req.PullRequestName,
-		AuthorID:        req.AuthorID,
+	team, err := h.S.SetTeamReviewSLA(r.Context(), req.TeamName, req.ReviewSLA)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSLA) {
+			http.Error(w, "reassign_after must be greater than reminder_after", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
 	}
 
-	pr, err := h.S.CreatePR(r.Context(), pr)
+	err = json.NewEncoder(w).Encode(map[string]domain.Team{"team": team})
 	if err != nil {
-		if errors.Is(err, domain.ErrPrExists) {
-			http.Error(w, "PR_EXISTS", http.StatusConflict)
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+func (h *Handler) SaveReviewerPool(w http.ResponseWriter, r *http.Request) {
+	var pool domain.ReviewerPool
+	if !decodeJSON(w, r, &pool) {
+		return
+	}
+
+	if pool.PoolName == "" {
+		http.Error(w, "pool_name required", http.StatusBadRequest)
+		return
+	}
+
+	pool, err := h.S.SaveReviewerPool(r.Context(), pool)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
 			return
 		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error saving reviewer pool: %v", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]domain.ReviewerPool{"pool": pool})
+	if err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+func (h *Handler) GetReviewerPool(w http.ResponseWriter, r *http.Request) {
+	poolName := r.URL.Query().Get("pool_name")
+	if poolName == "" {
+		http.Error(w, "pool_name required", http.StatusBadRequest)
+		return
+	}
+
+	pool, err := h.S.GetReviewerPool(r.Context(), poolName)
+	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			http.Error(w, "AUTHOR_OR_TEAM_NOT_FOUND", http.StatusNotFound)
+			http.Error(w, "POOL_NOT_FOUND", http.StatusNotFound)
 			return
 		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
-		log.Printf("error creating PR: %v", err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	err = json.NewEncoder(w).Encode(pool)
 	if err != nil {
-		log.Printf("error encoding PR: %v", err)
+		log.Printf("failed to encode response: %v", err)
 	}
 }
 
-func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PullRequestID string `json:"pull_request_id"`
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	pr, err := h.S.MergePR(r.Context(), req.PullRequestID)
+	user, err := h.S.SetActive(r.Context(), req.UserID, req.IsActive)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			http.Error(w, "PR_NOT_FOUND", http.StatusNotFound)
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
 			return
 		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	err = json.NewEncoder(w).Encode(map[string]domain.User{"user": user})
 	if err != nil {
-		log.Printf("error encoding PR: %v", err)
+		log.Printf("error encoding user: %v", err)
 	}
 }
 
-func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		PullRequestID string `json:"pull_request_id"`
-		OldUserID     string `json:"old_reviewer_id"`
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "user_id required", http.StatusBadRequest)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+
+	user, err := h.S.GetUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
 
-	pr, replacedBy, err := h.S.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	err = json.NewEncoder(w).Encode(map[string]domain.User{"user": user})
 	if err != nil {
-		if errors.Is(err, domain.ErrPrMerged) {
-			http.Error(w, "PR_MERGED", http.StatusConflict)
+		log.Printf("error encoding user: %v", err)
+	}
+}
+
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.UserListFilter{
+		TeamName:       query.Get("team_name"),
+		UsernamePrefix: query.Get("username_prefix"),
+	}
+
+	if v := query.Get("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid is_active", http.StatusBadRequest)
 			return
 		}
-		if errors.Is(err, domain.ErrNotAssigned) {
-			http.Error(w, "NOT_ASSIGNED", http.StatusConflict)
+		filter.IsActive = &active
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
 			return
 		}
-		if errors.Is(err, domain.ErrNoCandidate) {
-			http.Error(w, "NO_CANDIDATE", http.StatusConflict)
+		filter.Limit = limit
+	}
+	if c := query.Get("cursor"); c != "" {
+		after, err := domain.DecodeUserCursor(c)
+		if err != nil {
+			http.Error(w, "INVALID_CURSOR", http.StatusBadRequest)
 			return
 		}
+		filter.AfterUserID = after
+	}
+
+	page, err := h.S.ListUsers(r.Context(), filter)
+	if err != nil {
 		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error listing users: %v", err)
 		return
 	}
 
-	resp := map[string]interface{}{
-		"pr":          pr,
-		"replaced_by": replacedBy,
+	err = json.NewEncoder(w).Encode(page)
+	if err != nil {
+		log.Printf("error encoding users: %v", err)
 	}
-	err = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) AddUser(w http.ResponseWriter, r *http.Request) {
+	// пользователь без is_active в запросе создаётся активным
+	req := domain.User{IsActive: true}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	user, err := h.S.CreateUser(r.Context(), req)
 	if err != nil {
-		log.Printf("error reassigning reviewer: %v", err)
+		if errors.Is(err, domain.ErrInvalidUser) {
+			http.Error(w, "user_id, username and team_name required, max_open_reviews must not be negative", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSchedule) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrUserExists) {
+			http.Error(w, "USER_EXISTS", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error creating user: %v", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]domain.User{"user": user})
+	if err != nil {
+		log.Printf("error encoding user: %v", err)
 	}
 }
 
-func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.S.GetStats(r.Context())
+func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+		domain.UserUpdate
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	user, err := h.S.UpdateUser(r.Context(), req.UserID, req.UserUpdate)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUser) {
+			http.Error(w, "username must not be empty, max_open_reviews must not be negative", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSchedule) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
+			return
+		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error updating user: %v", err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(stats)
+	err = json.NewEncoder(w).Encode(map[string]domain.User{"user": user})
 	if err != nil {
-		log.Printf("error encoding stats: %v", err)
+		log.Printf("error encoding user: %v", err)
 	}
 }
 
-func (h *Handler) DeactivateUsersHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserIDs []string `json:"user_ids"`
+		UserID         string `json:"user_id"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "bad request", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.MaxOpenReviews != nil && *req.MaxOpenReviews < 0 {
+		http.Error(w, "max_open_reviews must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.S.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]domain.User{"user": user})
+	if err != nil {
+		log.Printf("error encoding user: %v", err)
+	}
+}
+
+func (h *Handler) GetReviewLoad(w http.ResponseWriter, r *http.Request) {
+	load, err := h.S.GetReviewLoad(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error getting review load: %v", err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(map[string]interface{}{"teams": load})
+	if err != nil {
+		log.Printf("error encoding review load: %v", err)
+	}
+}
+
+func (h *Handler) AddUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req domain.Unavailability
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	window, err := h.S.AddUnavailability(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUnavailability) {
+			http.Error(w, "INVALID_UNAVAILABILITY", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error adding unavailability: %v", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]domain.Unavailability{"unavailability": window})
+	if err != nil {
+		log.Printf("error encoding unavailability: %v", err)
+	}
+}
+
+func (h *Handler) GetUnavailability(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "user_id required", http.StatusBadRequest)
+		return
+	}
+
+	windows, err := h.S.ListUnavailability(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "USER_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"user_id":        userID,
+		"unavailability": windows,
+	}
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Printf("error encoding unavailability: %v", err)
+	}
+}
+
+func (h *Handler) RemoveUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := h.S.DeleteUnavailability(r.Context(), req.ID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "UNAVAILABILITY_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetUserPRs(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		http.Error(w, "user_id required", http.StatusBadRequest)
+		return
+	}
+
+	prs, err := h.S.GetPRsForReviewer(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error getting PRs for reviewer: %v", err)
+		return
+	}
+
+	if prs == nil {
+		http.Error(w, "PR_NOT_FOUND", http.StatusNotFound)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"user_id":       userID,
+		"pull_requests": prs,
+	}
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Printf("error writing response: %v", err)
+	}
+}
+
+func (h *Handler) ListPRs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.PRListFilter{
+		AuthorID:   query.Get("author_id"),
+		ReviewerID: query.Get("reviewer_id"),
+		TeamName:   query.Get("team_name"),
+		Status:     domain.PullRequestStatus(query.Get("status")),
+		SortBy:     domain.PRSortField(query.Get("sort")),
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		http.Error(w, "invalid order", http.StatusBadRequest)
+		return
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	for name, dst := range map[string]**time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+		"merged_from":  &filter.MergedFrom,
+		"merged_to":    &filter.MergedTo,
+	} {
+		if *dst, err = parseStatsTime(query.Get(name)); err != nil {
+			http.Error(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if c := query.Get("cursor"); c != "" {
+		if filter.Cursor, err = domain.DecodePRCursor(c); err != nil {
+			http.Error(w, "INVALID_CURSOR", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.S.ListPRs(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			http.Error(w, "INVALID_CURSOR", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPRFilter) {
+			http.Error(w, "INVALID_PR_FILTER", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error listing PRs: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(page)
+	if err != nil {
+		log.Printf("error encoding PR page: %v", err)
+	}
+}
+
+func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID   string   `json:"pull_request_id"`
+		PullRequestName string   `json:"pull_request_name"`
+		AuthorID        string   `json:"author_id"`
+		Repository      *string  `json:"repository"`
+		PRNumber        *int     `json:"pr_number"`
+		ChangedFiles    []string `json:"changed_files"`
+		AssignmentMode  string   `json:"assignment_mode"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if (req.Repository == nil) != (req.PRNumber == nil) {
+		http.Error(w, "repository and pr_number must be set together", http.StatusBadRequest)
+		return
+	}
+
+	mode := domain.AssignmentMode(req.AssignmentMode)
+	if mode != "" && !mode.Valid() {
+		http.Error(w, "unknown assignment_mode", http.StatusBadRequest)
+		return
+	}
+
+	pr := domain.PullRequest{
+		PullRequestID:   req.PullRequestID,
+		PullRequestName: req.PullRequestName,
+		AuthorID:        req.AuthorID,
+		Repository:      req.Repository,
+		PRNumber:        req.PRNumber,
+	}
+
+	pr, err := h.S.CreatePR(r.Context(), pr, services.CreatePROptions{
+		ChangedFiles:   req.ChangedFiles,
+		AssignmentMode: mode,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPrExists) {
+			http.Error(w, "PR_EXISTS", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "AUTHOR_OR_TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error creating PR: %v", err)
+		return
+	}
+
+	setPRETag(w, pr)
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	if err != nil {
+		log.Printf("error encoding PR: %v", err)
+	}
+}
+
+// BulkCreatePRs принимает JSON-массив PR или NDJSON (по объекту на строку).
+func (h *Handler) BulkCreatePRs(w http.ResponseWriter, r *http.Request) {
+	results, err := h.S.BulkCreatePRs(r.Context(), r.Body)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidBulkInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error bulk creating PRs: %v", err)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"results": results,
+	}
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Printf("error encoding results: %v", err)
+	}
+}
+
+func (h *Handler) GetPR(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		http.Error(w, "pull_request_id required", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.S.GetPR(r.Context(), prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "PR_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error getting PR: %v", err)
+		return
+	}
+
+	setPRETag(w, pr)
+	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	if err != nil {
+		log.Printf("error encoding PR: %v", err)
+	}
+}
+
+func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writePreconditionFailed(w)
+		return
+	}
+
+	pr, err := h.S.MergePR(r.Context(), req.PullRequestID, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "PR_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			writePreconditionFailed(w)
+			return
+		}
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			http.Error(w, "PR_CONCURRENT_UPDATE", http.StatusConflict)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	setPRETag(w, pr)
+
+	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	if err != nil {
+		log.Printf("error encoding PR: %v", err)
+	}
+}
+
+func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_reviewer_id"`
+		NewUserID     string `json:"new_reviewer_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writePreconditionFailed(w)
+		return
+	}
+
+	if req.NewUserID != "" {
+		pr, err := h.S.ReassignReviewerTo(r.Context(), req.PullRequestID, req.OldUserID, req.NewUserID, version)
+		if err != nil {
+			writeManualReviewerError(w, err)
+			return
+		}
+		setPRETag(w, pr)
+		resp := map[string]interface{}{
+			"pr":          pr,
+			"replaced_by": req.NewUserID,
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("error reassigning reviewer: %v", err)
+		}
+		return
+	}
+
+	pr, replacedBy, err := h.S.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionMismatch) {
+			writePreconditionFailed(w)
+			return
+		}
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			http.Error(w, "PR_CONCURRENT_UPDATE", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrPrMerged) {
+			http.Error(w, "PR_MERGED", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrNotAssigned) {
+			http.Error(w, "NOT_ASSIGNED", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrNoCandidate) {
+			http.Error(w, "NO_CANDIDATE", http.StatusConflict)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	setPRETag(w, pr)
+	resp := map[string]interface{}{
+		"pr":          pr,
+		"replaced_by": replacedBy,
+	}
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Printf("error reassigning reviewer: %v", err)
+	}
+}
+
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string               `json:"pull_request_id"`
+		ReviewerID    string               `json:"reviewer_id"`
+		Verdict       domain.ReviewVerdict `json:"verdict"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	review, err := h.S.SubmitReview(r.Context(), req.PullRequestID, req.ReviewerID, req.Verdict)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidVerdict) {
+			http.Error(w, "INVALID_VERDICT", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "PR_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrPrMerged) {
+			http.Error(w, "PR_MERGED", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrNotAssigned) {
+			http.Error(w, "NOT_ASSIGNED", http.StatusConflict)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error submitting review: %v", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(map[string]domain.Review{"review": review})
+	if err != nil {
+		log.Printf("error encoding review: %v", err)
+	}
+}
+
+func (h *Handler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writePreconditionFailed(w)
+		return
+	}
+
+	pr, err := h.S.AddReviewer(r.Context(), req.PullRequestID, req.ReviewerID, version)
+	if err != nil {
+		writeManualReviewerError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	if err != nil {
+		log.Printf("error encoding PR: %v", err)
+	}
+}
+
+func (h *Handler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id"`
+		ReviewerID    string `json:"reviewer_id"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writePreconditionFailed(w)
+		return
+	}
+
+	pr, err := h.S.RemoveReviewer(r.Context(), req.PullRequestID, req.ReviewerID, version)
+	if err != nil {
+		writeManualReviewerError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	err = json.NewEncoder(w).Encode(map[string]domain.PullRequest{"pr": pr})
+	if err != nil {
+		log.Printf("error encoding PR: %v", err)
+	}
+}
+
+// writeManualReviewerError отвечает на ошибки ручного назначения и снятия ревьюверов.
+func writeManualReviewerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, "NOT_FOUND", http.StatusNotFound)
+	case errors.Is(err, domain.ErrPrMerged):
+		http.Error(w, "PR_MERGED", http.StatusConflict)
+	case errors.Is(err, domain.ErrNotAssigned):
+		http.Error(w, "NOT_ASSIGNED", http.StatusConflict)
+	case errors.Is(err, domain.ErrAlreadyAssigned):
+		http.Error(w, "ALREADY_ASSIGNED", http.StatusConflict)
+	case errors.Is(err, domain.ErrNotTeamMember):
+		http.Error(w, "NOT_TEAM_MEMBER", http.StatusConflict)
+	case errors.Is(err, domain.ErrReviewerIsAuthor):
+		http.Error(w, "REVIEWER_IS_AUTHOR", http.StatusConflict)
+	case errors.Is(err, domain.ErrUserInactive):
+		http.Error(w, "USER_INACTIVE", http.StatusConflict)
+	case errors.Is(err, domain.ErrReviewerLimit):
+		http.Error(w, "REVIEWER_LIMIT", http.StatusConflict)
+	case errors.Is(err, domain.ErrVersionMismatch):
+		writePreconditionFailed(w)
+	case errors.Is(err, domain.ErrConcurrentUpdate):
+		http.Error(w, "PR_CONCURRENT_UPDATE", http.StatusConflict)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error changing reviewers: %v", err)
+	}
+}
+
+func (h *Handler) UploadCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req domain.CodeOwnersFile
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	co, err := h.S.UploadCodeOwners(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCodeOwners) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "TEAM_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error uploading CODEOWNERS: %v", err)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"team_name": req.TeamName,
+		"rules":     co.Rules,
+	}
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Printf("error encoding CODEOWNERS: %v", err)
+	}
+}
+
+func (h *Handler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	file, err := h.S.GetCodeOwners(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "CODEOWNERS_NOT_FOUND", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(file)
+	if err != nil {
+		log.Printf("error encoding CODEOWNERS: %v", err)
+	}
+}
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStatsFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.S.GetStats(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidStatsFilter) {
+			http.Error(w, "INVALID_STATS_FILTER", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(stats)
+	if err != nil {
+		log.Printf("error encoding stats: %v", err)
+	}
+}
+
+func (h *Handler) GetCycleTimeStats(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStatsFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.S.GetCycleTimeStats(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidStatsFilter) {
+			http.Error(w, "INVALID_STATS_FILTER", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error getting cycle time stats: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(stats)
+	if err != nil {
+		log.Printf("error encoding cycle time stats: %v", err)
+	}
+}
+
+func (h *Handler) GetFairnessReport(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStatsFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.S.GetFairnessReport(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidStatsFilter) {
+			http.Error(w, "INVALID_STATS_FILTER", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		log.Printf("error getting fairness report: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(report)
+	if err != nil {
+		log.Printf("error encoding fairness report: %v", err)
+	}
+}
+
+func parseStatsFilter(r *http.Request) (domain.StatsFilter, error) {
+	query := r.URL.Query()
+	filter := domain.StatsFilter{
+		TeamName: query.Get("team_name"),
+		Status:   domain.PullRequestStatus(query.Get("status")),
+	}
+
+	var err error
+	if filter.From, err = parseStatsTime(query.Get("from")); err != nil {
+		return domain.StatsFilter{}, errors.New("invalid from")
+	}
+	if filter.To, err = parseStatsTime(query.Get("to")); err != nil {
+		return domain.StatsFilter{}, errors.New("invalid to")
+	}
+	return filter, nil
+}
+
+// parseStatsTime принимает RFC 3339 или дату YYYY-MM-DD (полночь UTC).
+func parseStatsTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func (h *Handler) DeactivateUsersHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserIDs []string `json:"user_ids"`
+	}
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
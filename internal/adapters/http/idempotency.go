@@ -0,0 +1,206 @@
+package http
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	defaultIdempotencyKeysTTL = 24 * time.Hour
+	// idempotencyLease — срок аренды незавершённой резервации. Пока запрос выполняется,
+	// аренда продлевается; если процесс упал, ретрай перезаймёт ключ после её истечения.
+	idempotencyLease = 30 * time.Second
+)
+
+// replayedHeaders — заголовки ответа, которые сохраняются и отдаются при повторе.
+var replayedHeaders = []string{"ETag"}
+
+// Idempotency сохраняет ответы на POST-запросы с заголовком Idempotency-Key и
+// отдаёт их повторно при ретраях. Ключи разных API-токенов не пересекаются. Повтор ключа
+// с другим запросом — 409
+// IDEMPOTENCY_KEY_REUSED, повтор во время выполнения первого — 409
+// IDEMPOTENCY_KEY_IN_PROGRESS; если первый запрос так и не ответил (процесс упал), ключ
+// перезанимается по истечении аренды. Ответы 5xx не сохраняются, чтобы запрос можно было повторить.
+func Idempotency(store ports.IdempotencyStore, ttl time.Duration) func(http.Handler) http.Handler {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyKeysTTL
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "REQUEST_TOO_LARGE", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := hashedBearerToken(r)
+			fingerprint := IdempotencyFingerprint(r.Method, r.URL.RequestURI(), body)
+			owner := newIdempotencyOwner()
+			now := time.Now()
+			record, reserved, err := store.ReserveIdempotencyKey(r.Context(), scope, key, fingerprint, owner, now.Add(idempotencyLease), now.Add(ttl))
+			if err != nil {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				log.Printf("error reserving idempotency key: %v", err)
+				return
+			}
+
+			if !reserved {
+				switch {
+				case record.Fingerprint != fingerprint:
+					http.Error(w, "IDEMPOTENCY_KEY_REUSED", http.StatusConflict)
+				case record.StatusCode == nil:
+					http.Error(w, "IDEMPOTENCY_KEY_IN_PROGRESS", http.StatusConflict)
+				default:
+					if record.ContentType != nil && *record.ContentType != "" {
+						w.Header().Set("Content-Type", *record.ContentType)
+					}
+					for name, value := range record.Headers {
+						w.Header().Set(name, value)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(*record.StatusCode)
+					if _, err := w.Write(record.Body); err != nil {
+						log.Printf("error replaying idempotent response: %v", err)
+					}
+				}
+				return
+			}
+
+			stopLease := extendLease(context.WithoutCancel(r.Context()), store, scope, key, owner)
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				stopLease()
+				// запрос мог быть отменён клиентом, а результат всё равно нужно сохранить
+				ctx := context.WithoutCancel(r.Context())
+				if p := recover(); p != nil {
+					if err := store.ReleaseIdempotencyKey(ctx, scope, key, owner); err != nil {
+						log.Printf("error releasing idempotency key: %v", err)
+					}
+					panic(p)
+				}
+				if rec.status >= http.StatusInternalServerError {
+					if err := store.ReleaseIdempotencyKey(ctx, scope, key, owner); err != nil {
+						log.Printf("error releasing idempotency key: %v", err)
+					}
+					return
+				}
+				var headers domain.ReplayedHeaders
+				for _, name := range replayedHeaders {
+					if v := w.Header().Get(name); v != "" {
+						if headers == nil {
+							headers = make(domain.ReplayedHeaders)
+						}
+						headers[name] = v
+					}
+				}
+				if err := store.CompleteIdempotencyKey(ctx, scope, key, owner, rec.status, w.Header().Get("Content-Type"), headers, rec.body.Bytes()); err != nil {
+					log.Printf("error saving idempotent response: %v", err)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// IdempotencyFingerprint — отпечаток запроса, к которому привязывается ключ идемпотентности;
+// uri включает query-параметры.
+func IdempotencyFingerprint(method, uri string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(method + " " + uri + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+func newIdempotencyOwner() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// extendLease продлевает аренду резервации, пока запрос выполняется; возвращает функцию остановки.
+func extendLease(ctx context.Context, store ports.IdempotencyStore, scope, key, owner string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(idempotencyLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.ExtendIdempotencyKey(ctx, scope, key, owner, time.Now().Add(idempotencyLease)); err != nil {
+					log.Printf("error extending idempotency key lease: %v", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// PurgeIdempotencyKeys периодически удаляет истёкшие ключи, пока не отменён ctx.
+func PurgeIdempotencyKeys(ctx context.Context, store ports.IdempotencyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := store.PurgeIdempotencyKeys(ctx, time.Now()); err != nil {
+				log.Printf("error purging idempotency keys: %v", err)
+			}
+		}
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitConfig задаёт отдельные корзины для API-токена из заголовка Authorization
+// и для IP клиента. Запрос с токеном проходит обе проверки.
+type RateLimitConfig struct {
+	PerToken domain.RateLimit
+	PerIP    domain.RateLimit
+}
+
+// RateLimit отвечает 429 RATE_LIMITED с Retry-After, когда корзина клиента пуста.
+// Ошибки хранилища не блокируют запросы: недоступность лимитера не должна останавливать API.
+func RateLimit(store ports.RateLimitStore, cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+
+			buckets := make([]rateLimitBucket, 0, 2)
+			if cfg.PerIP.Enabled() {
+				buckets = append(buckets, rateLimitBucket{key: "ip:" + clientIP(r), limit: cfg.PerIP})
+			}
+			if token := hashedBearerToken(r); token != "" && cfg.PerToken.Enabled() {
+				// в хранилище попадает только хеш токена
+				buckets = append(buckets, rateLimitBucket{key: "token:" + token, limit: cfg.PerToken})
+			}
+
+			for _, b := range buckets {
+				allowed, retryAfter, err := store.TakeRateLimitToken(r.Context(), b.key, b.limit, now)
+				if err != nil {
+					log.Printf("error checking rate limit: %v", err)
+					continue
+				}
+				if !allowed {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+					http.Error(w, "RATE_LIMITED", http.StatusTooManyRequests)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type rateLimitBucket struct {
+	key   string
+	limit domain.RateLimit
+}
+
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// hashedBearerToken — хеш API-токена из заголовка Authorization, без токена — пустая строка.
+func hashedBearerToken(r *http.Request) string {
+	token := bearerToken(r)
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// MaxBodySize ограничивает размер тела запроса: заведомо большие тела отклоняются
+// сразу с 413, остальные обрываются на limit байтах при чтении.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				http.Error(w, "REQUEST_TOO_LARGE", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MemoryRateLimitStore хранит корзины в памяти процесса; лимиты действуют на каждый
+// экземпляр сервиса отдельно.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]time.Time)}
+}
+
+func (s *MemoryRateLimitStore) TakeRateLimitToken(_ context.Context, key string, limit domain.RateLimit, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tat, allowed, retryAfter := limit.Take(s.buckets[key], now)
+	s.buckets[key] = tat
+	return allowed, retryAfter, nil
+}
+
+func (s *MemoryRateLimitStore) PurgeRateLimitBuckets(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key, tat := range s.buckets {
+		if !tat.After(before) {
+			delete(s.buckets, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// PurgeRateLimitBuckets периодически удаляет заполнившиеся корзины, пока не отменён ctx.
+func PurgeRateLimitBuckets(ctx context.Context, store ports.RateLimitStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := store.PurgeRateLimitBuckets(ctx, time.Now()); err != nil {
+				log.Printf("error purging rate limit buckets: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"PRService/internal/ports"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RouterConfig — хранилища и лимиты middleware HTTP API.
+type RouterConfig struct {
+	RateLimits     ports.RateLimitStore
+	RateLimit      RateLimitConfig
+	MaxBodyBytes   int64
+	Idempotency    ports.IdempotencyStore
+	IdempotencyTTL time.Duration
+	// AdminToken — Bearer-токен для /admin/*; пустой отключает эти маршруты.
+	AdminToken string
+	// GraphQL обслуживает POST /graphql; nil — без GraphQL.
+	GraphQL http.Handler
+}
+
+// NewRouter собирает маршруты API вместе с middleware. Им пользуются и сервер, и e2e-тесты,
+// чтобы тесты проверяли тот же порядок middleware, что и в проде.
+func NewRouter(handler *Handler, cfg RouterConfig) chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+	r.Use(RateLimit(cfg.RateLimits, cfg.RateLimit))
+
+	// массовая загрузка читает тело потоком, поэтому без лимита размера и без Idempotency-Key:
+	// повтор безопасен, уже созданные PR вернутся как "PR already exists"
+	r.Post("/pullRequest/bulkCreate", handler.BulkCreatePRs)
+
+	// выгрузка для импорта может быть больше MaxBodyBytes
+	r.Group(func(r chi.Router) {
+		r.Use(AdminOnly(cfg.AdminToken))
+		r.Get("/admin/export", handler.ExportData)
+		r.Post("/admin/import", handler.ImportData)
+		r.Get("/admin/migrations", handler.GetMigrationStatus)
+		r.Post("/admin/migrations/up", handler.MigrateUp)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(MaxBodySize(cfg.MaxBodyBytes))
+		r.Use(Idempotency(cfg.Idempotency, cfg.IdempotencyTTL))
+
+		r.Post("/team/add", handler.CreateTeam)
+		r.Get("/team/get", handler.GetTeam)
+		r.Post("/team/setFallback", handler.SetTeamFallback)
+		r.Post("/team/setMaxOpenReviews", handler.SetTeamMaxOpenReviews)
+		r.Post("/team/setReviewerLimits", handler.SetTeamReviewerLimits)
+		r.Post("/team/setReviewSLA", handler.SetTeamReviewSLA)
+
+		r.Post("/pool/add", handler.SaveReviewerPool)
+		r.Get("/pool/get", handler.GetReviewerPool)
+
+		r.Get("/users/get", handler.GetUser)
+		r.Get("/users/list", handler.ListUsers)
+		r.Post("/users/add", handler.AddUser)
+		r.Post("/users/update", handler.UpdateUser)
+		r.Post("/users/setIsActive", handler.SetUserActive)
+		r.Post("/users/deactivate", handler.DeactivateUsersHandler) // безопасная массовая деактивация
+		r.Get("/users/getReview", handler.GetUserPRs)
+		r.Post("/users/setMaxOpenReviews", handler.SetMaxOpenReviews)
+		r.Get("/users/load", handler.GetReviewLoad)
+		r.Post("/users/addUnavailability", handler.AddUnavailability)
+		r.Get("/users/getUnavailability", handler.GetUnavailability)
+		r.Post("/users/removeUnavailability", handler.RemoveUnavailability)
+
+		r.Post("/pullRequest/create", handler.CreatePR)
+		r.Get("/pullRequest/get", handler.GetPR)
+		r.Post("/pullRequest/merge", handler.MergePR)
+		r.Post("/pullRequest/reassign", handler.ReassignReviewer)
+		r.Post("/pullRequest/reviewers/add", handler.AddReviewer)
+		r.Post("/pullRequest/reviewers/remove", handler.RemoveReviewer)
+		r.Post("/pullRequest/review", handler.SubmitReview)
+		r.Get("/pullRequest/list", handler.ListPRs)
+
+		r.Post("/codeowners/upload", handler.UploadCodeOwners)
+		r.Get("/codeowners/get", handler.GetCodeOwners)
+
+		r.Get("/stats", handler.GetStats)
+		r.Get("/stats/cycle-time", handler.GetCycleTimeStats)
+		r.Get("/stats/fairness", handler.GetFairnessReport)
+
+		if cfg.GraphQL != nil {
+			r.Post("/graphql", cfg.GraphQL.ServeHTTP)
+		}
+	})
+
+	return r
+}
@@ -0,0 +1,349 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportSnapshot вызывает fn с чтениями из одной read-only транзакции REPEATABLE READ,
+// чтобы выгрузка была согласованной, даже если данные меняются во время неё.
+func (r *Repo) ExportSnapshot(ctx context.Context, fn func(ports.ExportReader) error) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(&Repo{db: r.db, read: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListTeams возвращает настройки всех команд, включая команды без участников; участники не заполняются.
+func (r *Repo) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	return r.listTeams(ctx, nil)
+}
+
+// GetTeamsByNames — то же, что ListTeams, для перечисленных команд; отсутствующие пропускаются.
+func (r *Repo) GetTeamsByNames(ctx context.Context, teamNames []string) ([]domain.Team, error) {
+	if len(teamNames) == 0 {
+		return nil, nil
+	}
+	return r.listTeams(ctx, teamNames)
+}
+
+// ListTeamsPage возвращает до filter.Limit команд после filter.AfterTeamName в порядке имени.
+func (r *Repo) ListTeamsPage(ctx context.Context, filter domain.TeamListFilter) ([]domain.Team, error) {
+	var names []string
+	err := r.db.SelectContext(ctx, &names,
+		`SELECT team_name FROM teams WHERE team_name > $1 ORDER BY team_name LIMIT $2`,
+		filter.AfterTeamName, filter.Limit,
+	)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	return r.listTeams(ctx, names)
+}
+
+// listTeams читает настройки команд из teamNames или всех команд, если список пуст.
+func (r *Repo) listTeams(ctx context.Context, teamNames []string) ([]domain.Team, error) {
+	where := ""
+	var args []interface{}
+	if len(teamNames) > 0 {
+		where = " WHERE team_name IN (?)"
+		args = append(args, teamNames)
+	}
+
+	var rows []struct {
+		TeamName              string `db:"team_name"`
+		DefaultMaxOpenReviews *int   `db:"default_max_open_reviews"`
+		ReminderSeconds       *int64 `db:"review_sla_reminder_seconds"`
+		ReassignSeconds       *int64 `db:"review_sla_reassign_seconds"`
+		MinReviewers          *int   `db:"min_reviewers"`
+		MaxReviewers          *int   `db:"max_reviewers"`
+	}
+	query, queryArgs, err := sqlx.In(
+		`SELECT team_name, default_max_open_reviews, review_sla_reminder_seconds, review_sla_reassign_seconds,
+		        min_reviewers, max_reviewers
+		 FROM teams`+where+` ORDER BY team_name`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.read.SelectContext(ctx, &rows, r.read.Rebind(query), queryArgs...); err != nil {
+		return nil, err
+	}
+
+	var fallbacks []struct {
+		TeamName   string                `db:"team_name"`
+		Kind       domain.ReviewerSource `db:"kind"`
+		SourceName string                `db:"source_name"`
+	}
+	query, queryArgs, err = sqlx.In(
+		`SELECT team_name, kind, source_name FROM team_fallbacks`+where+` ORDER BY team_name, position`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.read.SelectContext(ctx, &fallbacks, r.read.Rebind(query), queryArgs...); err != nil {
+		return nil, err
+	}
+
+	teams := make([]domain.Team, 0, len(rows))
+	index := make(map[string]int, len(rows))
+	for _, row := range rows {
+		t := domain.Team{
+			TeamName:              row.TeamName,
+			DefaultMaxOpenReviews: row.DefaultMaxOpenReviews,
+			MinReviewers:          row.MinReviewers,
+			MaxReviewers:          row.MaxReviewers,
+		}
+		if row.ReminderSeconds != nil {
+			t.ReviewSLA = &domain.ReviewSLA{ReminderAfter: time.Duration(*row.ReminderSeconds) * time.Second}
+			if row.ReassignSeconds != nil {
+				t.ReviewSLA.ReassignAfter = time.Duration(*row.ReassignSeconds) * time.Second
+			}
+		}
+		index[t.TeamName] = len(teams)
+		teams = append(teams, t)
+	}
+	for _, f := range fallbacks {
+		t := &teams[index[f.TeamName]]
+		if f.Kind == domain.SourcePartnerTeam {
+			t.PartnerTeams = append(t.PartnerTeams, f.SourceName)
+		} else {
+			t.FallbackPools = append(t.FallbackPools, f.SourceName)
+		}
+	}
+	return teams, nil
+}
+
+func (r *Repo) ListReviewerPools(ctx context.Context) ([]domain.ReviewerPool, error) {
+	var rows []struct {
+		PoolName string  `db:"pool_name"`
+		UserID   *string `db:"user_id"`
+	}
+	err := r.read.SelectContext(ctx, &rows,
+		`SELECT p.pool_name, m.user_id
+		 FROM reviewer_pools p
+		 LEFT JOIN reviewer_pool_members m ON m.pool_name = p.pool_name
+		 ORDER BY p.pool_name, m.user_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []domain.ReviewerPool
+	for _, row := range rows {
+		if len(pools) == 0 || pools[len(pools)-1].PoolName != row.PoolName {
+			pools = append(pools, domain.ReviewerPool{PoolName: row.PoolName, Members: []string{}})
+		}
+		if row.UserID != nil {
+			p := &pools[len(pools)-1]
+			p.Members = append(p.Members, *row.UserID)
+		}
+	}
+	return pools, nil
+}
+
+func (r *Repo) ListCodeOwners(ctx context.Context) ([]domain.CodeOwnersFile, error) {
+	files := []domain.CodeOwnersFile{}
+	err := r.read.SelectContext(ctx, &files,
+		`SELECT team_name, content, updated_at FROM codeowners ORDER BY team_name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ListReviewsByPRs возвращает отзывы сразу по нескольким PR.
+func (r *Repo) ListReviewsByPRs(ctx context.Context, prIDs []string) ([]domain.Review, error) {
+	if len(prIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(
+		`SELECT id, pull_request_id, user_id, verdict, submitted_at
+		 FROM pr_reviews WHERE pull_request_id IN (?)
+		 ORDER BY pull_request_id, submitted_at, id`,
+		prIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []domain.Review
+	if err := r.read.SelectContext(ctx, &reviews, r.read.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ListUserCreatedAt возвращает, когда появились пользователи userIDs.
+func (r *Repo) ListUserCreatedAt(ctx context.Context, userIDs []string) (map[string]time.Time, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT user_id, created_at FROM users WHERE user_id IN (?)`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		UserID    string    `db:"user_id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	if err := r.read.SelectContext(ctx, &rows, r.read.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	createdAt := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		createdAt[row.UserID] = row.CreatedAt
+	}
+	return createdAt, nil
+}
+
+// ListRemindersByPRs возвращает, когда ревьюверам PR уже напомнили по SLA: pull_request_id -> user_id -> время.
+func (r *Repo) ListRemindersByPRs(ctx context.Context, prIDs []string) (map[string]map[string]time.Time, error) {
+	if len(prIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(
+		`SELECT pull_request_id, user_id, reminded_at
+		 FROM pr_reviewers WHERE pull_request_id IN (?) AND reminded_at IS NOT NULL`,
+		prIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		PullRequestID string    `db:"pull_request_id"`
+		UserID        string    `db:"user_id"`
+		RemindedAt    time.Time `db:"reminded_at"`
+	}
+	if err := r.read.SelectContext(ctx, &rows, r.read.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	reminders := make(map[string]map[string]time.Time)
+	for _, row := range rows {
+		if reminders[row.PullRequestID] == nil {
+			reminders[row.PullRequestID] = make(map[string]time.Time)
+		}
+		reminders[row.PullRequestID][row.UserID] = row.RemindedAt
+	}
+	return reminders, nil
+}
+
+// SaveUser записывает пользователя целиком, в отличие от UpsertUsers не сохраняя прежние
+// лимит, часовой пояс и рабочие часы. createdAt == nil оставляет прежнее время появления.
+// Используется при импорте выгрузки.
+func (r *Repo) SaveUser(ctx context.Context, user domain.User, createdAt *time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (user_id, username, team_name, is_active, max_open_reviews, timezone, working_hours, created_at)
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, COALESCE($8, now()))
+		 ON CONFLICT (user_id) DO UPDATE SET
+		     username = EXCLUDED.username,
+		     team_name = EXCLUDED.team_name,
+		     is_active = EXCLUDED.is_active,
+		     max_open_reviews = EXCLUDED.max_open_reviews,
+		     timezone = EXCLUDED.timezone,
+		     working_hours = EXCLUDED.working_hours,
+		     created_at = COALESCE($8, users.created_at)`,
+		user.UserID, user.Username, user.TeamName, user.IsActive, user.MaxOpenReviews,
+		user.Timezone, user.WorkingHours, createdAt,
+	)
+	if err != nil && strings.Contains(err.Error(), "foreign key") {
+		return domain.ErrTeamNotFound
+	}
+	return err
+}
+
+// SavePR записывает PR целиком: создаёт или перезаписывает строку PR и заменяет его
+// назначения, отметки о напоминаниях remindedAt (по user_id) и историю отзывов.
+// Используется при импорте выгрузки.
+func (r *Repo) SavePR(ctx context.Context, pr domain.PullRequest, reviews []domain.Review, remindedAt map[string]time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO pull_requests
+		    (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, repository, pr_number, version)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, GREATEST($9, 1))
+		 ON CONFLICT (pull_request_id) DO UPDATE SET
+		     pull_request_name = EXCLUDED.pull_request_name,
+		     author_id = EXCLUDED.author_id,
+		     status = EXCLUDED.status,
+		     created_at = EXCLUDED.created_at,
+		     merged_at = EXCLUDED.merged_at,
+		     repository = EXCLUDED.repository,
+		     pr_number = EXCLUDED.pr_number,
+		     version = pull_requests.version + 1`,
+		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.CreatedAt, pr.MergedAt,
+		pr.Repository, pr.PRNumber, pr.Version,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	for _, q := range []string{
+		`DELETE FROM pr_reviewers WHERE pull_request_id = $1`,
+		`DELETE FROM pr_reviews WHERE pull_request_id = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, pr.PullRequestID); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range pr.Assignments {
+		var reminded *time.Time
+		if at, ok := remindedAt[a.UserID]; ok {
+			reminded = &at
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (pull_request_id, user_id, source, source_name, assigned_at, reminded_at)
+			 VALUES ($1, $2, $3, $4, COALESCE($5, now()), $6)`,
+			pr.PullRequestID, a.UserID, a.Source, a.SourceName, a.AssignedAt, reminded,
+		)
+		if err != nil {
+			if strings.Contains(err.Error(), "foreign key") {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+	}
+	for _, v := range reviews {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO pr_reviews (pull_request_id, user_id, verdict, submitted_at) VALUES ($1, $2, $3, $4)`,
+			pr.PullRequestID, v.UserID, v.Verdict, v.SubmittedAt,
+		)
+		if err != nil {
+			if strings.Contains(err.Error(), "foreign key") {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+func (r *Repo) ReserveIdempotencyKey(ctx context.Context, scope, key, fingerprint, owner string, leaseUntil, expiresAt time.Time) (domain.IdempotencyRecord, bool, error) {
+	// истёкший ключ перезанимается любым запросом, брошенная резервация — только тем же запросом
+	var reserved string
+	err := r.db.GetContext(ctx, &reserved,
+		`INSERT INTO idempotency_keys (scope, key, fingerprint, owner, lease_until, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (scope, key) DO UPDATE SET
+		     fingerprint = EXCLUDED.fingerprint,
+		     owner = EXCLUDED.owner,
+		     status_code = NULL,
+		     content_type = NULL,
+		     headers = NULL,
+		     body = NULL,
+		     created_at = now(),
+		     lease_until = EXCLUDED.lease_until,
+		     expires_at = EXCLUDED.expires_at
+		 WHERE idempotency_keys.expires_at <= now()
+		    OR (idempotency_keys.status_code IS NULL
+		        AND idempotency_keys.fingerprint = EXCLUDED.fingerprint
+		        AND idempotency_keys.lease_until <= now())
+		 RETURNING key`,
+		scope, key, fingerprint, owner, leaseUntil, expiresAt,
+	)
+	if err == nil {
+		return domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	var existing domain.IdempotencyRecord
+	err = r.db.GetContext(ctx, &existing,
+		`SELECT key, fingerprint, status_code, content_type, headers, body, expires_at
+		 FROM idempotency_keys WHERE scope = $1 AND key = $2`,
+		scope, key,
+	)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	return existing, false, nil
+}
+
+func (r *Repo) ExtendIdempotencyKey(ctx context.Context, scope, key, owner string, leaseUntil time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE idempotency_keys SET lease_until = $1
+		 WHERE scope = $2 AND key = $3 AND owner = $4 AND status_code IS NULL`,
+		leaseUntil, scope, key, owner,
+	)
+	return err
+}
+
+func (r *Repo) CompleteIdempotencyKey(ctx context.Context, scope, key, owner string, statusCode int, contentType string, headers domain.ReplayedHeaders, body []byte) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE idempotency_keys
+		 SET status_code = $1, content_type = $2, headers = $3, body = $4, lease_until = NULL
+		 WHERE scope = $5 AND key = $6 AND owner = $7`,
+		statusCode, contentType, headers, body, scope, key, owner,
+	)
+	return err
+}
+
+func (r *Repo) ReleaseIdempotencyKey(ctx context.Context, scope, key, owner string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND owner = $3`,
+		scope, key, owner,
+	)
+	return err
+}
+
+func (r *Repo) PurgeIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
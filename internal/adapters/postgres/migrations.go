@@ -1,8 +1,13 @@
 package postgres
 
 import (
+	"PRService/internal/domain"
+	"context"
+	"database/sql"
 	"embed"
 	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -13,30 +18,130 @@ import (
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
-func (r *Repo) Migrate() error {
-	log.Print("running migration")
+// withMigrate запускает fn над встроенными миграциями. Драйвер миграций держит
+// подключение до Close и закрывает переданный ему пул, поэтому пул у него свой.
+func (r *Repo) withMigrate(fn func(m *migrate.Migrate) error) error {
+	db, err := sql.Open(r.db.DriverName(), r.dbURL)
+	if err != nil {
+		return err
+	}
 	files, err := iofs.New(migrationFiles, "migrations")
 	if err != nil {
+		_ = db.Close()
 		return err
 	}
-	driver, err := pgx.WithInstance(r.db.DB, &pgx.Config{})
+	driver, err := pgx.WithInstance(db, &pgx.Config{})
 	if err != nil {
+		_ = db.Close()
 		return err
 	}
 	m, err := migrate.NewWithInstance("iofs", files, "pgx", driver)
 	if err != nil {
+		_ = driver.Close()
 		return err
 	}
-
-	err = m.Up()
-
-	if err != nil {
-		if !errors.Is(err, migrate.ErrNoChange) {
-			return err
+	defer func() {
+		if srcErr, dbErr := m.Close(); srcErr != nil || dbErr != nil {
+			log.Printf("error closing migrations: source: %v, db: %v", srcErr, dbErr)
 		}
+	}()
+
+	err = fn(m)
+	if errors.Is(err, migrate.ErrNoChange) {
 		log.Print("migration did not change anything")
+		return nil
 	}
+	return err
+}
 
+func (r *Repo) Migrate() error {
+	log.Print("running migration")
+	if err := r.withMigrate((*migrate.Migrate).Up); err != nil {
+		return err
+	}
 	log.Print("migration finished")
 	return nil
 }
+
+// MigrateDown откатывает steps последних применённых миграций.
+func (r *Repo) MigrateDown(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("invalid number of steps: %d", steps)
+	}
+	return r.withMigrate(func(m *migrate.Migrate) error {
+		return m.Steps(-steps)
+	})
+}
+
+// MigrateTo применяет или откатывает миграции до version.
+func (r *Repo) MigrateTo(version uint) error {
+	return r.withMigrate(func(m *migrate.Migrate) error {
+		return m.Migrate(version)
+	})
+}
+
+// ForceMigrationVersion записывает version без выполнения миграций и снимает флаг dirty —
+// после того как упавшая миграция исправлена вручную. -1 означает, что миграций нет.
+func (r *Repo) ForceMigrationVersion(version int) error {
+	if version < -1 {
+		return fmt.Errorf("invalid version: %d", version)
+	}
+	return r.withMigrate(func(m *migrate.Migrate) error {
+		return m.Force(version)
+	})
+}
+
+// MigrationStatus читает версию из таблицы golang-migrate, не беря блокировку миграций.
+func (r *Repo) MigrationStatus(ctx context.Context) (domain.MigrationStatus, error) {
+	latest, err := latestMigration()
+	if err != nil {
+		return domain.MigrationStatus{}, err
+	}
+	status := domain.MigrationStatus{Latest: latest}
+
+	var table sql.NullString
+	if err := r.db.GetContext(ctx, &table, `SELECT to_regclass('schema_migrations')::text`); err != nil {
+		return domain.MigrationStatus{}, err
+	}
+	if !table.Valid {
+		return status, nil
+	}
+
+	var row struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	err = r.db.GetContext(ctx, &row, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status, nil
+	}
+	if err != nil {
+		return domain.MigrationStatus{}, err
+	}
+	status.Version = uint(row.Version)
+	status.Dirty = row.Dirty
+	return status, nil
+}
+
+func latestMigration() (uint, error) {
+	files, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer files.Close()
+
+	version, err := files.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := files.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
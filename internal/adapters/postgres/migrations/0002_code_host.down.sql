@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS pr_number,
+    DROP COLUMN IF EXISTS repository;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    ADD COLUMN repository TEXT    NULL,
+    ADD COLUMN pr_number  INTEGER NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_username;
+
+DROP TABLE IF EXISTS codeowners;
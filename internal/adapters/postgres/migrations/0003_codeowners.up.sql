@@ -0,0 +1,9 @@
+-- team_name = '' хранит глобальный CODEOWNERS
+CREATE TABLE codeowners
+(
+    team_name  TEXT PRIMARY KEY,
+    content    TEXT                     NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_users_username ON users (username);
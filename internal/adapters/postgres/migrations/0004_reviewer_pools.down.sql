@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_reviewer_pool_members_user;
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS assigned_at,
+    DROP COLUMN IF EXISTS source_name,
+    DROP COLUMN IF EXISTS source;
+
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS reviewer_pool_members;
+DROP TABLE IF EXISTS reviewer_pools;
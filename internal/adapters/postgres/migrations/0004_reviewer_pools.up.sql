@@ -0,0 +1,29 @@
+CREATE TABLE reviewer_pools
+(
+    pool_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE reviewer_pool_members
+(
+    pool_name TEXT NOT NULL REFERENCES reviewer_pools (pool_name) ON DELETE CASCADE,
+    user_id   TEXT NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    PRIMARY KEY (pool_name, user_id)
+);
+
+-- упорядоченный список источников, из которых команда добирает ревьюверов
+CREATE TABLE team_fallbacks
+(
+    team_name   TEXT    NOT NULL REFERENCES teams (team_name) ON DELETE CASCADE,
+    kind        TEXT    NOT NULL CHECK (kind IN ('PARTNER_TEAM', 'POOL')),
+    source_name TEXT    NOT NULL,
+    position    INTEGER NOT NULL,
+    PRIMARY KEY (team_name, kind, source_name)
+);
+
+ALTER TABLE pr_reviewers
+    ADD COLUMN source      TEXT                     NOT NULL DEFAULT 'TEAM'
+        CHECK (source IN ('TEAM', 'CODEOWNERS', 'PARTNER_TEAM', 'POOL')),
+    ADD COLUMN source_name TEXT                     NOT NULL DEFAULT '',
+    ADD COLUMN assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+
+CREATE INDEX idx_reviewer_pool_members_user ON reviewer_pool_members (user_id);
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS max_open_reviews;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS default_max_open_reviews;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_unavailability_pending;
+DROP INDEX IF EXISTS idx_unavailability_user;
+
+DROP TABLE IF EXISTS user_unavailability;
@@ -0,0 +1,15 @@
+CREATE TABLE user_unavailability
+(
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       TEXT                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    kind          TEXT                     NOT NULL CHECK (kind IN ('VACATION', 'ON_CALL', 'SICK_LEAVE', 'OTHER')),
+    starts_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason        TEXT                     NOT NULL DEFAULT '',
+    -- когда открытые ревью пользователя были переназначены при начале отсутствия
+    reassigned_at TIMESTAMP WITH TIME ZONE NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_unavailability_user ON user_unavailability (user_id, ends_at);
+CREATE INDEX idx_unavailability_pending ON user_unavailability (starts_at) WHERE reassigned_at IS NULL;
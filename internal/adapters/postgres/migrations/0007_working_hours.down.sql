@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS working_hours,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,4 @@
+-- timezone: имя из базы IANA, NULL означает UTC
+ALTER TABLE users
+    ADD COLUMN timezone      TEXT  NULL,
+    ADD COLUMN working_hours JSONB NULL;
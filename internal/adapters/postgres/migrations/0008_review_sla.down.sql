@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_assigned_at;
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS reassign_failed_at,
+    DROP COLUMN IF EXISTS reminded_at;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS review_sla_reassign_seconds,
+    DROP COLUMN IF EXISTS review_sla_reminder_seconds;
@@ -0,0 +1,10 @@
+ALTER TABLE teams
+    ADD COLUMN review_sla_reminder_seconds BIGINT NULL CHECK (review_sla_reminder_seconds > 0),
+    ADD COLUMN review_sla_reassign_seconds BIGINT NULL CHECK (review_sla_reassign_seconds > 0);
+
+-- reassign_failed_at: когда переназначение по SLA не нашло кандидата; следующая попытка — через срок переназначения
+ALTER TABLE pr_reviewers
+    ADD COLUMN reminded_at        TIMESTAMP WITH TIME ZONE NULL,
+    ADD COLUMN reassign_failed_at TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX idx_pr_reviewers_assigned_at ON pr_reviewers (assigned_at);
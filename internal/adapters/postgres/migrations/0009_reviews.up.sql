@@ -0,0 +1,11 @@
+CREATE TABLE pr_reviews
+(
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT                     NOT NULL REFERENCES pull_requests (pull_request_id) ON DELETE CASCADE,
+    user_id         TEXT                     NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    verdict         TEXT                     NOT NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    submitted_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pr_reviews_pr ON pr_reviews (pull_request_id, submitted_at);
+CREATE INDEX idx_pr_created_at ON pull_requests (created_at);
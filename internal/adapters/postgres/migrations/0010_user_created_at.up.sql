@@ -0,0 +1,13 @@
+ALTER TABLE users
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- существующие пользователи появились не позже своего первого PR или назначения на ревью;
+-- now() остаётся только тем, у кого их нет
+UPDATE users u
+SET created_at = COALESCE(LEAST((SELECT MIN(pr.created_at) FROM pull_requests pr WHERE pr.author_id = u.user_id),
+                                (SELECT MIN(r.assigned_at) FROM pr_reviewers r WHERE r.user_id = u.user_id)),
+                          now());
+
+ALTER TABLE users
+    ALTER COLUMN created_at SET DEFAULT now(),
+    ALTER COLUMN created_at SET NOT NULL;
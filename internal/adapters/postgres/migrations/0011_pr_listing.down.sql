@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pr_status_created_at;
+DROP INDEX IF EXISTS idx_pr_author_created_at;
+DROP INDEX IF EXISTS idx_pr_merged_at;
+DROP INDEX IF EXISTS idx_pr_created_at;
+
+CREATE INDEX idx_pr_created_at ON pull_requests (created_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pr_created_at;
+
+CREATE INDEX idx_pr_created_at ON pull_requests (created_at, pull_request_id);
+CREATE INDEX idx_pr_merged_at ON pull_requests (merged_at, pull_request_id) WHERE merged_at IS NOT NULL;
+CREATE INDEX idx_pr_author_created_at ON pull_requests (author_id, created_at, pull_request_id);
+CREATE INDEX idx_pr_status_created_at ON pull_requests (status, created_at, pull_request_id);
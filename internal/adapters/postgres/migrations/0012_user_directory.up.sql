@@ -0,0 +1 @@
+CREATE INDEX idx_users_username_prefix ON users (lower(username) text_pattern_ops, user_id);
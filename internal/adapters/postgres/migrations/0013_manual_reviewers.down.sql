@@ -0,0 +1,12 @@
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_reviewer_limits_check,
+    DROP COLUMN IF EXISTS max_reviewers,
+    DROP COLUMN IF EXISTS min_reviewers;
+
+UPDATE pr_reviewers SET source = 'TEAM', source_name = '' WHERE source = 'MANUAL';
+
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT IF EXISTS pr_reviewers_source_check;
+ALTER TABLE pr_reviewers
+    ADD CONSTRAINT pr_reviewers_source_check
+        CHECK (source IN ('TEAM', 'CODEOWNERS', 'PARTNER_TEAM', 'POOL'));
@@ -0,0 +1,10 @@
+ALTER TABLE pr_reviewers
+    DROP CONSTRAINT IF EXISTS pr_reviewers_source_check;
+ALTER TABLE pr_reviewers
+    ADD CONSTRAINT pr_reviewers_source_check
+        CHECK (source IN ('TEAM', 'CODEOWNERS', 'PARTNER_TEAM', 'POOL', 'MANUAL'));
+
+ALTER TABLE teams
+    ADD COLUMN min_reviewers INTEGER NULL CHECK (min_reviewers >= 0),
+    ADD COLUMN max_reviewers INTEGER NULL CHECK (max_reviewers >= 1),
+    ADD CONSTRAINT teams_reviewer_limits_check CHECK (min_reviewers <= max_reviewers);
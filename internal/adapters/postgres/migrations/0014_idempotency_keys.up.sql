@@ -0,0 +1,22 @@
+-- ответы на POST-запросы с заголовком Idempotency-Key; status_code NULL — запрос ещё выполняется.
+-- scope — хеш API-токена клиента, у каждого токена свои ключи. Незавершённая резервация держится
+-- до lease_until и продлевается, пока запрос выполняется; после падения процесса ключ перезанимает
+-- следующий ретрай. owner отличает владельца резервации, headers — заголовки ответа, которые
+-- отдаются при повторе (ETag)
+CREATE TABLE idempotency_keys
+(
+    scope        TEXT                     NOT NULL,
+    key          TEXT                     NOT NULL,
+    fingerprint  TEXT                     NOT NULL,
+    owner        TEXT                     NOT NULL,
+    status_code  INTEGER                  NULL,
+    content_type TEXT                     NULL,
+    headers      JSONB                    NULL,
+    body         BYTEA                    NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    lease_until  TIMESTAMP WITH TIME ZONE NULL,
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
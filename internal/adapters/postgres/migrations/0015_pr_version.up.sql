@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
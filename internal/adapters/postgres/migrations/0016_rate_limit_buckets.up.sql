@@ -0,0 +1,9 @@
+-- общие для всех экземпляров сервиса корзины ограничения частоты запросов;
+-- tat — теоретическое время прихода следующего запроса (GCRA)
+CREATE TABLE rate_limit_buckets
+(
+    key TEXT PRIMARY KEY,
+    tat TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_tat ON rate_limit_buckets (tat);
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ListPRs отдаёт страницу PR с keyset-пагинацией по (поле сортировки, pull_request_id).
+// Размер страницы задаёт filter.Limit.
+func (r *Repo) ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error) {
+	sortColumn := "pr.created_at"
+	if filter.SortBy == domain.SortByMergedAt {
+		sortColumn = "pr.merged_at"
+	}
+
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+
+	if filter.AuthorID != "" {
+		add("pr.author_id = ?", filter.AuthorID)
+	}
+	if filter.ReviewerID != "" {
+		add(`EXISTS (SELECT 1 FROM pr_reviewers f
+		        WHERE f.pull_request_id = pr.pull_request_id AND f.user_id = ?)`, filter.ReviewerID)
+	}
+	if filter.TeamName != "" {
+		add("a.team_name = ?", filter.TeamName)
+	}
+	if filter.Status != "" {
+		add("pr.status = ?", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		add("pr.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("pr.created_at < ?", *filter.CreatedTo)
+	}
+	if filter.MergedFrom != nil {
+		add("pr.merged_at >= ?", *filter.MergedFrom)
+	}
+	if filter.MergedTo != nil {
+		add("pr.merged_at < ?", *filter.MergedTo)
+	}
+	if filter.SortBy == domain.SortByMergedAt {
+		where = append(where, "pr.merged_at IS NOT NULL")
+	}
+
+	direction, cmp := "ASC", ">"
+	if filter.Desc {
+		direction, cmp = "DESC", "<"
+	}
+	if c := filter.Cursor; c != nil {
+		where = append(where, "("+sortColumn+", pr.pull_request_id) "+cmp+" (?, ?)")
+		args = append(args, c.Value, c.PullRequestID)
+	}
+
+	q := `
+		SELECT ` + prColumns + `
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id`
+	if len(where) > 0 {
+		q += "\n\t\tWHERE " + strings.Join(where, "\n\t\t  AND ")
+	}
+	q += "\n\t\tORDER BY " + sortColumn + " " + direction + ", pr.pull_request_id " + direction +
+		"\n\t\tLIMIT ?"
+	args = append(args, filter.Limit)
+
+	var rows []prRow
+	if err := r.read.SelectContext(ctx, &rows, r.read.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	prs := make([]domain.PullRequest, 0, len(rows))
+	for _, row := range rows {
+		prs = append(prs, row.pullRequest())
+	}
+	return prs, nil
+}
+
+// GetPRsByIDs возвращает найденные PR из prIDs; отсутствующие пропускаются.
+func (r *Repo) GetPRsByIDs(ctx context.Context, prIDs []string) ([]domain.PullRequest, error) {
+	if len(prIDs) == 0 {
+		return nil, nil
+	}
+	return r.selectPRsIn(ctx,
+		`SELECT `+prColumns+`
+		 FROM pull_requests pr
+		 WHERE pr.pull_request_id IN (?)
+		 ORDER BY pr.pull_request_id`,
+		prIDs,
+	)
+}
+
+// ListOpenPRsByReviewers возвращает открытые PR, на которые назначен хотя бы один из userIDs.
+func (r *Repo) ListOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return r.selectPRsIn(ctx,
+		`SELECT `+prColumns+`
+		 FROM pull_requests pr
+		 WHERE pr.status = 'OPEN' AND EXISTS (
+		     SELECT 1 FROM pr_reviewers r
+		     WHERE r.pull_request_id = pr.pull_request_id AND r.user_id IN (?)
+		 )
+		 ORDER BY pr.created_at, pr.pull_request_id`,
+		userIDs,
+	)
+}
+
+func (r *Repo) selectPRsIn(ctx context.Context, query string, values []string) ([]domain.PullRequest, error) {
+	query, args, err := sqlx.In(query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []prRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	prs := make([]domain.PullRequest, 0, len(rows))
+	for _, row := range rows {
+		prs = append(prs, row.pullRequest())
+	}
+	return prs, nil
+}
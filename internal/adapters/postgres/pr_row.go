@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"encoding/json"
+	"fmt"
+)
+
+// prColumns выбирает PR (под псевдонимом pr) вместе с командой автора и назначениями
+// с последними вердиктами одним запросом, без отдельного обращения к pr_reviewers на каждый PR.
+const prColumns = `pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
+	pr.created_at, pr.merged_at, pr.repository, pr.pr_number, pr.version,
+	(SELECT au.team_name FROM users au WHERE au.user_id = pr.author_id) AS author_team,
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'user_id', rv.user_id,
+			'source', rv.source,
+			'source_name', rv.source_name,
+			'assigned_at', rv.assigned_at,
+			'verdict', lv.verdict,
+			'reviewed_at', lv.submitted_at
+		) ORDER BY rv.assigned_at, rv.user_id)
+		FROM pr_reviewers rv
+		LEFT JOIN LATERAL (
+			SELECT v.verdict, v.submitted_at FROM pr_reviews v
+			WHERE v.pull_request_id = rv.pull_request_id AND v.user_id = rv.user_id
+			ORDER BY v.submitted_at DESC, v.id DESC
+			LIMIT 1
+		) lv ON true
+		WHERE rv.pull_request_id = pr.pull_request_id
+	), '[]') AS assignments_json`
+
+type assignmentList []domain.ReviewerAssignment
+
+func (a *assignmentList) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into assignments", src)
+	}
+}
+
+type prRow struct {
+	domain.PullRequest
+	AssignmentsJSON assignmentList `db:"assignments_json"`
+}
+
+func (row prRow) pullRequest() domain.PullRequest {
+	pr := row.PullRequest
+	pr.Assignments = []domain.ReviewerAssignment(row.AssignmentsJSON)
+	pr.AssignedReviewers = make([]string, 0, len(pr.Assignments))
+	for _, a := range pr.Assignments {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, a.UserID)
+	}
+	return pr
+}
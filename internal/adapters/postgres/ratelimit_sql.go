@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// TakeRateLimitToken — GCRA из domain.RateLimit.Take одним upsert, чтобы корзину можно было
+// делить между несколькими экземплярами сервиса.
+func (r *Repo) TakeRateLimitToken(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (bool, time.Duration, error) {
+	interval := limit.Interval().Seconds()
+
+	var tat time.Time
+	err := r.db.GetContext(ctx, &tat,
+		`INSERT INTO rate_limit_buckets AS b (key, tat)
+		 VALUES ($1, $2::timestamptz + make_interval(secs => $3))
+		 ON CONFLICT (key) DO UPDATE SET
+		     tat = GREATEST(b.tat, $2::timestamptz) + make_interval(secs => $3)
+		 WHERE GREATEST(b.tat, $2::timestamptz) + make_interval(secs => $3)
+		       <= $2::timestamptz + make_interval(secs => $3 * $4)
+		 RETURNING tat`,
+		key, now, interval, limit.Burst,
+	)
+	if err == nil {
+		return true, 0, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, 0, err
+	}
+
+	// корзина пуста: upsert ничего не изменил, время ожидания считается по текущему tat
+	err = r.db.GetContext(ctx, &tat, `SELECT tat FROM rate_limit_buckets WHERE key = $1`, key)
+	if err != nil {
+		return false, 0, err
+	}
+	_, allowed, retryAfter := limit.Take(tat, now)
+	return allowed, retryAfter, nil
+}
+
+func (r *Repo) PurgeRateLimitBuckets(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE tat <= $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
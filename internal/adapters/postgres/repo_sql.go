@@ -13,8 +13,21 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+const userColumns = `user_id, username, team_name, is_active, max_open_reviews,
+	COALESCE(timezone, '') AS timezone, working_hours`
+
+// reader — чтения, общие для *sqlx.DB и *sqlx.Tx.
+type reader interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	Rebind(query string) string
+}
+
 type Repo struct {
 	db *sqlx.DB
+	// read — через него идут чтения выгрузки: сама база или транзакция ExportSnapshot
+	read reader
+	// dbURL нужен миграциям: им требуется отдельное подключение, которое можно закрыть.
+	dbURL string
 }
 
 func NewPostgresRepo(dbURL string) *Repo {
@@ -23,16 +36,19 @@ func NewPostgresRepo(dbURL string) *Repo {
 		log.Fatalf("connect to db: %v", err)
 	}
 
-	return &Repo{db: db}
+	return &Repo{db: db, read: db, dbURL: dbURL}
 }
 func (r *Repo) Close() error {
 	return r.db.Close()
 }
 
 func (r *Repo) CreateTeam(ctx context.Context, team domain.Team) error {
+	reminder, reassign := slaSeconds(team.ReviewSLA)
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO teams (team_name) VALUES ($1)`,
-		team.TeamName,
+		`INSERT INTO teams (team_name, default_max_open_reviews, review_sla_reminder_seconds, review_sla_reassign_seconds,
+		                    min_reviewers, max_reviewers)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		team.TeamName, team.DefaultMaxOpenReviews, reminder, reassign, team.MinReviewers, team.MaxReviewers,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -49,7 +65,8 @@ func (r *Repo) GetTeam(ctx context.Context, teamName string) (domain.Team, error
 
 	var members []domain.TeamMember
 	err := r.db.SelectContext(ctx, &members,
-		`SELECT user_id, username, is_active 
+		`SELECT user_id, username, is_active, max_open_reviews,
+		        COALESCE(timezone, '') AS timezone, working_hours
 		 FROM users WHERE team_name = $1`,
 		teamName,
 	)
@@ -62,21 +79,208 @@ func (r *Repo) GetTeam(ctx context.Context, teamName string) (domain.Team, error
 	}
 
 	t.Members = members
+
+	var settings struct {
+		DefaultMaxOpenReviews *int   `db:"default_max_open_reviews"`
+		ReminderSeconds       *int64 `db:"review_sla_reminder_seconds"`
+		ReassignSeconds       *int64 `db:"review_sla_reassign_seconds"`
+		MinReviewers          *int   `db:"min_reviewers"`
+		MaxReviewers          *int   `db:"max_reviewers"`
+	}
+	err = r.db.GetContext(ctx, &settings,
+		`SELECT default_max_open_reviews, review_sla_reminder_seconds, review_sla_reassign_seconds,
+		        min_reviewers, max_reviewers
+		 FROM teams WHERE team_name = $1`,
+		teamName,
+	)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	t.DefaultMaxOpenReviews = settings.DefaultMaxOpenReviews
+	t.MinReviewers = settings.MinReviewers
+	t.MaxReviewers = settings.MaxReviewers
+	if settings.ReminderSeconds != nil {
+		t.ReviewSLA = &domain.ReviewSLA{ReminderAfter: time.Duration(*settings.ReminderSeconds) * time.Second}
+		if settings.ReassignSeconds != nil {
+			t.ReviewSLA.ReassignAfter = time.Duration(*settings.ReassignSeconds) * time.Second
+		}
+	}
+
+	var fallbacks []struct {
+		Kind       domain.ReviewerSource `db:"kind"`
+		SourceName string                `db:"source_name"`
+	}
+	err = r.db.SelectContext(ctx, &fallbacks,
+		`SELECT kind, source_name FROM team_fallbacks
+		 WHERE team_name = $1 ORDER BY position`,
+		teamName,
+	)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	for _, f := range fallbacks {
+		if f.Kind == domain.SourcePartnerTeam {
+			t.PartnerTeams = append(t.PartnerTeams, f.SourceName)
+		} else {
+			t.FallbackPools = append(t.FallbackPools, f.SourceName)
+		}
+	}
+
 	return t, nil
 }
 
+func (r *Repo) SetTeamFallback(ctx context.Context, teamName string, partnerTeams, pools []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_name = $1`, teamName)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	position := 0
+	insert := func(kind domain.ReviewerSource, name string) error {
+		position++
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO team_fallbacks (team_name, kind, source_name, position)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT DO NOTHING`,
+			teamName, kind, name, position,
+		)
+		return err
+	}
+	for _, name := range partnerTeams {
+		if err := insert(domain.SourcePartnerTeam, name); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	for _, name := range pools {
+		if err := insert(domain.SourcePool, name); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repo) SetTeamReviewSLA(ctx context.Context, teamName string, sla *domain.ReviewSLA) error {
+	reminder, reassign := slaSeconds(sla)
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE teams SET review_sla_reminder_seconds=$1, review_sla_reassign_seconds=$2
+		 WHERE team_name=$3`,
+		reminder, reassign, teamName,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func slaSeconds(sla *domain.ReviewSLA) (reminder, reassign *int64) {
+	if sla == nil {
+		return nil, nil
+	}
+	rem := int64(sla.ReminderAfter / time.Second)
+	reminder = &rem
+	if sla.ReassignAfter > 0 {
+		re := int64(sla.ReassignAfter / time.Second)
+		reassign = &re
+	}
+	return reminder, reassign
+}
+
+func (r *Repo) SaveReviewerPool(ctx context.Context, pool domain.ReviewerPool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO reviewer_pools (pool_name) VALUES ($1) ON CONFLICT DO NOTHING`,
+		pool.PoolName,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM reviewer_pool_members WHERE pool_name = $1`, pool.PoolName)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, userID := range pool.Members {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO reviewer_pool_members (pool_name, user_id) VALUES ($1, $2)
+			 ON CONFLICT DO NOTHING`,
+			pool.PoolName, userID,
+		)
+		if err != nil {
+			_ = tx.Rollback()
+			if strings.Contains(err.Error(), "foreign key") {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repo) GetReviewerPool(ctx context.Context, poolName string) (domain.ReviewerPool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		`SELECT EXISTS (SELECT 1 FROM reviewer_pools WHERE pool_name = $1)`,
+		poolName,
+	)
+	if err != nil {
+		return domain.ReviewerPool{}, err
+	}
+	if !exists {
+		return domain.ReviewerPool{}, domain.ErrNotFound
+	}
+
+	members := []string{}
+	err = r.db.SelectContext(ctx, &members,
+		`SELECT user_id FROM reviewer_pool_members WHERE pool_name = $1 ORDER BY user_id`,
+		poolName,
+	)
+	if err != nil {
+		return domain.ReviewerPool{}, err
+	}
+
+	return domain.ReviewerPool{PoolName: poolName, Members: members}, nil
+}
+
 func (r *Repo) UpsertUsers(ctx context.Context, users []domain.User) error {
 	if len(users) == 0 {
 		return nil
 	}
 
 	query := `
-	INSERT INTO users (user_id, username, team_name, is_active)
-	VALUES (:user_id, :username, :team_name, :is_active)
+	INSERT INTO users (user_id, username, team_name, is_active, max_open_reviews, timezone, working_hours)
+	VALUES (:user_id, :username, :team_name, :is_active, :max_open_reviews, NULLIF(:timezone, ''), :working_hours)
 	ON CONFLICT (user_id) DO UPDATE SET
 	    username = EXCLUDED.username,
 	    team_name = EXCLUDED.team_name,
-	    is_active = EXCLUDED.is_active`
+	    is_active = EXCLUDED.is_active,
+	    max_open_reviews = COALESCE(EXCLUDED.max_open_reviews, users.max_open_reviews),
+	    timezone = COALESCE(EXCLUDED.timezone, users.timezone),
+	    working_hours = COALESCE(EXCLUDED.working_hours, users.working_hours)`
 
 	q, args, err := sqlx.Named(query, users)
 	if err != nil {
@@ -106,10 +310,96 @@ func (r *Repo) SetUserActive(ctx context.Context, userID string, isActive bool)
 	return r.GetUser(ctx, userID)
 }
 
+func (r *Repo) SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) (domain.User, error) {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET max_open_reviews=$1 WHERE user_id=$2`,
+		limit, userID,
+	)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return r.GetUser(ctx, userID)
+}
+
+func (r *Repo) SetTeamMaxOpenReviews(ctx context.Context, teamName string, limit *int) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE teams SET default_max_open_reviews=$1 WHERE team_name=$2`,
+		limit, teamName,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repo) SetTeamReviewerLimits(ctx context.Context, teamName string, minReviewers, maxReviewers *int) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE teams SET min_reviewers=$1, max_reviewers=$2 WHERE team_name=$3`,
+		minReviewers, maxReviewers, teamName,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repo) GetReviewLoad(ctx context.Context, filter domain.ReviewLoadFilter) ([]domain.UserLoad, error) {
+	baseQuery := `
+	SELECT u.user_id, u.username, u.team_name, u.is_active,
+	       COUNT(pr.pull_request_id) AS open_reviews,
+	       COALESCE(u.max_open_reviews, t.default_max_open_reviews) AS max_open_reviews
+	FROM users u
+	JOIN teams t ON t.team_name = u.team_name
+	LEFT JOIN pr_reviewers r ON r.user_id = u.user_id
+	LEFT JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id AND pr.status = 'OPEN'
+	WHERE true
+	`
+
+	var args []interface{}
+	if filter.TeamName != "" {
+		baseQuery += " AND u.team_name = ?"
+		args = append(args, filter.TeamName)
+	}
+	if len(filter.UserIDs) > 0 {
+		baseQuery += " AND u.user_id IN (?)"
+		args = append(args, filter.UserIDs)
+	}
+	baseQuery += " GROUP BY u.user_id, t.team_name ORDER BY u.team_name, u.user_id"
+
+	query, args, err := sqlx.In(baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var load []domain.UserLoad
+	if err := r.db.SelectContext(ctx, &load, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return load, nil
+}
+
 func (r *Repo) GetUser(ctx context.Context, userID string) (domain.User, error) {
 	var u domain.User
 	err := r.db.GetContext(ctx, &u,
-		`SELECT user_id, username, team_name, is_active
+		`SELECT `+userColumns+`
 		 FROM users WHERE user_id=$1`,
 		userID,
 	)
@@ -125,7 +415,7 @@ func (r *Repo) GetUser(ctx context.Context, userID string) (domain.User, error)
 
 func (r *Repo) ListActiveTeamMembers(ctx context.Context, teamName string, excludeIDs []string, limit int) ([]domain.User, error) {
 	baseQuery := `
-	SELECT user_id, username, team_name, is_active
+	SELECT ` + userColumns + `
 	FROM users
 	WHERE team_name = ? AND is_active = true
 	`
@@ -134,13 +424,15 @@ func (r *Repo) ListActiveTeamMembers(ctx context.Context, teamName string, exclu
 		baseQuery += " AND user_id NOT IN (?)"
 	}
 
-	baseQuery += " LIMIT ?"
-
 	args := []interface{}{teamName}
 	if len(excludeIDs) > 0 {
 		args = append(args, excludeIDs)
 	}
-	args = append(args, limit)
+
+	if limit > 0 {
+		baseQuery += " LIMIT ?"
+		args = append(args, limit)
+	}
 
 	query, args, err := sqlx.In(baseQuery, args...)
 	if err != nil {
@@ -157,51 +449,186 @@ func (r *Repo) ListActiveTeamMembers(ctx context.Context, teamName string, exclu
 	return users, nil
 }
 
-func (r *Repo) CreatePR(ctx context.Context, pr domain.PullRequest, reviewers []string) error {
+func (r *Repo) ListActivePoolMembers(ctx context.Context, poolName string, excludeIDs []string, limit int) ([]domain.User, error) {
+	baseQuery := `
+	SELECT ` + userColumns + `
+	FROM users
+	WHERE user_id IN (SELECT user_id FROM reviewer_pool_members WHERE pool_name = ?)
+	  AND is_active = true
+	`
+
+	args := []interface{}{poolName}
+	if len(excludeIDs) > 0 {
+		baseQuery += " AND user_id NOT IN (?)"
+		args = append(args, excludeIDs)
+	}
+
+	if limit > 0 {
+		baseQuery += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	query, args, err := sqlx.In(baseQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []domain.User
+	if err := r.db.SelectContext(ctx, &users, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *Repo) ListUsersByLogins(ctx context.Context, logins []string) ([]domain.User, error) {
+	if len(logins) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+	SELECT `+userColumns+`
+	FROM users
+	WHERE user_id IN (?) OR username IN (?)
+	ORDER BY user_id`, logins, logins)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []domain.User
+	if err := r.db.SelectContext(ctx, &users, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *Repo) SaveCodeOwners(ctx context.Context, file domain.CodeOwnersFile) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO codeowners (team_name, content, updated_at)
+		 VALUES ($1, $2, now())
+		 ON CONFLICT (team_name) DO UPDATE SET
+		     content = EXCLUDED.content,
+		     updated_at = EXCLUDED.updated_at`,
+		file.TeamName, file.Content,
+	)
+	return err
+}
+
+func (r *Repo) GetCodeOwners(ctx context.Context, teamName string) (domain.CodeOwnersFile, error) {
+	var f domain.CodeOwnersFile
+	err := r.db.GetContext(ctx, &f,
+		`SELECT team_name, content, updated_at FROM codeowners WHERE team_name=$1`,
+		teamName,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.CodeOwnersFile{}, domain.ErrNotFound
+		}
+		return domain.CodeOwnersFile{}, err
+	}
+
+	return f, nil
+}
+
+func (r *Repo) CreatePR(ctx context.Context, pr domain.PullRequest, reviewers []domain.ReviewerAssignment) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.ExecContext(ctx,
+	if err := insertPR(ctx, tx, pr, reviewers); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreatePRs создаёт пачку PR с назначениями (pr.Assignments) в одной транзакции.
+// Каждый PR пишется под своей точкой сохранения, поэтому ошибка одного PR не отменяет
+// остальные: она возвращается в errs под тем же индексом.
+func (r *Repo) CreatePRs(ctx context.Context, prs []domain.PullRequest) ([]error, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	errs := make([]error, len(prs))
+	for i, pr := range prs {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT create_pr`); err != nil {
+			return nil, err
+		}
+		if errs[i] = insertPR(ctx, tx, pr, pr.Assignments); errs[i] != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT create_pr`); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT create_pr`); err != nil {
+			return nil, err
+		}
+	}
+
+	return errs, tx.Commit()
+}
+
+func insertPR(ctx context.Context, tx *sqlx.Tx, pr domain.PullRequest, reviewers []domain.ReviewerAssignment) error {
+	_, err := tx.ExecContext(ctx,
 		`INSERT INTO pull_requests 
-		    (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		    (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, repository, pr_number, version)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 		pr.PullRequestID,
 		pr.PullRequestName,
 		pr.AuthorID,
 		pr.Status,
 		pr.CreatedAt,
 		pr.MergedAt,
+		pr.Repository,
+		pr.PRNumber,
+		pr.Version,
 	)
 	if err != nil {
-		_ = tx.Rollback()
 		if strings.Contains(err.Error(), "duplicate key") {
 			return domain.ErrPrExists
 		}
+		if strings.Contains(err.Error(), "foreign key") {
+			return domain.ErrNotFound
+		}
 		return err
 	}
 
 	for _, reviewer := range reviewers {
+		assignedAt := reviewer.AssignedAt
+		if assignedAt == nil {
+			assignedAt = pr.CreatedAt
+		}
 		_, err = tx.ExecContext(ctx,
-			`INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)`,
-			pr.PullRequestID, reviewer,
+			`INSERT INTO pr_reviewers (pull_request_id, user_id, source, source_name, assigned_at)
+			 VALUES ($1, $2, $3, $4, COALESCE($5, now()))`,
+			pr.PullRequestID, reviewer.UserID, reviewer.Source, reviewer.SourceName, assignedAt,
 		)
 		if err != nil {
-			_ = tx.Rollback()
+			if strings.Contains(err.Error(), "foreign key") {
+				return domain.ErrNotFound
+			}
 			return err
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 func (r *Repo) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
-	var pr domain.PullRequest
+	return getPR(ctx, r.db, prID)
+}
+
+func getPR(ctx context.Context, q sqlx.QueryerContext, prID string) (domain.PullRequest, error) {
+	var row prRow
 
-	err := r.db.GetContext(ctx, &pr,
-		`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-	     FROM pull_requests WHERE pull_request_id=$1`,
+	err := sqlx.GetContext(ctx, q, &row,
+		`SELECT `+prColumns+`
+		 FROM pull_requests pr WHERE pr.pull_request_id=$1`,
 		prID,
 	)
 
@@ -212,63 +639,143 @@ func (r *Repo) GetPR(ctx context.Context, prID string) (domain.PullRequest, erro
 		return domain.PullRequest{}, err
 	}
 
-	var reviewers []string
-	err = r.db.SelectContext(ctx, &reviewers,
-		`SELECT user_id FROM pr_reviewers WHERE pull_request_id=$1`,
+	return row.pullRequest(), nil
+}
+
+// bumpPRVersion увеличивает версию PR внутри транзакции изменения. Ненулевая expectedVersion
+// должна совпасть с текущей, иначе возвращается ErrVersionMismatch; строка PR при этом
+// блокируется до конца транзакции, так что параллельные изменения выполняются по очереди.
+func bumpPRVersion(ctx context.Context, tx *sqlx.Tx, prID string, expectedVersion int64) error {
+	var current int64
+	err := tx.GetContext(ctx, &current,
+		`SELECT version FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`,
 		prID,
 	)
 	if err != nil {
-		return domain.PullRequest{}, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	if expectedVersion != 0 && current != expectedVersion {
+		return domain.ErrVersionMismatch
 	}
 
-	pr.AssignedReviewers = reviewers
-	return pr, nil
+	_, err = tx.ExecContext(ctx,
+		`UPDATE pull_requests SET version = version + 1 WHERE pull_request_id = $1`,
+		prID,
+	)
+	return err
 }
 
-func (r *Repo) UpdatePRStatusMerged(ctx context.Context, prID string, mergedAt *time.Time) (domain.PullRequest, error) {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE pull_requests
-		 SET status='MERGED', merged_at=$1
-		 WHERE pull_request_id=$2`,
-		mergedAt, prID,
-	)
+// updatePR выполняет изменение PR вместе с повышением версии и возвращает PR после изменения.
+func (r *Repo) updatePR(ctx context.Context, prID string, expectedVersion int64, apply func(tx *sqlx.Tx) error) (domain.PullRequest, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	return r.GetPR(ctx, prID)
-}
-
-func (r *Repo) ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) (domain.PullRequest, error) {
-	log.Printf("Replacing reviewer: PR=%s, oldUser=%s, newUser=%s", prID, oldUserID, newUserID)
-
-	res, err := r.db.ExecContext(ctx,
-		`UPDATE pr_reviewers 
-		 SET user_id = $1 
-		 WHERE pull_request_id = $2 AND user_id = $3`,
-		newUserID, prID, oldUserID,
-	)
-	if err != nil {
+	if err := bumpPRVersion(ctx, tx, prID, expectedVersion); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := apply(tx); err != nil {
 		return domain.PullRequest{}, err
 	}
 
-	n, err := res.RowsAffected()
+	pr, err := getPR(ctx, tx, prID)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
-	if n == 0 {
-		return domain.PullRequest{}, domain.ErrNotAssigned
-	}
+	return pr, tx.Commit()
+}
 
-	return r.GetPR(ctx, prID)
+func (r *Repo) UpdatePRStatusMerged(ctx context.Context, prID string, mergedAt *time.Time, expectedVersion int64) (domain.PullRequest, error) {
+	return r.updatePR(ctx, prID, expectedVersion, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE pull_requests
+			 SET status='MERGED', merged_at=$1
+			 WHERE pull_request_id=$2`,
+			mergedAt, prID,
+		)
+		return err
+	})
+}
+
+func (r *Repo) ReplaceReviewer(ctx context.Context, prID, oldUserID string, newReviewer domain.ReviewerAssignment, expectedVersion int64) (domain.PullRequest, error) {
+	log.Printf("Replacing reviewer: PR=%s, oldUser=%s, newUser=%s", prID, oldUserID, newReviewer.UserID)
+
+	return r.updatePR(ctx, prID, expectedVersion, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE pr_reviewers 
+			 SET user_id = $1, source = $2, source_name = $3, assigned_at = COALESCE($4, now()),
+			     reminded_at = NULL, reassign_failed_at = NULL
+			 WHERE pull_request_id = $5 AND user_id = $6`,
+			newReviewer.UserID, newReviewer.Source, newReviewer.SourceName, newReviewer.AssignedAt, prID, oldUserID,
+		)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return domain.ErrAlreadyAssigned
+			}
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domain.ErrNotAssigned
+		}
+		return nil
+	})
+}
+
+func (r *Repo) AddReviewer(ctx context.Context, prID string, reviewer domain.ReviewerAssignment, expectedVersion int64) (domain.PullRequest, error) {
+	return r.updatePR(ctx, prID, expectedVersion, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (pull_request_id, user_id, source, source_name, assigned_at)
+			 VALUES ($1, $2, $3, $4, COALESCE($5, now()))`,
+			prID, reviewer.UserID, reviewer.Source, reviewer.SourceName, reviewer.AssignedAt,
+		)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return domain.ErrAlreadyAssigned
+			}
+			if strings.Contains(err.Error(), "foreign key") {
+				return domain.ErrNotFound
+			}
+		}
+		return err
+	})
+}
+
+func (r *Repo) RemoveReviewer(ctx context.Context, prID, userID string, expectedVersion int64) (domain.PullRequest, error) {
+	return r.updatePR(ctx, prID, expectedVersion, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`,
+			prID, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domain.ErrNotAssigned
+		}
+		return nil
+	})
 }
 
 func (r *Repo) ListPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error) {
-	var prs []domain.PullRequest
+	var rows []prRow
 
-	err := r.db.SelectContext(ctx, &prs,
-		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
-		        pr.created_at, pr.merged_at
+	err := r.db.SelectContext(ctx, &rows,
+		`SELECT `+prColumns+`
 		 FROM pull_requests pr
 		 JOIN pr_reviewers r ON pr.pull_request_id = r.pull_request_id
 		 WHERE r.user_id=$1`,
@@ -279,73 +786,59 @@ func (r *Repo) ListPRsByReviewer(ctx context.Context, userID string) ([]domain.P
 		return nil, err
 	}
 
-	for i := range prs {
-		var reviewers []string
-		err := r.db.SelectContext(ctx, &reviewers,
-			`SELECT user_id FROM pr_reviewers WHERE pull_request_id=$1`,
-			prs[i].PullRequestID,
-		)
-		if err != nil {
-			return nil, err
-		}
-		prs[i].AssignedReviewers = reviewers
+	prs := make([]domain.PullRequest, 0, len(rows))
+	for _, row := range rows {
+		prs = append(prs, row.pullRequest())
 	}
-
 	return prs, nil
 }
 
-func (r *Repo) GetReviewerStats(ctx context.Context) (map[string]int, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT user_id, COUNT(*) AS assignments
-		FROM pr_reviewers
-		GROUP BY user_id
-	`)
+func (r *Repo) ListOverdueAssignments(ctx context.Context, at time.Time) ([]domain.OverdueAssignment, error) {
+	var overdue []domain.OverdueAssignment
+	err := r.db.SelectContext(ctx, &overdue,
+		`SELECT r.pull_request_id, r.user_id, t.team_name, r.assigned_at, r.reminded_at,
+		        t.review_sla_reminder_seconds AS reminder_seconds,
+		        t.review_sla_reassign_seconds AS reassign_seconds
+		 FROM pr_reviewers r
+		 JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+		 JOIN users a ON a.user_id = pr.author_id
+		 JOIN teams t ON t.team_name = a.team_name
+		 WHERE pr.status = 'OPEN'
+		   AND t.review_sla_reminder_seconds IS NOT NULL
+		   AND r.assigned_at <= $1::timestamptz - make_interval(secs => t.review_sla_reminder_seconds)
+		   -- после напоминания назначение нужно только для переназначения, если подошёл его срок
+		   -- и прошлая неудачная попытка была не раньше, чем срок переназначения назад
+		   AND (r.reminded_at IS NULL
+		        OR (t.review_sla_reassign_seconds IS NOT NULL
+		            AND r.assigned_at <= $1::timestamptz - make_interval(secs => t.review_sla_reassign_seconds)
+		            AND (r.reassign_failed_at IS NULL
+		                 OR r.reassign_failed_at <= $1::timestamptz - make_interval(secs => t.review_sla_reassign_seconds))))
+		   AND NOT EXISTS (
+		       SELECT 1 FROM pr_reviews v
+		       WHERE v.pull_request_id = r.pull_request_id AND v.user_id = r.user_id
+		   )
+		 ORDER BY r.assigned_at`,
+		at,
+	)
 	if err != nil {
 		return nil, err
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}(rows)
 
-	stats := make(map[string]int)
-	for rows.Next() {
-		var userID string
-		var count int
-		if err := rows.Scan(&userID, &count); err != nil {
-			return nil, err
-		}
-		stats[userID] = count
-	}
-	return stats, nil
+	return overdue, nil
 }
 
-func (r *Repo) GetPRStats(ctx context.Context) (map[string]int, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT pull_request_id, COUNT(*) AS reviewers
-		FROM pr_reviewers
-		GROUP BY pull_request_id
-	`)
-	if err != nil {
-		return nil, err
-	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			log.Printf("failed to close rows: %v", err)
-		}
-	}(rows)
+func (r *Repo) MarkReviewReminded(ctx context.Context, prID, userID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE pr_reviewers SET reminded_at = $1 WHERE pull_request_id = $2 AND user_id = $3`,
+		at, prID, userID,
+	)
+	return err
+}
 
-	stats := make(map[string]int)
-	for rows.Next() {
-		var prID string
-		var count int
-		if err := rows.Scan(&prID, &count); err != nil {
-			return nil, err
-		}
-		stats[prID] = count
-	}
-	return stats, nil
+func (r *Repo) MarkReassignFailed(ctx context.Context, prID, userID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE pr_reviewers SET reassign_failed_at = $1 WHERE pull_request_id = $2 AND user_id = $3`,
+		at, prID, userID,
+	)
+	return err
 }
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"time"
+)
+
+func (r *Repo) AddReview(ctx context.Context, review domain.Review) (domain.Review, error) {
+	var created domain.Review
+	// вердикт входит в представление PR, поэтому отзыв тоже меняет его версию
+	err := r.db.GetContext(ctx, &created,
+		`WITH bumped AS (
+		     UPDATE pull_requests SET version = version + 1 WHERE pull_request_id = $1
+		 )
+		 INSERT INTO pr_reviews (pull_request_id, user_id, verdict, submitted_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, pull_request_id, user_id, verdict, submitted_at`,
+		review.PullRequestID, review.UserID, review.Verdict, review.SubmittedAt,
+	)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return created, nil
+}
+
+// cycleTimeAggregates ожидает колонки first_review_secs, approval_secs и merge_secs;
+// percentile_cont пропускает NULL, поэтому незавершённые этапы не искажают медиану.
+const cycleTimeAggregates = `
+	COUNT(first_review_secs) AS first_review_count,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY first_review_secs) AS first_review_median,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY first_review_secs) AS first_review_p90,
+	COUNT(approval_secs) AS approval_count,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY approval_secs) AS approval_median,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY approval_secs) AS approval_p90,
+	COUNT(merge_secs) AS merge_count,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY merge_secs) AS merge_median,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY merge_secs) AS merge_p90`
+
+type cycleTimeRow struct {
+	FirstReviewCount  int      `db:"first_review_count"`
+	FirstReviewMedian *float64 `db:"first_review_median"`
+	FirstReviewP90    *float64 `db:"first_review_p90"`
+	ApprovalCount     int      `db:"approval_count"`
+	ApprovalMedian    *float64 `db:"approval_median"`
+	ApprovalP90       *float64 `db:"approval_p90"`
+	MergeCount        int      `db:"merge_count"`
+	MergeMedian       *float64 `db:"merge_median"`
+	MergeP90          *float64 `db:"merge_p90"`
+}
+
+func (c cycleTimeRow) metrics() domain.CycleTimeMetrics {
+	return domain.CycleTimeMetrics{
+		FirstReview: domain.DurationStats{Count: c.FirstReviewCount, Median: c.FirstReviewMedian, P90: c.FirstReviewP90},
+		Approval:    domain.DurationStats{Count: c.ApprovalCount, Median: c.ApprovalMedian, P90: c.ApprovalP90},
+		Merge:       domain.DurationStats{Count: c.MergeCount, Median: c.MergeMedian, P90: c.MergeP90},
+	}
+}
+
+// GetTeamCycleTimes считает длительности от создания PR по команде автора:
+// итог за весь период и понедельный ряд.
+func (r *Repo) GetTeamCycleTimes(ctx context.Context, filter domain.StatsFilter) (map[string]domain.CycleTimeMetrics, map[string][]domain.CycleTimePoint, error) {
+	where, args := statsWhere(filter)
+	q := `
+		WITH prs AS (
+			SELECT a.team_name,
+				date_trunc('week', pr.created_at, 'UTC') AS week,
+				EXTRACT(EPOCH FROM v.first_review_at - pr.created_at) AS first_review_secs,
+				EXTRACT(EPOCH FROM v.approved_at - pr.created_at) AS approval_secs,
+				EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) AS merge_secs
+			FROM pull_requests pr
+			JOIN users a ON a.user_id = pr.author_id
+			LEFT JOIN LATERAL (
+				SELECT MIN(submitted_at) AS first_review_at,
+					MIN(submitted_at) FILTER (WHERE verdict = 'APPROVED') AS approved_at
+				FROM pr_reviews
+				WHERE pull_request_id = pr.pull_request_id
+			) v ON true` + where + `
+		)
+		SELECT team_name, GROUPING(week) = 0 AS weekly, week,` + cycleTimeAggregates + `
+		FROM prs
+		GROUP BY GROUPING SETS ((team_name), (team_name, week))
+		ORDER BY team_name, week`
+
+	var rows []struct {
+		TeamName string     `db:"team_name"`
+		Weekly   bool       `db:"weekly"`
+		Week     *time.Time `db:"week"`
+		cycleTimeRow
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, nil, err
+	}
+
+	totals := make(map[string]domain.CycleTimeMetrics)
+	weekly := make(map[string][]domain.CycleTimePoint)
+	for _, row := range rows {
+		if !row.Weekly {
+			totals[row.TeamName] = row.metrics()
+			continue
+		}
+		weekly[row.TeamName] = append(weekly[row.TeamName], domain.CycleTimePoint{
+			WeekStart:        row.Week.UTC(),
+			CycleTimeMetrics: row.metrics(),
+		})
+	}
+	return totals, weekly, nil
+}
+
+// GetReviewerCycleTimes считает первое ревью и одобрение от момента назначения
+// ревьювера, а мерж — от создания PR, который он ревьюит.
+func (r *Repo) GetReviewerCycleTimes(ctx context.Context, filter domain.StatsFilter) (map[string]domain.CycleTimeMetrics, error) {
+	where, args := statsWhere(filter)
+	q := `
+		WITH assigned AS (
+			SELECT rv.user_id,
+				EXTRACT(EPOCH FROM v.first_review_at - rv.assigned_at) AS first_review_secs,
+				EXTRACT(EPOCH FROM v.approved_at - rv.assigned_at) AS approval_secs,
+				EXTRACT(EPOCH FROM pr.merged_at - pr.created_at) AS merge_secs
+			FROM pr_reviewers rv
+			JOIN pull_requests pr ON pr.pull_request_id = rv.pull_request_id
+			JOIN users a ON a.user_id = pr.author_id
+			LEFT JOIN LATERAL (
+				SELECT MIN(submitted_at) AS first_review_at,
+					MIN(submitted_at) FILTER (WHERE verdict = 'APPROVED') AS approved_at
+				FROM pr_reviews
+				WHERE pull_request_id = rv.pull_request_id AND user_id = rv.user_id
+			) v ON true` + where + `
+		)
+		SELECT user_id,` + cycleTimeAggregates + `
+		FROM assigned
+		GROUP BY user_id`
+
+	var rows []struct {
+		UserID string `db:"user_id"`
+		cycleTimeRow
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]domain.CycleTimeMetrics, len(rows))
+	for _, row := range rows {
+		metrics[row.UserID] = row.metrics()
+	}
+	return metrics, nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"strings"
+	"time"
+)
+
+// statsWhere строит фильтр статистики по периоду создания PR, команде автора и статусу.
+// Запросы должны обращаться к PR как pr, а к автору как a.
+func statsWhere(filter domain.StatsFilter) (string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if filter.From != nil {
+		where = append(where, "pr.created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		where = append(where, "pr.created_at < ?")
+		args = append(args, *filter.To)
+	}
+	if filter.TeamName != "" {
+		where = append(where, "a.team_name = ?")
+		args = append(args, filter.TeamName)
+	}
+	if filter.Status != "" {
+		where = append(where, "pr.status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if len(where) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(where, " AND "), args
+}
+
+func (r *Repo) GetReviewerStats(ctx context.Context, filter domain.StatsFilter) (map[string]domain.AssignmentCount, error) {
+	where, args := statsWhere(filter)
+	q := `
+		SELECT rv.user_id,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open
+		FROM pr_reviewers rv
+		JOIN pull_requests pr ON pr.pull_request_id = rv.pull_request_id
+		JOIN users a ON a.user_id = pr.author_id` + where + `
+		GROUP BY rv.user_id`
+
+	var rows []struct {
+		UserID string `db:"user_id"`
+		domain.AssignmentCount
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]domain.AssignmentCount, len(rows))
+	for _, row := range rows {
+		stats[row.UserID] = row.AssignmentCount
+	}
+	return stats, nil
+}
+
+func (r *Repo) GetPRStats(ctx context.Context, filter domain.StatsFilter) (map[string]int, error) {
+	where, args := statsWhere(filter)
+	q := `
+		SELECT pr.pull_request_id, COUNT(*) AS reviewers
+		FROM pr_reviewers rv
+		JOIN pull_requests pr ON pr.pull_request_id = rv.pull_request_id
+		JOIN users a ON a.user_id = pr.author_id` + where + `
+		GROUP BY pr.pull_request_id`
+
+	var rows []struct {
+		PullRequestID string `db:"pull_request_id"`
+		Reviewers     int    `db:"reviewers"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]int, len(rows))
+	for _, row := range rows {
+		stats[row.PullRequestID] = row.Reviewers
+	}
+	return stats, nil
+}
+
+// GetTeamStats группирует PR и назначения по команде автора.
+func (r *Repo) GetTeamStats(ctx context.Context, filter domain.StatsFilter) (map[string]domain.TeamStats, error) {
+	where, args := statsWhere(filter)
+	q := `
+		SELECT a.team_name,
+			COUNT(DISTINCT pr.pull_request_id) AS pull_requests,
+			COUNT(DISTINCT pr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') AS open_pull_requests,
+			COUNT(DISTINCT pr.pull_request_id) FILTER (WHERE pr.status = 'MERGED') AS merged_pull_requests,
+			COUNT(rv.user_id) AS assignments,
+			COUNT(rv.user_id) FILTER (WHERE pr.status = 'OPEN') AS open_assignments,
+			COUNT(DISTINCT rv.user_id) AS reviewers
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id
+		LEFT JOIN pr_reviewers rv ON rv.pull_request_id = pr.pull_request_id` + where + `
+		GROUP BY a.team_name`
+
+	var rows []struct {
+		TeamName string `db:"team_name"`
+		domain.TeamStats
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]domain.TeamStats, len(rows))
+	for _, row := range rows {
+		stats[row.TeamName] = row.TeamStats
+	}
+	return stats, nil
+}
+
+// ListReviewerActivity возвращает активных пользователей с числом назначений
+// за [from, to) и днями, когда они могли ревьюить. Пересекающиеся периоды
+// отсутствия объединяются, поэтому не вычитаются дважды.
+func (r *Repo) ListReviewerActivity(ctx context.Context, from, to time.Time, teamName string) ([]domain.ReviewerActivity, error) {
+	var activity []domain.ReviewerActivity
+	err := r.db.SelectContext(ctx, &activity,
+		`SELECT u.user_id, u.team_name,
+		        (SELECT COUNT(*) FROM pr_reviewers rv
+		         WHERE rv.user_id = u.user_id AND rv.assigned_at >= $1 AND rv.assigned_at < $2) AS assignments,
+		        (SELECT COALESCE(SUM(EXTRACT(EPOCH FROM upper(p) - lower(p))), 0) / 86400
+		         FROM unnest(
+		             tstzmultirange(tstzrange(LEAST(GREATEST($1::timestamptz, u.created_at), $2::timestamptz), $2::timestamptz))
+		             - COALESCE((SELECT range_agg(tstzrange(ua.starts_at, ua.ends_at))
+		                         FROM user_unavailability ua WHERE ua.user_id = u.user_id),
+		                        '{}'::tstzmultirange)
+		         ) AS p) AS active_days
+		 FROM users u
+		 WHERE u.is_active AND ($3 = '' OR u.team_name = $3)
+		 ORDER BY u.team_name, u.user_id`,
+		from, to, teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
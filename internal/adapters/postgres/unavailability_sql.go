@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const unavailabilityColumns = `id, user_id, kind, starts_at, ends_at, reason, reassigned_at`
+
+func (r *Repo) AddUnavailability(ctx context.Context, u domain.Unavailability) (domain.Unavailability, error) {
+	var created domain.Unavailability
+	err := r.db.GetContext(ctx, &created,
+		`INSERT INTO user_unavailability (user_id, kind, starts_at, ends_at, reason)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+unavailabilityColumns,
+		u.UserID, u.Kind, u.StartsAt, u.EndsAt, u.Reason,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			return domain.Unavailability{}, domain.ErrNotFound
+		}
+		return domain.Unavailability{}, err
+	}
+
+	return created, nil
+}
+
+func (r *Repo) DeleteUnavailability(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM user_unavailability WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repo) ListUnavailability(ctx context.Context, userID string, endsAfter time.Time) ([]domain.Unavailability, error) {
+	windows := []domain.Unavailability{}
+	err := r.db.SelectContext(ctx, &windows,
+		`SELECT `+unavailabilityColumns+`
+		 FROM user_unavailability
+		 WHERE user_id = $1 AND ends_at > $2
+		 ORDER BY starts_at`,
+		userID, endsAfter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+func (r *Repo) ListUnavailableAt(ctx context.Context, userIDs []string, at time.Time) ([]domain.Unavailability, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+	SELECT `+unavailabilityColumns+`
+	FROM user_unavailability
+	WHERE user_id IN (?) AND starts_at <= ? AND ends_at > ?
+	ORDER BY user_id, ends_at`, userIDs, at, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var windows []domain.Unavailability
+	if err := r.db.SelectContext(ctx, &windows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+// ListUnavailabilityByUsers возвращает все периоды пользователей userIDs, включая прошедшие.
+func (r *Repo) ListUnavailabilityByUsers(ctx context.Context, userIDs []string) ([]domain.Unavailability, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+	SELECT `+unavailabilityColumns+`
+	FROM user_unavailability
+	WHERE user_id IN (?)
+	ORDER BY user_id, starts_at`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var windows []domain.Unavailability
+	if err := r.read.SelectContext(ctx, &windows, r.read.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+func (r *Repo) ListStartedUnavailability(ctx context.Context, at time.Time) ([]domain.Unavailability, error) {
+	var windows []domain.Unavailability
+	err := r.db.SelectContext(ctx, &windows,
+		`SELECT `+unavailabilityColumns+`
+		 FROM user_unavailability
+		 WHERE reassigned_at IS NULL AND starts_at <= $1 AND ends_at > $1
+		 ORDER BY starts_at`,
+		at,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+func (r *Repo) MarkUnavailabilityReassigned(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE user_unavailability SET reassigned_at = $1 WHERE id = $2`,
+		at, id,
+	)
+	return err
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (r *Repo) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
+	var created domain.User
+	err := r.db.GetContext(ctx, &created,
+		`INSERT INTO users (user_id, username, team_name, is_active, max_open_reviews, timezone, working_hours)
+		 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		 RETURNING `+userColumns,
+		user.UserID, user.Username, user.TeamName, user.IsActive, user.MaxOpenReviews, user.Timezone, user.WorkingHours,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return domain.User{}, domain.ErrUserExists
+		}
+		if strings.Contains(err.Error(), "foreign key") {
+			return domain.User{}, domain.ErrTeamNotFound
+		}
+		return domain.User{}, err
+	}
+	return created, nil
+}
+
+func (r *Repo) UpdateUser(ctx context.Context, userID string, update domain.UserUpdate) (domain.User, error) {
+	var updated domain.User
+	err := r.db.GetContext(ctx, &updated,
+		`UPDATE users SET
+		     username = COALESCE($2, username),
+		     team_name = COALESCE($3, team_name),
+		     is_active = COALESCE($4, is_active),
+		     max_open_reviews = COALESCE($5, max_open_reviews),
+		     timezone = CASE WHEN $6::text IS NULL THEN timezone ELSE NULLIF($6, '') END,
+		     working_hours = COALESCE($7::jsonb, working_hours)
+		 WHERE user_id = $1
+		 RETURNING `+userColumns,
+		userID, update.Username, update.TeamName, update.IsActive, update.MaxOpenReviews, update.Timezone, update.WorkingHours,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.User{}, domain.ErrNotFound
+		}
+		if strings.Contains(err.Error(), "foreign key") {
+			return domain.User{}, domain.ErrTeamNotFound
+		}
+		return domain.User{}, err
+	}
+	return updated, nil
+}
+
+func (r *Repo) ListUsers(ctx context.Context, filter domain.UserListFilter) ([]domain.User, error) {
+	var where []string
+	var args []interface{}
+
+	if filter.TeamName != "" {
+		where = append(where, "team_name = ?")
+		args = append(args, filter.TeamName)
+	}
+	if filter.UsernamePrefix != "" {
+		where = append(where, `lower(username) LIKE ? ESCAPE '\'`)
+		args = append(args, likePrefix(strings.ToLower(filter.UsernamePrefix)))
+	}
+	if filter.IsActive != nil {
+		where = append(where, "is_active = ?")
+		args = append(args, *filter.IsActive)
+	}
+	if filter.AfterUserID != "" {
+		where = append(where, "user_id > ?")
+		args = append(args, filter.AfterUserID)
+	}
+
+	q := `SELECT ` + userColumns + ` FROM users`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY user_id LIMIT ?"
+	args = append(args, filter.Limit)
+
+	var users []domain.User
+	if err := r.read.SelectContext(ctx, &users, r.read.Rebind(q), args...); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUsersByIDs возвращает найденных пользователей из userIDs; отсутствующие пропускаются.
+func (r *Repo) GetUsersByIDs(ctx context.Context, userIDs []string) ([]domain.User, error) {
+	return r.selectUsersIn(ctx, "user_id", userIDs)
+}
+
+// ListUsersByTeams возвращает участников команд teamNames.
+func (r *Repo) ListUsersByTeams(ctx context.Context, teamNames []string) ([]domain.User, error) {
+	return r.selectUsersIn(ctx, "team_name", teamNames)
+}
+
+func (r *Repo) selectUsersIn(ctx context.Context, column string, values []string) ([]domain.User, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(
+		`SELECT `+userColumns+` FROM users WHERE `+column+` IN (?) ORDER BY user_id`,
+		values,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []domain.User
+	if err := r.db.SelectContext(ctx, &users, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// likePrefix экранирует спецсимволы LIKE, чтобы префикс сравнивался буквально.
+func likePrefix(prefix string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(prefix) + "%"
+}
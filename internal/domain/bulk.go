@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// BulkPRItem — PR для массовой загрузки, например открытых PR команды при подключении.
+// Если Reviewers заданы, они назначаются как есть (источник MANUAL) вместо автоподбора.
+type BulkPRItem struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	Repository      *string    `json:"repository,omitempty"`
+	PRNumber        *int       `json:"pr_number,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	Reviewers       []string   `json:"reviewers,omitempty"`
+	ChangedFiles    []string   `json:"changed_files,omitempty"`
+}
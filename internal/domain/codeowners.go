@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type CodeOwnersFile struct {
+	TeamName  string     `db:"team_name" json:"team_name,omitempty"`
+	Content   string     `db:"content" json:"content"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+type CodeOwnersRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	re      *regexp.Regexp
+}
+
+type CodeOwners struct {
+	Rules []CodeOwnersRule `json:"rules"`
+}
+
+// ParseCodeOwners разбирает файл CODEOWNERS по правилам GitHub:
+// шаблоны в стиле gitignore без "!" и "[ ]", побеждает последнее совпавшее правило.
+func ParseCodeOwners(content string) (CodeOwners, error) {
+	var co CodeOwners
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		fields := strings.Fields(line)
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, `\#`) {
+			pattern = pattern[1:]
+		}
+		if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") {
+			return CodeOwners{}, fmt.Errorf("%w: line %d: unsupported pattern %q", ErrInvalidCodeOwners, lineNo, pattern)
+		}
+
+		owners := fields[1:]
+		for _, o := range owners {
+			if !strings.Contains(o, "@") {
+				return CodeOwners{}, fmt.Errorf("%w: line %d: invalid owner %q", ErrInvalidCodeOwners, lineNo, o)
+			}
+		}
+
+		re, err := compileCodeOwnersPattern(pattern)
+		if err != nil {
+			return CodeOwners{}, fmt.Errorf("%w: line %d: %v", ErrInvalidCodeOwners, lineNo, err)
+		}
+		co.Rules = append(co.Rules, CodeOwnersRule{Pattern: pattern, Owners: owners, re: re})
+	}
+	if err := scanner.Err(); err != nil {
+		return CodeOwners{}, err
+	}
+
+	return co, nil
+}
+
+// Owners возвращает владельцев файла; правило без владельцев снимает владение.
+func (c CodeOwners) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].re.MatchString(path) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+func compileCodeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if seg == "**" {
+			if last {
+				b.WriteString(".*")
+			} else {
+				b.WriteString("(?:.*/)?")
+			}
+			continue
+		}
+		for _, ch := range seg {
+			switch ch {
+			case '*':
+				b.WriteString("[^/]*")
+			case '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(ch)))
+			}
+		}
+		if !last {
+			b.WriteString("/")
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*")
+	case strings.HasSuffix(p, "/*"), strings.HasSuffix(p, "**"):
+		// "docs/*" не захватывает вложенные каталоги, "**" уже покрывает всё
+	default:
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
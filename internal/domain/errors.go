@@ -9,4 +9,33 @@ var (
 	ErrNotAssigned = errors.New("NOT_ASSIGNED")
 	ErrNoCandidate = errors.New("NO_CANDIDATE")
 	ErrNotFound    = errors.New("NOT_FOUND")
+	ErrUserExists  = errors.New("USER_EXISTS")
+	// ErrTeamNotFound отличает отсутствующую команду от отсутствующего пользователя там,
+	// где возможны оба случая.
+	ErrTeamNotFound = errors.New("TEAM_NOT_FOUND")
+
+	ErrAlreadyAssigned  = errors.New("ALREADY_ASSIGNED")
+	ErrNotTeamMember    = errors.New("NOT_TEAM_MEMBER")
+	ErrReviewerIsAuthor = errors.New("REVIEWER_IS_AUTHOR")
+	ErrUserInactive     = errors.New("USER_INACTIVE")
+	ErrReviewerLimit    = errors.New("REVIEWER_LIMIT")
+	// ErrVersionMismatch — PR изменился после того, как клиент получил его версию.
+	ErrVersionMismatch = errors.New("PR_VERSION_MISMATCH")
+	// ErrConcurrentUpdate — PR без If-Match так и не удалось изменить: его всё время меняли параллельно.
+	ErrConcurrentUpdate = errors.New("PR_CONCURRENT_UPDATE")
+	// ErrImportConflict — импортируемая запись уже есть, а политика конфликтов — fail.
+	ErrImportConflict = errors.New("IMPORT_CONFLICT")
+
+	ErrInvalidCodeOwners     = errors.New("INVALID_CODEOWNERS")
+	ErrInvalidUnavailability = errors.New("INVALID_UNAVAILABILITY")
+	ErrInvalidSchedule       = errors.New("INVALID_SCHEDULE")
+	ErrInvalidSLA            = errors.New("INVALID_SLA")
+	ErrInvalidStatsFilter    = errors.New("INVALID_STATS_FILTER")
+	ErrInvalidVerdict        = errors.New("INVALID_VERDICT")
+	ErrInvalidCursor         = errors.New("INVALID_CURSOR")
+	ErrInvalidPRFilter       = errors.New("INVALID_PR_FILTER")
+	ErrInvalidUser           = errors.New("INVALID_USER")
+	ErrInvalidLimits         = errors.New("INVALID_REVIEWER_LIMITS")
+	ErrInvalidBulkInput      = errors.New("INVALID_BULK_INPUT")
+	ErrInvalidExport         = errors.New("INVALID_EXPORT")
 )
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventReviewReminder  EventType = "REVIEW_REMINDER"
+	EventReviewEscalated EventType = "REVIEW_ESCALATED"
+
+	// События назначений: UserID — назначенный или снятый ревьювер,
+	// ReplacedBy — его замена при переназначении.
+	EventReviewerAssigned   EventType = "REVIEWER_ASSIGNED"
+	EventReviewerReassigned EventType = "REVIEWER_REASSIGNED"
+	EventReviewerRemoved    EventType = "REVIEWER_REMOVED"
+)
+
+func (t EventType) IsAssignment() bool {
+	return t == EventReviewerAssigned || t == EventReviewerReassigned || t == EventReviewerRemoved
+}
+
+type Event struct {
+	Type          EventType `json:"type"`
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	TeamName      string    `json:"team_name,omitempty"`
+	ReplacedBy    string    `json:"replaced_by,omitempty"`
+	At            time.Time `json:"at"`
+}
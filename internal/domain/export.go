@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// ExportFormatVersion — версия формата выгрузки. Импорт принимает выгрузки
+// этой и более ранних версий.
+const ExportFormatVersion = 1
+
+type ExportKind string
+
+const (
+	ExportHeader         ExportKind = "header"
+	ExportTeam           ExportKind = "team"
+	ExportUser           ExportKind = "user"
+	ExportPool           ExportKind = "pool"
+	ExportCodeOwners     ExportKind = "codeowners"
+	ExportUnavailability ExportKind = "unavailability"
+	ExportPullRequest    ExportKind = "pull_request"
+)
+
+// ExportRecord — одна строка выгрузки в NDJSON. Первой идёт запись header с версией
+// формата, затем записи в порядке зависимостей: команды, пользователи, пулы, CODEOWNERS,
+// периоды отсутствия и PR вместе с назначениями и историей отзывов.
+// Участники команд выгружаются отдельными записями user.
+type ExportRecord struct {
+	Kind ExportKind `json:"kind"`
+
+	Version    int        `json:"version,omitempty"`
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+
+	Team           *Team           `json:"team,omitempty"`
+	User           *User           `json:"user,omitempty"`
+	Pool           *ReviewerPool   `json:"pool,omitempty"`
+	CodeOwners     *CodeOwnersFile `json:"codeowners,omitempty"`
+	Unavailability *Unavailability `json:"unavailability,omitempty"`
+	PullRequest    *PullRequest    `json:"pull_request,omitempty"`
+	Reviews        []Review        `json:"reviews,omitempty"`
+
+	// Служебные времена, которых нет в ответах API: когда появился пользователь
+	// (от этого считается отчёт о справедливости) и когда ревьюверам PR напомнили по SLA.
+	UserCreatedAt *time.Time           `json:"user_created_at,omitempty"`
+	RemindedAt    map[string]time.Time `json:"reminded_at,omitempty"`
+}
+
+// ConflictPolicy определяет, что делать при импорте записи, которая уже есть в базе.
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	ConflictFail      ConflictPolicy = "fail"
+)
+
+func (p ConflictPolicy) Valid() bool {
+	return p == ConflictSkip || p == ConflictOverwrite || p == ConflictFail
+}
+
+type ImportCounts struct {
+	Created     int `json:"created"`
+	Overwritten int `json:"overwritten"`
+	Skipped     int `json:"skipped"`
+}
+
+// ImportStats — итоги импорта по видам записей.
+type ImportStats map[ExportKind]*ImportCounts
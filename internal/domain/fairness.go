@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// FairnessOutlierZ — на сколько стандартных отклонений темп ревьювера должен
+// отличаться от среднего по команде, чтобы попасть в выбросы.
+const FairnessOutlierZ = 1.5
+
+// ReviewerActivity — назначения ревьювера за период и число дней, когда он
+// мог ревьюить: с момента появления в системе, за вычетом периодов отсутствия.
+type ReviewerActivity struct {
+	UserID      string  `db:"user_id" json:"user_id"`
+	TeamName    string  `db:"team_name" json:"-"`
+	Assignments int     `db:"assignments" json:"assignments"`
+	ActiveDays  float64 `db:"active_days" json:"active_days"`
+}
+
+// Rate — назначений в активный день.
+func (a ReviewerActivity) Rate() float64 {
+	if a.ActiveDays <= 0 {
+		return 0
+	}
+	return float64(a.Assignments) / a.ActiveDays
+}
+
+type FairnessOutlier struct {
+	ReviewerActivity
+	Rate   float64 `json:"rate"`
+	ZScore float64 `json:"z_score"`
+}
+
+// TeamFairness описывает распределение темпа назначений внутри команды.
+// Gini и MaxMinRatio считаются по темпу, MaxMinRatio пуст, если у кого-то ноль.
+type TeamFairness struct {
+	Reviewers   int               `json:"reviewers"`
+	Assignments int               `json:"assignments"`
+	Gini        float64           `json:"gini"`
+	MaxMinRatio *float64          `json:"max_min_ratio"`
+	MeanRate    float64           `json:"mean_rate"`
+	StdDevRate  float64           `json:"std_dev_rate"`
+	Outliers    []FairnessOutlier `json:"outliers"`
+}
+
+type FairnessReport struct {
+	From  time.Time               `json:"from"`
+	To    time.Time               `json:"to"`
+	Teams map[string]TeamFairness `json:"teams"`
+}
+
+// NewTeamFairness считает метрики по ревьюверам команды; пользователи
+// без активных дней в периоде не учитываются.
+func NewTeamFairness(activity []ReviewerActivity) TeamFairness {
+	tf := TeamFairness{Outliers: []FairnessOutlier{}}
+
+	var rates []float64
+	var members []ReviewerActivity
+	for _, a := range activity {
+		if a.ActiveDays <= 0 {
+			continue
+		}
+		members = append(members, a)
+		rates = append(rates, a.Rate())
+		tf.Assignments += a.Assignments
+	}
+	tf.Reviewers = len(members)
+	if len(members) == 0 {
+		return tf
+	}
+
+	var sum float64
+	for _, r := range rates {
+		sum += r
+	}
+	tf.MeanRate = sum / float64(len(rates))
+
+	var variance float64
+	for _, r := range rates {
+		variance += (r - tf.MeanRate) * (r - tf.MeanRate)
+	}
+	tf.StdDevRate = math.Sqrt(variance / float64(len(rates)))
+
+	sorted := slices.Clone(rates)
+	slices.Sort(sorted)
+	if sum > 0 {
+		var weighted float64
+		n := float64(len(sorted))
+		for i, r := range sorted {
+			weighted += (2*float64(i+1) - n - 1) * r
+		}
+		tf.Gini = weighted / (n * sum)
+	}
+	if lo := sorted[0]; lo > 0 {
+		ratio := sorted[len(sorted)-1] / lo
+		tf.MaxMinRatio = &ratio
+	}
+
+	if tf.StdDevRate > 0 {
+		for i, a := range members {
+			z := (rates[i] - tf.MeanRate) / tf.StdDevRate
+			if math.Abs(z) >= FairnessOutlierZ {
+				tf.Outliers = append(tf.Outliers, FairnessOutlier{ReviewerActivity: a, Rate: rates[i], ZScore: z})
+			}
+		}
+	}
+
+	return tf
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// IdempotencyRecord — сохранённый ответ на запрос с ключом идемпотентности.
+// StatusCode пуст, пока первый запрос с этим ключом ещё выполняется.
+type IdempotencyRecord struct {
+	Key         string          `db:"key"`
+	Fingerprint string          `db:"fingerprint"`
+	StatusCode  *int            `db:"status_code"`
+	ContentType *string         `db:"content_type"`
+	Headers     ReplayedHeaders `db:"headers"`
+	Body        []byte          `db:"body"`
+	ExpiresAt   time.Time       `db:"expires_at"`
+}
+
+// ReplayedHeaders — заголовки ответа, которые отдаются при повторе вместе с телом.
+type ReplayedHeaders map[string]string
+
+func (h ReplayedHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (h *ReplayedHeaders) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, h)
+	case string:
+		return json.Unmarshal([]byte(v), h)
+	default:
+		return fmt.Errorf("cannot scan %T into ReplayedHeaders", src)
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+// MigrationStatus — состояние схемы базы: применённая версия и последняя версия,
+// встроенная в сервис. Dirty означает, что миграция Version упала на середине.
+type MigrationStatus struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+	Latest  uint `json:"latest"`
+}
+
+func (s MigrationStatus) UpToDate() bool {
+	return !s.Dirty && s.Version == s.Latest
+}
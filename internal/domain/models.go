@@ -3,21 +3,109 @@ package domain
 import "time"
 
 type TeamMember struct {
-	UserID   string `db:"user_id" json:"user_id"`
-	Username string `db:"username" json:"username"`
-	IsActive bool   `db:"is_active" json:"is_active"`
+	UserID         string `db:"user_id" json:"user_id"`
+	Username       string `db:"username" json:"username"`
+	IsActive       bool   `db:"is_active" json:"is_active"`
+	MaxOpenReviews *int   `db:"max_open_reviews" json:"max_open_reviews,omitempty"`
+
+	Timezone     string        `db:"timezone" json:"timezone,omitempty"`
+	WorkingHours *WorkingHours `db:"working_hours" json:"working_hours,omitempty"`
+
+	IsAvailable      bool       `json:"is_available"`
+	UnavailableUntil *time.Time `json:"unavailable_until,omitempty"`
 }
 
 type Team struct {
-	TeamName string       `db:"team_name" json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName      string       `db:"team_name" json:"team_name"`
+	Members       []TeamMember `json:"members"`
+	PartnerTeams  []string     `json:"partner_teams,omitempty"`
+	FallbackPools []string     `json:"fallback_pools,omitempty"`
+
+	DefaultMaxOpenReviews *int       `db:"default_max_open_reviews" json:"default_max_open_reviews,omitempty"`
+	ReviewSLA             *ReviewSLA `json:"review_sla,omitempty"`
+
+	// MinReviewers и MaxReviewers ограничивают число ревьюверов на PR авторов команды.
+	MinReviewers *int `db:"min_reviewers" json:"min_reviewers,omitempty"`
+	MaxReviewers *int `db:"max_reviewers" json:"max_reviewers,omitempty"`
+}
+
+// DefaultReviewers — сколько ревьюверов назначается на новый PR автоматически.
+const DefaultReviewers = 2
+
+// ReviewerBounds возвращает допустимое число ревьюверов на PR; upper < 0 — без ограничения.
+func (t Team) ReviewerBounds() (lower, upper int) {
+	lower, upper = 0, -1
+	if t.MinReviewers != nil {
+		lower = *t.MinReviewers
+	}
+	if t.MaxReviewers != nil {
+		upper = *t.MaxReviewers
+	}
+	return lower, upper
+}
+
+// ReviewerPool — общий пул ревьюверов, из которого команды добирают кандидатов.
+type ReviewerPool struct {
+	PoolName string   `db:"pool_name" json:"pool_name"`
+	Members  []string `json:"members"`
 }
 
 type User struct {
-	UserID   string `db:"user_id" json:"user_id"`
-	Username string `db:"username" json:"username"`
-	TeamName string `db:"team_name" json:"team_name"`
-	IsActive bool   `db:"is_active" json:"is_active"`
+	UserID         string `db:"user_id" json:"user_id"`
+	Username       string `db:"username" json:"username"`
+	TeamName       string `db:"team_name" json:"team_name"`
+	IsActive       bool   `db:"is_active" json:"is_active"`
+	MaxOpenReviews *int   `db:"max_open_reviews" json:"max_open_reviews,omitempty"`
+
+	Timezone     string        `db:"timezone" json:"timezone,omitempty"`
+	WorkingHours *WorkingHours `db:"working_hours" json:"working_hours,omitempty"`
+}
+
+// UserLoad — текущая нагрузка ревьювера; MaxOpenReviews учитывает значение по умолчанию команды.
+type UserLoad struct {
+	UserID         string `db:"user_id" json:"user_id"`
+	Username       string `db:"username" json:"username"`
+	TeamName       string `db:"team_name" json:"team_name"`
+	IsActive       bool   `db:"is_active" json:"is_active"`
+	OpenReviews    int    `db:"open_reviews" json:"open_reviews"`
+	MaxOpenReviews *int   `db:"max_open_reviews" json:"max_open_reviews"`
+}
+
+func (l UserLoad) HasCapacity() bool {
+	return l.MaxOpenReviews == nil || l.OpenReviews < *l.MaxOpenReviews
+}
+
+type UnavailabilityKind string
+
+const (
+	UnavailabilityVacation  UnavailabilityKind = "VACATION"
+	UnavailabilityOnCall    UnavailabilityKind = "ON_CALL"
+	UnavailabilitySickLeave UnavailabilityKind = "SICK_LEAVE"
+	UnavailabilityOther     UnavailabilityKind = "OTHER"
+)
+
+func (k UnavailabilityKind) Valid() bool {
+	switch k {
+	case UnavailabilityVacation, UnavailabilityOnCall, UnavailabilitySickLeave, UnavailabilityOther:
+		return true
+	}
+	return false
+}
+
+// Unavailability — период, когда пользователь не может ревьюить (отпуск, дежурство, больничный).
+type Unavailability struct {
+	ID           int64              `db:"id" json:"id"`
+	UserID       string             `db:"user_id" json:"user_id"`
+	Kind         UnavailabilityKind `db:"kind" json:"kind"`
+	StartsAt     time.Time          `db:"starts_at" json:"starts_at"`
+	EndsAt       time.Time          `db:"ends_at" json:"ends_at"`
+	Reason       string             `db:"reason" json:"reason,omitempty"`
+	ReassignedAt *time.Time         `db:"reassigned_at" json:"reassigned_at,omitempty"`
+}
+
+type ReviewLoadFilter struct {
+	TeamName string
+	UserIDs  []string
 }
 
 type PullRequestStatus string
@@ -27,14 +115,41 @@ const (
 	StatusMerged PullRequestStatus = "MERGED"
 )
 
+type ReviewerSource string
+
+const (
+	SourceTeam        ReviewerSource = "TEAM"
+	SourceCodeOwners  ReviewerSource = "CODEOWNERS"
+	SourcePartnerTeam ReviewerSource = "PARTNER_TEAM"
+	SourcePool        ReviewerSource = "POOL"
+	SourceManual      ReviewerSource = "MANUAL"
+)
+
+type ReviewerAssignment struct {
+	UserID     string         `db:"user_id" json:"user_id"`
+	Source     ReviewerSource `db:"source" json:"source"`
+	SourceName string         `db:"source_name" json:"source_name,omitempty"`
+	AssignedAt *time.Time     `db:"assigned_at" json:"assigned_at,omitempty"`
+
+	// Verdict и ReviewedAt — последний вердикт ревьювера, если он уже есть.
+	Verdict    *ReviewVerdict `json:"verdict,omitempty"`
+	ReviewedAt *time.Time     `json:"reviewed_at,omitempty"`
+}
+
 type PullRequest struct {
-	PullRequestID     string            `db:"pull_request_id" json:"pull_request_id"`
-	PullRequestName   string            `db:"pull_request_name" json:"pull_request_name"`
-	AuthorID          string            `db:"author_id" json:"author_id"`
-	Status            PullRequestStatus `db:"status" json:"status"`
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	CreatedAt         *time.Time        `db:"created_at" json:"createdAt,omitempty"`
-	MergedAt          *time.Time        `db:"merged_at" json:"mergedAt,omitempty"`
+	PullRequestID     string               `db:"pull_request_id" json:"pull_request_id"`
+	PullRequestName   string               `db:"pull_request_name" json:"pull_request_name"`
+	AuthorID          string               `db:"author_id" json:"author_id"`
+	AuthorTeam        string               `db:"author_team" json:"author_team,omitempty"`
+	Status            PullRequestStatus    `db:"status" json:"status"`
+	AssignedReviewers []string             `json:"assigned_reviewers"`
+	Assignments       []ReviewerAssignment `json:"assignments,omitempty"`
+	CreatedAt         *time.Time           `db:"created_at" json:"createdAt,omitempty"`
+	MergedAt          *time.Time           `db:"merged_at" json:"mergedAt,omitempty"`
+	Repository        *string              `db:"repository" json:"repository,omitempty"`
+	PRNumber          *int                 `db:"pr_number" json:"pr_number,omitempty"`
+	// Version растёт при каждом изменении PR и отдаётся клиентам как ETag.
+	Version int64 `db:"version" json:"version"`
 }
 
 type PullRequestShort struct {
@@ -45,6 +160,31 @@ type PullRequestShort struct {
 }
 
 type Stats struct {
-	ReviewerAssignments map[string]int `json:"reviewer_assignments"`
-	PRAssignments       map[string]int `json:"pr_assignments"`
+	ReviewerAssignments     map[string]int       `json:"reviewer_assignments"`
+	ReviewerOpenAssignments map[string]int       `json:"reviewer_open_assignments"`
+	PRAssignments           map[string]int       `json:"pr_assignments"`
+	Teams                   map[string]TeamStats `json:"teams"`
+}
+
+type AssignmentCount struct {
+	Total int `db:"total"`
+	Open  int `db:"open"`
+}
+
+type TeamStats struct {
+	PullRequests       int `db:"pull_requests" json:"pull_requests"`
+	OpenPullRequests   int `db:"open_pull_requests" json:"open_pull_requests"`
+	MergedPullRequests int `db:"merged_pull_requests" json:"merged_pull_requests"`
+	Assignments        int `db:"assignments" json:"assignments"`
+	OpenAssignments    int `db:"open_assignments" json:"open_assignments"`
+	Reviewers          int `db:"reviewers" json:"reviewers"`
+}
+
+// StatsFilter ограничивает статистику PR, созданными в [From, To),
+// командой автора и статусом. Пустые поля не фильтруют.
+type StatsFilter struct {
+	From     *time.Time
+	To       *time.Time
+	TeamName string
+	Status   PullRequestStatus
 }
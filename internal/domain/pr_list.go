@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+type PRSortField string
+
+const (
+	SortByCreatedAt PRSortField = "created_at"
+	SortByMergedAt  PRSortField = "merged_at"
+)
+
+func (f PRSortField) Valid() bool {
+	return f == SortByCreatedAt || f == SortByMergedAt
+}
+
+// PRListFilter — фильтры, сортировка и страница списка PR. Сортировка по
+// merged_at оставляет только смерженные PR. Limit задаёт размер страницы.
+type PRListFilter struct {
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string
+	Status      PullRequestStatus
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+
+	SortBy PRSortField
+	Desc   bool
+	Limit  int
+	Cursor *PRCursor
+}
+
+// PRCursor — позиция последнего PR на странице. Курсор привязан к сортировке,
+// с которой он выдан.
+type PRCursor struct {
+	SortBy        PRSortField `json:"s"`
+	Desc          bool        `json:"d,omitempty"`
+	Value         time.Time   `json:"v"`
+	PullRequestID string      `json:"id"`
+}
+
+func (c PRCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodePRCursor(s string) (*PRCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c PRCursor
+	if err := json.Unmarshal(b, &c); err != nil || !c.SortBy.Valid() || c.PullRequestID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type PRPage struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
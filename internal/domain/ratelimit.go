@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// RateLimit — корзина токенов: до Burst запросов подряд, затем PerSecond запросов в секунду.
+// Нулевое значение отключает ограничение.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.PerSecond > 0 && l.Burst > 0
+}
+
+// Interval — время, за которое в корзину возвращается один токен.
+func (l RateLimit) Interval() time.Duration {
+	return time.Duration(float64(time.Second) / l.PerSecond)
+}
+
+// Take списывает токен по алгоритму GCRA: состояние корзины — теоретическое время прихода
+// следующего запроса tat. Возвращает новое tat, либо отказ и время до следующего разрешённого запроса.
+func (l RateLimit) Take(tat, now time.Time) (next time.Time, allowed bool, retryAfter time.Duration) {
+	interval := l.Interval()
+	next = now.Add(interval)
+	if tat.After(now) {
+		next = tat.Add(interval)
+	}
+	if wait := next.Sub(now) - time.Duration(l.Burst)*interval; wait > 0 {
+		return tat, false, wait
+	}
+	return next, true, 0
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+type ReviewVerdict string
+
+const (
+	VerdictApproved         ReviewVerdict = "APPROVED"
+	VerdictChangesRequested ReviewVerdict = "CHANGES_REQUESTED"
+	VerdictCommented        ReviewVerdict = "COMMENTED"
+)
+
+func (v ReviewVerdict) Valid() bool {
+	return v == VerdictApproved || v == VerdictChangesRequested || v == VerdictCommented
+}
+
+type Review struct {
+	ID            int64         `db:"id" json:"id"`
+	PullRequestID string        `db:"pull_request_id" json:"pull_request_id"`
+	UserID        string        `db:"user_id" json:"user_id"`
+	Verdict       ReviewVerdict `db:"verdict" json:"verdict"`
+	SubmittedAt   time.Time     `db:"submitted_at" json:"submitted_at"`
+}
+
+// DurationStats — распределение длительностей в секундах; Median и P90
+// пусты, если замеров нет.
+type DurationStats struct {
+	Count  int      `json:"count"`
+	Median *float64 `json:"median_seconds"`
+	P90    *float64 `json:"p90_seconds"`
+}
+
+// CycleTimeMetrics — время до первого ревью, до одобрения и до мержа.
+// Для команд отсчёт идёт от создания PR, для ревьюверов первое ревью
+// и одобрение считаются от момента назначения.
+type CycleTimeMetrics struct {
+	FirstReview DurationStats `json:"time_to_first_review"`
+	Approval    DurationStats `json:"time_to_approval"`
+	Merge       DurationStats `json:"time_to_merge"`
+}
+
+type CycleTimePoint struct {
+	WeekStart time.Time `json:"week_start"`
+	CycleTimeMetrics
+}
+
+type CycleTimeStats struct {
+	Teams     map[string]CycleTimeMetrics `json:"teams"`
+	Reviewers map[string]CycleTimeMetrics `json:"reviewers"`
+	// Weekly — понедельные ряды по командам, неделя определяется датой создания PR.
+	Weekly map[string][]CycleTimePoint `json:"weekly"`
+}
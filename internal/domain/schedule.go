@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type AssignmentMode string
+
+const (
+	AssignmentDefault      AssignmentMode = "DEFAULT"
+	AssignmentWorkingHours AssignmentMode = "WORKING_HOURS"
+)
+
+func (m AssignmentMode) Valid() bool {
+	return m == AssignmentDefault || m == AssignmentWorkingHours
+}
+
+// WorkingHours — рабочее время пользователя в его часовом поясе.
+// Days — дни недели по ISO (1 — понедельник, 7 — воскресенье), по умолчанию будни.
+// Если End раньше Start, смена переходит через полночь.
+type WorkingHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Days  []int  `json:"days,omitempty"`
+}
+
+var defaultWorkDays = []int{1, 2, 3, 4, 5}
+
+func (w WorkingHours) Validate() error {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("%w: start and end must differ", ErrInvalidSchedule)
+	}
+	for _, d := range w.Days {
+		if d < 1 || d > 7 {
+			return fmt.Errorf("%w: invalid weekday %d", ErrInvalidSchedule, d)
+		}
+	}
+	return nil
+}
+
+// Contains сообщает, попадает ли момент t (в часовом поясе loc) в рабочее время.
+func (w WorkingHours) Contains(t time.Time, loc *time.Location) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+
+	days := w.Days
+	if len(days) == 0 {
+		days = defaultWorkDays
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return slices.Contains(days, isoWeekday(local)) && minute >= start && minute < end
+	}
+
+	// ночная смена: после полуночи она всё ещё относится к предыдущему дню
+	if minute >= start {
+		return slices.Contains(days, isoWeekday(local))
+	}
+	return minute < end && slices.Contains(days, isoWeekday(local.AddDate(0, 0, -1)))
+}
+
+// Value сохраняет отсутствие расписания как NULL: sqlx.In вызывает Value и у nil-указателя.
+func (w *WorkingHours) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (w *WorkingHours) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return fmt.Errorf("cannot scan %T into WorkingHours", src)
+	}
+}
+
+// IsWorkingAt сообщает, находится ли пользователь в рабочем времени.
+// Пользователь без расписания считается доступным всегда.
+func (u User) IsWorkingAt(t time.Time) bool {
+	if u.WorkingHours == nil {
+		return true
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return u.WorkingHours.Contains(t, loc)
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid time %q, expected HH:MM", ErrInvalidSchedule, s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
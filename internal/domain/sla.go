@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReviewSLA — сроки реакции на ревью для команды. Через ReminderAfter ревьюверу
+// отправляется напоминание, через ReassignAfter (если задан) ревью переназначается.
+type ReviewSLA struct {
+	ReminderAfter time.Duration
+	ReassignAfter time.Duration
+}
+
+type reviewSLAJSON struct {
+	ReminderAfter string `json:"reminder_after"`
+	ReassignAfter string `json:"reassign_after,omitempty"`
+}
+
+func (s ReviewSLA) MarshalJSON() ([]byte, error) {
+	v := reviewSLAJSON{ReminderAfter: s.ReminderAfter.String()}
+	if s.ReassignAfter > 0 {
+		v.ReassignAfter = s.ReassignAfter.String()
+	}
+	return json.Marshal(v)
+}
+
+func (s *ReviewSLA) UnmarshalJSON(b []byte) error {
+	var v reviewSLAJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	reminder, err := time.ParseDuration(v.ReminderAfter)
+	if err != nil || reminder <= 0 {
+		return fmt.Errorf("%w: invalid reminder_after %q", ErrInvalidSLA, v.ReminderAfter)
+	}
+	s.ReminderAfter = reminder
+
+	s.ReassignAfter = 0
+	if v.ReassignAfter != "" {
+		reassign, err := time.ParseDuration(v.ReassignAfter)
+		if err != nil || reassign <= 0 {
+			return fmt.Errorf("%w: invalid reassign_after %q", ErrInvalidSLA, v.ReassignAfter)
+		}
+		s.ReassignAfter = reassign
+	}
+	return nil
+}
+
+// OverdueAssignment — назначение ревьювера на открытый PR, превысившее срок напоминания.
+type OverdueAssignment struct {
+	PullRequestID string     `db:"pull_request_id"`
+	UserID        string     `db:"user_id"`
+	TeamName      string     `db:"team_name"`
+	AssignedAt    time.Time  `db:"assigned_at"`
+	RemindedAt    *time.Time `db:"reminded_at"`
+	ReminderAfter int64      `db:"reminder_seconds"`
+	ReassignAfter *int64     `db:"reassign_seconds"`
+}
+
+func (o OverdueAssignment) SLA() ReviewSLA {
+	sla := ReviewSLA{ReminderAfter: time.Duration(o.ReminderAfter) * time.Second}
+	if o.ReassignAfter != nil {
+		sla.ReassignAfter = time.Duration(*o.ReassignAfter) * time.Second
+	}
+	return sla
+}
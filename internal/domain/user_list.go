@@ -0,0 +1,57 @@
+package domain
+
+import "encoding/base64"
+
+// UserUpdate — частичное обновление пользователя: nil-поля не меняются,
+// пустой Timezone возвращает часовой пояс по умолчанию (UTC).
+type UserUpdate struct {
+	Username       *string       `json:"username"`
+	TeamName       *string       `json:"team_name"`
+	IsActive       *bool         `json:"is_active"`
+	MaxOpenReviews *int          `json:"max_open_reviews"`
+	Timezone       *string       `json:"timezone"`
+	WorkingHours   *WorkingHours `json:"working_hours"`
+}
+
+// UserListFilter — поиск пользователей; выдача упорядочена по user_id,
+// AfterUserID задаёт начало страницы.
+type UserListFilter struct {
+	TeamName       string
+	UsernamePrefix string
+	IsActive       *bool
+	AfterUserID    string
+	Limit          int
+}
+
+type UserPage struct {
+	Users      []User `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func EncodeUserCursor(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
+
+func DecodeUserCursor(s string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return "", ErrInvalidCursor
+	}
+	return string(b), nil
+}
+
+// TeamListFilter — страница команд в порядке team_name, начиная после AfterTeamName.
+type TeamListFilter struct {
+	AfterTeamName string
+	Limit         int
+}
+
+type TeamPage struct {
+	Teams      []Team `json:"teams"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Курсор команд кодируется так же, как курсор пользователей.
+func EncodeTeamCursor(teamName string) string { return EncodeUserCursor(teamName) }
+
+func DecodeTeamCursor(s string) (string, error) { return DecodeUserCursor(s) }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// CodeHostClient запрашивает ревью на хостинге кода. Логины на хостинге — это username
+// пользователей сервиса, поэтому для синхронизации username должен совпадать с логином на GitHub.
+type CodeHostClient interface {
+	RequestReviewers(ctx context.Context, repository string, number int, logins []string) error
+	RemoveReviewers(ctx context.Context, repository string, number int, logins []string) error
+}
+
+// ErrCodeHostRejected оборачивает ответы хостинга, которые не изменятся при повторе
+// (4xx, кроме 429): такие запросы не повторяются.
+var ErrCodeHostRejected = errors.New("code host rejected request")
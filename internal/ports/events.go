@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"PRService/internal/domain"
+	"context"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event)
+}
+
+type EventSubscriber interface {
+	// Subscribe отдаёт события, опубликованные после подписки, пока не отменён ctx.
+	Subscribe(ctx context.Context) <-chan domain.Event
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"time"
+)
+
+// IdempotencyStore хранит ключи идемпотентности; scope отделяет ключи разных API-токенов.
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey занимает ключ до expiresAt под владельцем owner. Если ключ уже занят
+	// и не истёк, возвращает существующую запись и false. Незавершённая резервация того же
+	// запроса с истёкшей арендой (владелец упал, не ответив) перезанимается.
+	ReserveIdempotencyKey(ctx context.Context, scope, key, fingerprint, owner string, leaseUntil, expiresAt time.Time) (domain.IdempotencyRecord, bool, error)
+	// ExtendIdempotencyKey продлевает аренду незавершённой резервации владельца owner.
+	ExtendIdempotencyKey(ctx context.Context, scope, key, owner string, leaseUntil time.Time) error
+	CompleteIdempotencyKey(ctx context.Context, scope, key, owner string, statusCode int, contentType string, headers domain.ReplayedHeaders, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, scope, key, owner string) error
+	PurgeIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+}
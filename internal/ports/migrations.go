@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"PRService/internal/domain"
+	"context"
+)
+
+type SchemaMigrator interface {
+	MigrationStatus(ctx context.Context) (domain.MigrationStatus, error)
+	// Migrate применяет все ещё не применённые миграции.
+	Migrate() error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"time"
+)
+
+type RateLimitStore interface {
+	// TakeRateLimitToken списывает запрос из корзины key. При отказе возвращает false
+	// и время, через которое запрос будет разрешён.
+	TakeRateLimitToken(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (bool, time.Duration, error)
+	// PurgeRateLimitBuckets удаляет корзины, заполнившиеся до before: они не отличаются от новых.
+	PurgeRateLimitBuckets(ctx context.Context, before time.Time) (int64, error)
+}
@@ -9,17 +9,85 @@ import (
 type Repository interface {
 	CreateTeam(ctx context.Context, team domain.Team) error
 	GetTeam(ctx context.Context, teamName string) (domain.Team, error)
+	SetTeamFallback(ctx context.Context, teamName string, partnerTeams, pools []string) error
+	SetTeamReviewerLimits(ctx context.Context, teamName string, minReviewers, maxReviewers *int) error
+	SetTeamReviewSLA(ctx context.Context, teamName string, sla *domain.ReviewSLA) error
+	SaveReviewerPool(ctx context.Context, pool domain.ReviewerPool) error
+	GetReviewerPool(ctx context.Context, poolName string) (domain.ReviewerPool, error)
+	ListActivePoolMembers(ctx context.Context, poolName string, excludeIDs []string, limit int) ([]domain.User, error)
 	UpsertUsers(ctx context.Context, users []domain.User) error
 	SetUserActive(ctx context.Context, userID string, isActive bool) (domain.User, error)
+	SetUserMaxOpenReviews(ctx context.Context, userID string, limit *int) (domain.User, error)
+	SetTeamMaxOpenReviews(ctx context.Context, teamName string, limit *int) error
+	GetReviewLoad(ctx context.Context, filter domain.ReviewLoadFilter) ([]domain.UserLoad, error)
+
+	AddUnavailability(ctx context.Context, u domain.Unavailability) (domain.Unavailability, error)
+	DeleteUnavailability(ctx context.Context, id int64) error
+	ListUnavailability(ctx context.Context, userID string, endsAfter time.Time) ([]domain.Unavailability, error)
+	ListUnavailableAt(ctx context.Context, userIDs []string, at time.Time) ([]domain.Unavailability, error)
+	ListUnavailabilityByUsers(ctx context.Context, userIDs []string) ([]domain.Unavailability, error)
+	ListStartedUnavailability(ctx context.Context, at time.Time) ([]domain.Unavailability, error)
+	MarkUnavailabilityReassigned(ctx context.Context, id int64, at time.Time) error
 	GetUser(ctx context.Context, userID string) (domain.User, error)
+	CreateUser(ctx context.Context, user domain.User) (domain.User, error)
+	UpdateUser(ctx context.Context, userID string, update domain.UserUpdate) (domain.User, error)
+	ListUsers(ctx context.Context, filter domain.UserListFilter) ([]domain.User, error)
+	ListTeamsPage(ctx context.Context, filter domain.TeamListFilter) ([]domain.Team, error)
 	ListActiveTeamMembers(ctx context.Context, teamName string, excludeIDs []string, limit int) ([]domain.User, error)
+	ListUsersByLogins(ctx context.Context, logins []string) ([]domain.User, error)
+
+	// Пакетная загрузка для даталоадеров GraphQL: отсутствующие ключи пропускаются.
+	GetUsersByIDs(ctx context.Context, userIDs []string) ([]domain.User, error)
+	ListUsersByTeams(ctx context.Context, teamNames []string) ([]domain.User, error)
+	GetTeamsByNames(ctx context.Context, teamNames []string) ([]domain.Team, error)
+	GetPRsByIDs(ctx context.Context, prIDs []string) ([]domain.PullRequest, error)
+	ListOpenPRsByReviewers(ctx context.Context, userIDs []string) ([]domain.PullRequest, error)
+
+	SaveCodeOwners(ctx context.Context, file domain.CodeOwnersFile) error
+	GetCodeOwners(ctx context.Context, teamName string) (domain.CodeOwnersFile, error)
 
-	CreatePR(ctx context.Context, pr domain.PullRequest, reviewers []string) error
+	CreatePR(ctx context.Context, pr domain.PullRequest, reviewers []domain.ReviewerAssignment) error
+	CreatePRs(ctx context.Context, prs []domain.PullRequest) ([]error, error)
 	GetPR(ctx context.Context, prID string) (domain.PullRequest, error)
-	UpdatePRStatusMerged(ctx context.Context, prID string, mergedAt *time.Time) (domain.PullRequest, error)
-	ReplaceReviewer(ctx context.Context, prID string, oldUserID, newUserID string) (domain.PullRequest, error)
+	// Методы изменения PR повышают его версию; ненулевая expectedVersion должна совпасть
+	// с текущей, иначе возвращается domain.ErrVersionMismatch.
+	UpdatePRStatusMerged(ctx context.Context, prID string, mergedAt *time.Time, expectedVersion int64) (domain.PullRequest, error)
+	ReplaceReviewer(ctx context.Context, prID string, oldUserID string, newReviewer domain.ReviewerAssignment, expectedVersion int64) (domain.PullRequest, error)
+	AddReviewer(ctx context.Context, prID string, reviewer domain.ReviewerAssignment, expectedVersion int64) (domain.PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, userID string, expectedVersion int64) (domain.PullRequest, error)
 	ListPRsByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error)
+	AddReview(ctx context.Context, review domain.Review) (domain.Review, error)
+	ListOverdueAssignments(ctx context.Context, at time.Time) ([]domain.OverdueAssignment, error)
+	MarkReviewReminded(ctx context.Context, prID, userID string, at time.Time) error
+	MarkReassignFailed(ctx context.Context, prID, userID string, at time.Time) error
+
+	GetReviewerStats(ctx context.Context, filter domain.StatsFilter) (map[string]domain.AssignmentCount, error)
+	GetPRStats(ctx context.Context, filter domain.StatsFilter) (map[string]int, error)
+	GetTeamStats(ctx context.Context, filter domain.StatsFilter) (map[string]domain.TeamStats, error)
+	GetTeamCycleTimes(ctx context.Context, filter domain.StatsFilter) (map[string]domain.CycleTimeMetrics, map[string][]domain.CycleTimePoint, error)
+	GetReviewerCycleTimes(ctx context.Context, filter domain.StatsFilter) (map[string]domain.CycleTimeMetrics, error)
+	ListReviewerActivity(ctx context.Context, from, to time.Time, teamName string) ([]domain.ReviewerActivity, error)
+
+	// Выгрузка и загрузка данных между окружениями.
+	ListTeams(ctx context.Context) ([]domain.Team, error)
+	ListReviewerPools(ctx context.Context) ([]domain.ReviewerPool, error)
+	ListCodeOwners(ctx context.Context) ([]domain.CodeOwnersFile, error)
+	ListReviewsByPRs(ctx context.Context, prIDs []string) ([]domain.Review, error)
+	ExportSnapshot(ctx context.Context, fn func(ExportReader) error) error
+	SaveUser(ctx context.Context, user domain.User, createdAt *time.Time) error
+	SavePR(ctx context.Context, pr domain.PullRequest, reviews []domain.Review, remindedAt map[string]time.Time) error
+}
 
-	GetReviewerStats(ctx context.Context) (map[string]int, error)
-	GetPRStats(ctx context.Context) (map[string]int, error)
+// ExportReader — чтения выгрузки из одного согласованного среза базы.
+type ExportReader interface {
+	ListTeams(ctx context.Context) ([]domain.Team, error)
+	ListUsers(ctx context.Context, filter domain.UserListFilter) ([]domain.User, error)
+	ListUserCreatedAt(ctx context.Context, userIDs []string) (map[string]time.Time, error)
+	ListReviewerPools(ctx context.Context) ([]domain.ReviewerPool, error)
+	ListCodeOwners(ctx context.Context) ([]domain.CodeOwnersFile, error)
+	ListUnavailabilityByUsers(ctx context.Context, userIDs []string) ([]domain.Unavailability, error)
+	ListPRs(ctx context.Context, filter domain.PRListFilter) ([]domain.PullRequest, error)
+	ListReviewsByPRs(ctx context.Context, prIDs []string) ([]domain.Review, error)
+	ListRemindersByPRs(ctx context.Context, prIDs []string) (map[string]map[string]time.Time, error)
 }
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// BulkCreateBatchSize — сколько PR записывается в одной транзакции при массовой загрузке.
+const BulkCreateBatchSize = 100
+
+// BulkCreatePRs читает JSON-массив или NDJSON с PR и создаёт их пачками по BulkCreateBatchSize
+// по мере чтения, не загружая всё тело в память. Результат — по pull_request_id
+// (или "#<номер>" для элементов без него и "<id>#<номер>" для повторов): "success"
+// либо причина отказа, как в DeactivateUsers.
+// Автоподбор учитывает ревьюверов, уже выбранных для предыдущих PR загрузки.
+// На невалидном элементе уже прочитанные PR записываются, а возвращается ErrInvalidBulkInput.
+func (s *Service) BulkCreatePRs(ctx context.Context, r io.Reader) (map[string]string, error) {
+	results := make(map[string]string)
+	authors := make(map[string]*domain.User)
+	pending := make(map[string]int)
+	seen := make(map[string]bool)
+
+	// pending копит нагрузку PR текущей пачки, чтобы автоподбор учитывал их до записи;
+	// если PR не записался, его ревьюверы снимаются обратно.
+	release := func(pr domain.PullRequest) {
+		for _, a := range pr.Assignments {
+			if pending[a.UserID]--; pending[a.UserID] <= 0 {
+				delete(pending, a.UserID)
+			}
+		}
+	}
+
+	batch := make([]domain.PullRequest, 0, BulkCreateBatchSize)
+	logins := make([][]string, 0, BulkCreateBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		errs, err := s.repo.CreatePRs(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i, pr := range batch {
+			switch {
+			case errs[i] == nil:
+				results[pr.PullRequestID] = "success"
+				s.syncCodeHost(pr, logins[i], nil)
+				s.publishAssignments(ctx, pr, pr.AssignedReviewers)
+			case errors.Is(errs[i], domain.ErrPrExists):
+				results[pr.PullRequestID] = "PR already exists"
+				release(pr)
+			default:
+				results[pr.PullRequestID] = fmt.Sprintf("failed to create: %v", errs[i])
+				release(pr)
+			}
+		}
+		batch, logins = batch[:0], logins[:0]
+		return nil
+	}
+
+	next, err := bulkItems(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; ; i++ {
+		item, ok, err := next()
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				return nil, flushErr
+			}
+			return results, fmt.Errorf("%w: item %d: %w", domain.ErrInvalidBulkInput, i+1, err)
+		}
+		if !ok {
+			if i == 0 {
+				return nil, fmt.Errorf("%w: pull requests required", domain.ErrInvalidBulkInput)
+			}
+			break
+		}
+
+		key := item.PullRequestID
+		if key == "" {
+			key = fmt.Sprintf("#%d", i+1)
+		}
+		if seen[key] {
+			// повтор не должен затереть результат первого вхождения
+			results[fmt.Sprintf("%s#%d", key, i+1)] = "duplicate pull_request_id in request"
+			continue
+		}
+		seen[key] = true
+
+		if item.PullRequestID == "" || item.PullRequestName == "" || item.AuthorID == "" {
+			results[key] = "pull_request_id, pull_request_name and author_id are required"
+			continue
+		}
+		if (item.Repository == nil) != (item.PRNumber == nil) {
+			results[key] = "repository and pr_number must be set together"
+			continue
+		}
+
+		author, ok := authors[item.AuthorID]
+		if !ok {
+			if u, err := s.repo.GetUser(ctx, item.AuthorID); err == nil {
+				author = &u
+			} else if !errors.Is(err, domain.ErrNotFound) {
+				return nil, err
+			}
+			authors[item.AuthorID] = author
+		}
+		if author == nil {
+			results[key] = "author not found"
+			continue
+		}
+
+		pr, reviewerLogins, reason, err := s.bulkPR(ctx, *author, item, pending)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			results[key] = reason
+			continue
+		}
+
+		for _, a := range pr.Assignments {
+			pending[a.UserID]++
+		}
+		batch = append(batch, pr)
+		logins = append(logins, reviewerLogins)
+		if len(batch) == BulkCreateBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// bulkItems возвращает функцию, которая читает следующий элемент из JSON-массива или NDJSON.
+// ok == false — элементы закончились.
+func bulkItems(r io.Reader) (func() (domain.BulkPRItem, bool, error), error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err == io.EOF {
+			return func() (domain.BulkPRItem, bool, error) { return domain.BulkPRItem{}, false, nil }, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			array := b[0] == '['
+			dec := json.NewDecoder(br)
+			if array {
+				// открывающая скобка массива
+				if _, err := dec.Token(); err != nil {
+					return nil, err
+				}
+			}
+			return func() (domain.BulkPRItem, bool, error) {
+				var item domain.BulkPRItem
+				if array && !dec.More() {
+					// закрывающая скобка массива
+					if _, err := dec.Token(); err != nil {
+						return item, false, err
+					}
+					return item, false, nil
+				}
+				err := dec.Decode(&item)
+				if err == io.EOF && !array {
+					return item, false, nil
+				}
+				if err != nil {
+					return item, false, err
+				}
+				return item, true, nil
+			}, nil
+		}
+		_, _ = br.ReadByte()
+	}
+}
+
+// bulkPR собирает PR для массовой загрузки. reason — причина отказа для этого элемента,
+// err — ошибка, прерывающая всю загрузку.
+func (s *Service) bulkPR(ctx context.Context, author domain.User, item domain.BulkPRItem, pending map[string]int) (domain.PullRequest, []string, string, error) {
+	now := s.now()
+	createdAt := &now
+	if item.CreatedAt != nil {
+		createdAt = item.CreatedAt
+	}
+	pr := domain.PullRequest{
+		PullRequestID:   item.PullRequestID,
+		PullRequestName: item.PullRequestName,
+		AuthorID:        item.AuthorID,
+		AuthorTeam:      author.TeamName,
+		Status:          domain.StatusOpen,
+		CreatedAt:       createdAt,
+		Repository:      item.Repository,
+		PRNumber:        item.PRNumber,
+		Version:         1,
+	}
+
+	var logins []string
+	if len(item.Reviewers) > 0 {
+		team, err := s.repo.GetTeam(ctx, author.TeamName)
+		if err != nil {
+			return domain.PullRequest{}, nil, "", err
+		}
+		if _, upper := team.ReviewerBounds(); upper >= 0 && len(item.Reviewers) > upper {
+			return domain.PullRequest{}, nil, fmt.Sprintf("at most %d reviewers allowed", upper), nil
+		}
+		for _, userID := range item.Reviewers {
+			if slices.Contains(pr.AssignedReviewers, userID) {
+				return domain.PullRequest{}, nil, fmt.Sprintf("reviewer %s listed twice", userID), nil
+			}
+			// те же проверки, что и у AddReviewer
+			user, err := s.manualReviewer(ctx, pr, userID)
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				return domain.PullRequest{}, nil, fmt.Sprintf("reviewer %s not found", userID), nil
+			case errors.Is(err, domain.ErrReviewerIsAuthor):
+				return domain.PullRequest{}, nil, "author cannot review own PR", nil
+			case errors.Is(err, domain.ErrUserInactive):
+				return domain.PullRequest{}, nil, fmt.Sprintf("reviewer %s is inactive", userID), nil
+			case errors.Is(err, domain.ErrNotTeamMember):
+				return domain.PullRequest{}, nil, fmt.Sprintf("reviewer %s is not in author's team, partner teams or pools", userID), nil
+			case err != nil:
+				return domain.PullRequest{}, nil, "", err
+			}
+			pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+			pr.Assignments = append(pr.Assignments, domain.ReviewerAssignment{
+				UserID:     userID,
+				Source:     domain.SourceManual,
+				AssignedAt: &now,
+			})
+			logins = append(logins, user.Username)
+		}
+		return pr, logins, "", nil
+	}
+
+	candidates, err := s.pickReviewers(ctx, reviewerQuery{
+		author:       author,
+		changedFiles: item.ChangedFiles,
+		exclude:      []string{author.UserID},
+		n:            s.autoReviewerCount(ctx, author.TeamName),
+		pending:      pending,
+	})
+	if err != nil {
+		return domain.PullRequest{}, nil, "", err
+	}
+	pr.AssignedReviewers = make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, c.user.UserID)
+		pr.Assignments = append(pr.Assignments, c.assignment(now))
+		logins = append(logins, c.user.Username)
+	}
+	return pr, logins, "", nil
+}
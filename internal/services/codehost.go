@@ -0,0 +1,73 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// WithCodeHost включает синхронизацию назначенных ревьюверов с внешним хостингом кода.
+func WithCodeHost(client ports.CodeHostClient) Option {
+	return func(s *Service) {
+		s.codeHost = client
+	}
+}
+
+func WithCodeHostRetry(attempts int, delay time.Duration) Option {
+	return func(s *Service) {
+		if attempts > 0 {
+			s.codeHostAttempts = attempts
+		}
+		if delay > 0 {
+			s.codeHostRetryDelay = delay
+		}
+	}
+}
+
+func (s *Service) syncCodeHost(pr domain.PullRequest, requested, removed []string) {
+	if s.codeHost == nil || pr.Repository == nil || pr.PRNumber == nil {
+		return
+	}
+	if len(requested) == 0 && len(removed) == 0 {
+		return
+	}
+
+	repository, number := *pr.Repository, *pr.PRNumber
+	s.syncs.Add(1)
+	go s.retryCodeHost(pr.PullRequestID, func(ctx context.Context) error {
+		if err := s.codeHost.RemoveReviewers(ctx, repository, number, removed); err != nil {
+			return err
+		}
+		return s.codeHost.RequestReviewers(ctx, repository, number, requested)
+	})
+}
+
+// retryCodeHost повторяет синхронизацию с растущей задержкой, пока сервис не закрыт.
+// Отказ хостинга (ports.ErrCodeHostRejected) не повторяется: ответ будет тем же.
+func (s *Service) retryCodeHost(prID string, push func(ctx context.Context) error) {
+	defer s.syncs.Done()
+	delay := s.codeHostRetryDelay
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(s.background, 30*time.Second)
+		err := push(ctx)
+		cancel()
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ports.ErrCodeHostRejected) || attempt >= s.codeHostAttempts {
+			log.Printf("code host sync for PR %s failed after %d attempts: %v", prID, attempt, err)
+			return
+		}
+		log.Printf("code host sync for PR %s failed (attempt %d), retrying in %s: %v", prID, attempt, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-s.background.Done():
+			log.Printf("code host sync for PR %s cancelled: service is closing", prID)
+			return
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"context"
+)
+
+func (s *Service) UploadCodeOwners(ctx context.Context, file domain.CodeOwnersFile) (domain.CodeOwners, error) {
+	co, err := domain.ParseCodeOwners(file.Content)
+	if err != nil {
+		return domain.CodeOwners{}, err
+	}
+
+	if file.TeamName != "" {
+		if _, err := s.repo.GetTeam(ctx, file.TeamName); err != nil {
+			return domain.CodeOwners{}, domain.ErrNotFound
+		}
+	}
+
+	if err := s.repo.SaveCodeOwners(ctx, file); err != nil {
+		return domain.CodeOwners{}, err
+	}
+	return co, nil
+}
+
+func (s *Service) GetCodeOwners(ctx context.Context, teamName string) (domain.CodeOwnersFile, error) {
+	return s.repo.GetCodeOwners(ctx, teamName)
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// exportPageSize — размер страницы при чтении пользователей и PR для выгрузки.
+const exportPageSize = 500
+
+// Export пишет в w все данные сервиса в формате NDJSON (см. domain.ExportRecord).
+// Данные читаются страницами из одного снимка базы, поэтому выгрузка согласована
+// и не держит всю базу в памяти.
+func (s *Service) Export(ctx context.Context, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	now := s.now()
+	if err := enc.Encode(domain.ExportRecord{Kind: domain.ExportHeader, Version: domain.ExportFormatVersion, ExportedAt: &now}); err != nil {
+		return err
+	}
+	return s.repo.ExportSnapshot(ctx, func(repo ports.ExportReader) error {
+		return export(ctx, repo, enc)
+	})
+}
+
+func export(ctx context.Context, repo ports.ExportReader, enc *json.Encoder) error {
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range teams {
+		if err := enc.Encode(domain.ExportRecord{Kind: domain.ExportTeam, Team: &teams[i]}); err != nil {
+			return err
+		}
+	}
+
+	var userIDs []string
+	filter := domain.UserListFilter{Limit: exportPageSize}
+	for {
+		users, err := repo.ListUsers(ctx, filter)
+		if err != nil {
+			return err
+		}
+		pageIDs := make([]string, len(users))
+		for i := range users {
+			pageIDs[i] = users[i].UserID
+		}
+		createdAt, err := repo.ListUserCreatedAt(ctx, pageIDs)
+		if err != nil {
+			return err
+		}
+		for i := range users {
+			rec := domain.ExportRecord{Kind: domain.ExportUser, User: &users[i]}
+			if at, ok := createdAt[users[i].UserID]; ok {
+				rec.UserCreatedAt = &at
+			}
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+		userIDs = append(userIDs, pageIDs...)
+		if len(users) < exportPageSize {
+			break
+		}
+		filter.AfterUserID = users[len(users)-1].UserID
+	}
+
+	pools, err := repo.ListReviewerPools(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range pools {
+		if err := enc.Encode(domain.ExportRecord{Kind: domain.ExportPool, Pool: &pools[i]}); err != nil {
+			return err
+		}
+	}
+
+	files, err := repo.ListCodeOwners(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range files {
+		if err := enc.Encode(domain.ExportRecord{Kind: domain.ExportCodeOwners, CodeOwners: &files[i]}); err != nil {
+			return err
+		}
+	}
+
+	for start := 0; start < len(userIDs); start += exportPageSize {
+		windows, err := repo.ListUnavailabilityByUsers(ctx, userIDs[start:min(start+exportPageSize, len(userIDs))])
+		if err != nil {
+			return err
+		}
+		for i := range windows {
+			if err := enc.Encode(domain.ExportRecord{Kind: domain.ExportUnavailability, Unavailability: &windows[i]}); err != nil {
+				return err
+			}
+		}
+	}
+
+	prFilter := domain.PRListFilter{SortBy: domain.SortByCreatedAt, Limit: exportPageSize}
+	for {
+		prs, err := repo.ListPRs(ctx, prFilter)
+		if err != nil {
+			return err
+		}
+		prIDs := make([]string, len(prs))
+		for i := range prs {
+			prIDs[i] = prs[i].PullRequestID
+		}
+		reviews, err := repo.ListReviewsByPRs(ctx, prIDs)
+		if err != nil {
+			return err
+		}
+		reminders, err := repo.ListRemindersByPRs(ctx, prIDs)
+		if err != nil {
+			return err
+		}
+		byPR := make(map[string][]domain.Review, len(prs))
+		for _, r := range reviews {
+			byPR[r.PullRequestID] = append(byPR[r.PullRequestID], r)
+		}
+		for i := range prs {
+			id := prs[i].PullRequestID
+			rec := domain.ExportRecord{Kind: domain.ExportPullRequest, PullRequest: &prs[i], Reviews: byPR[id], RemindedAt: reminders[id]}
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+		if len(prs) < exportPageSize {
+			break
+		}
+		last := prs[len(prs)-1]
+		prFilter.Cursor = &domain.PRCursor{SortBy: domain.SortByCreatedAt, Value: *last.CreatedAt, PullRequestID: last.PullRequestID}
+	}
+
+	return nil
+}
+
+// Import загружает выгрузку Export через репозиторий сервиса. Существующие записи
+// пропускаются, перезаписываются или прерывают импорт с ErrImportConflict согласно policy.
+// Импорт не транзакционный: при ошибке уже загруженные записи остаются.
+func (s *Service) Import(ctx context.Context, r io.Reader, policy domain.ConflictPolicy) (domain.ImportStats, error) {
+	if !policy.Valid() {
+		return nil, fmt.Errorf("%w: unknown conflict policy %q", domain.ErrInvalidExport, policy)
+	}
+
+	dec := json.NewDecoder(r)
+	var header domain.ExportRecord
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("header: %w: %w", domain.ErrInvalidExport, err)
+	}
+	if header.Kind != domain.ExportHeader {
+		return nil, fmt.Errorf("header: %w: first record must be a header", domain.ErrInvalidExport)
+	}
+	if header.Version < 1 || header.Version > domain.ExportFormatVersion {
+		return nil, fmt.Errorf("header: %w: unsupported format version %d", domain.ErrInvalidExport, header.Version)
+	}
+
+	stats := domain.ImportStats{}
+	// резервные источники ссылаются на другие команды и пулы, поэтому задаются в конце
+	fallbacks := make(map[string]domain.Team)
+
+	for n := 2; ; n++ {
+		var rec domain.ExportRecord
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("record %d: %w: %w", n, domain.ErrInvalidExport, err)
+		}
+
+		counts := stats[rec.Kind]
+		if counts == nil {
+			counts = &domain.ImportCounts{}
+			stats[rec.Kind] = counts
+		}
+		if err := s.importRecord(ctx, rec, policy, counts, fallbacks); err != nil {
+			return stats, fmt.Errorf("record %d: %w", n, err)
+		}
+	}
+
+	// пустые списки тоже записываются: overwrite должен снять резервные источники,
+	// которых нет в выгрузке
+	for name, team := range fallbacks {
+		if err := s.repo.SetTeamFallback(ctx, name, team.PartnerTeams, team.FallbackPools); err != nil {
+			return stats, fmt.Errorf("team %s fallbacks: %w", name, err)
+		}
+	}
+	return stats, nil
+}
+
+func (s *Service) importRecord(ctx context.Context, rec domain.ExportRecord, policy domain.ConflictPolicy, counts *domain.ImportCounts, fallbacks map[string]domain.Team) error {
+	// write сообщает, записывать ли запись, которая уже есть в базе
+	write := func(exists bool) (bool, error) {
+		switch {
+		case !exists:
+			counts.Created++
+			return true, nil
+		case policy == domain.ConflictOverwrite:
+			counts.Overwritten++
+			return true, nil
+		case policy == domain.ConflictSkip:
+			counts.Skipped++
+			return false, nil
+		default:
+			return false, domain.ErrImportConflict
+		}
+	}
+	exists := func(err error) (bool, error) {
+		if err == nil {
+			return true, nil
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	switch {
+	case rec.Kind == domain.ExportTeam && rec.Team != nil:
+		t := *rec.Team
+		t.Members = nil
+		err := s.repo.CreateTeam(ctx, t)
+		found := errors.Is(err, domain.ErrTeamExists)
+		if err != nil && !found {
+			return err
+		}
+		if ok, err := write(found); !ok {
+			return err
+		}
+		fallbacks[t.TeamName] = t
+		if !found {
+			// новая команда создаётся сразу со всеми настройками
+			return nil
+		}
+		if err := s.repo.SetTeamMaxOpenReviews(ctx, t.TeamName, t.DefaultMaxOpenReviews); err != nil {
+			return err
+		}
+		if err := s.repo.SetTeamReviewerLimits(ctx, t.TeamName, t.MinReviewers, t.MaxReviewers); err != nil {
+			return err
+		}
+		return s.repo.SetTeamReviewSLA(ctx, t.TeamName, t.ReviewSLA)
+
+	case rec.Kind == domain.ExportUser && rec.User != nil:
+		_, err := s.repo.GetUser(ctx, rec.User.UserID)
+		found, err := exists(err)
+		if err != nil {
+			return err
+		}
+		if ok, err := write(found); !ok {
+			return err
+		}
+		return s.repo.SaveUser(ctx, *rec.User, rec.UserCreatedAt)
+
+	case rec.Kind == domain.ExportPool && rec.Pool != nil:
+		_, err := s.repo.GetReviewerPool(ctx, rec.Pool.PoolName)
+		found, err := exists(err)
+		if err != nil {
+			return err
+		}
+		if ok, err := write(found); !ok {
+			return err
+		}
+		return s.repo.SaveReviewerPool(ctx, *rec.Pool)
+
+	case rec.Kind == domain.ExportCodeOwners && rec.CodeOwners != nil:
+		_, err := s.repo.GetCodeOwners(ctx, rec.CodeOwners.TeamName)
+		found, err := exists(err)
+		if err != nil {
+			return err
+		}
+		if ok, err := write(found); !ok {
+			return err
+		}
+		return s.repo.SaveCodeOwners(ctx, *rec.CodeOwners)
+
+	case rec.Kind == domain.ExportUnavailability && rec.Unavailability != nil:
+		u := *rec.Unavailability
+		windows, err := s.repo.ListUnavailability(ctx, u.UserID, time.Time{})
+		if err != nil {
+			return err
+		}
+		// у периода нет переносимого id, совпадением считается тот же интервал того же вида
+		var existing *domain.Unavailability
+		for i, w := range windows {
+			if w.Kind == u.Kind && w.StartsAt.Equal(u.StartsAt) && w.EndsAt.Equal(u.EndsAt) {
+				existing = &windows[i]
+				break
+			}
+		}
+		if ok, err := write(existing != nil); !ok {
+			return err
+		}
+		if existing != nil {
+			if err := s.repo.DeleteUnavailability(ctx, existing.ID); err != nil {
+				return err
+			}
+		}
+		created, err := s.repo.AddUnavailability(ctx, u)
+		if err != nil {
+			return err
+		}
+		// уже обработанные периоды не должны заново переназначать ревью
+		if u.ReassignedAt != nil {
+			return s.repo.MarkUnavailabilityReassigned(ctx, created.ID, *u.ReassignedAt)
+		}
+		return nil
+
+	case rec.Kind == domain.ExportPullRequest && rec.PullRequest != nil:
+		_, err := s.repo.GetPR(ctx, rec.PullRequest.PullRequestID)
+		found, err := exists(err)
+		if err != nil {
+			return err
+		}
+		if ok, err := write(found); !ok {
+			return err
+		}
+		return s.repo.SavePR(ctx, *rec.PullRequest, rec.Reviews, rec.RemindedAt)
+
+	default:
+		return domain.ErrInvalidExport
+	}
+}
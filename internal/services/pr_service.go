@@ -3,10 +3,18 @@ package services
 import (
 	"PRService/internal/domain"
 	"context"
-	"time"
+	"errors"
+	"slices"
 )
 
-func (s *Service) CreatePR(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
+type CreatePROptions struct {
+	// ChangedFiles — пути изменённых файлов для выбора ревьюверов по CODEOWNERS.
+	ChangedFiles []string
+	// AssignmentMode переопределяет режим выбора ревьюверов сервиса.
+	AssignmentMode domain.AssignmentMode
+}
+
+func (s *Service) CreatePR(ctx context.Context, pr domain.PullRequest, opts CreatePROptions) (domain.PullRequest, error) {
 	if _, err := s.repo.GetPR(ctx, pr.PullRequestID); err == nil {
 		return domain.PullRequest{}, domain.ErrPrExists
 	}
@@ -17,40 +25,83 @@ func (s *Service) CreatePR(ctx context.Context, pr domain.PullRequest) (domain.P
 	}
 
 	exclude := []string{pr.AuthorID}
-	candidates, err := s.repo.ListActiveTeamMembers(ctx, author.TeamName, exclude, 2)
+	candidates, err := s.pickReviewers(ctx, reviewerQuery{
+		author:       author,
+		changedFiles: opts.ChangedFiles,
+		exclude:      exclude,
+		n:            s.autoReviewerCount(ctx, author.TeamName),
+		mode:         opts.AssignmentMode,
+	})
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
 
+	now := s.now()
 	reviewers := make([]string, 0, len(candidates))
+	assignments := make([]domain.ReviewerAssignment, 0, len(candidates))
+	logins := make([]string, 0, len(candidates))
 	for _, c := range candidates {
-		reviewers = append(reviewers, c.UserID)
+		reviewers = append(reviewers, c.user.UserID)
+		assignments = append(assignments, c.assignment(now))
+		logins = append(logins, c.user.Username)
 	}
 
-	now := time.Now().UTC()
 	pr.AssignedReviewers = reviewers
+	pr.Assignments = assignments
+	pr.AuthorTeam = author.TeamName
 	pr.Status = domain.StatusOpen
 	pr.CreatedAt = &now
+	pr.Version = 1
 
-	if err := s.repo.CreatePR(ctx, pr, reviewers); err != nil {
+	if err := s.repo.CreatePR(ctx, pr, assignments); err != nil {
 		return domain.PullRequest{}, err
 	}
 
+	s.syncCodeHost(pr, logins, nil)
+	s.publishAssignments(ctx, pr, reviewers)
+
 	return pr, nil
 }
 
-func (s *Service) MergePR(ctx context.Context, prID string) (domain.PullRequest, error) {
+// autoReviewerCount — сколько ревьюверов назначать автоматически: DefaultReviewers,
+// но не меньше минимума и не больше максимума команды автора.
+func (s *Service) autoReviewerCount(ctx context.Context, teamName string) int {
+	n := domain.DefaultReviewers
+	if team, err := s.repo.GetTeam(ctx, teamName); err == nil {
+		lower, upper := team.ReviewerBounds()
+		n = max(n, lower)
+		if upper >= 0 && upper < n {
+			n = upper
+		}
+	}
+	return n
+}
+
+func (s *Service) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	return s.repo.GetPR(ctx, prID)
+}
+
+func (s *Service) MergePR(ctx context.Context, prID string, expectedVersion int64) (domain.PullRequest, error) {
+	return retryStale(expectedVersion, func() (domain.PullRequest, error) {
+		return s.mergePR(ctx, prID, expectedVersion)
+	})
+}
+
+func (s *Service) mergePR(ctx context.Context, prID string, expectedVersion int64) (domain.PullRequest, error) {
 	pr, err := s.repo.GetPR(ctx, prID)
 	if err != nil {
 		return domain.PullRequest{}, domain.ErrNotFound
 	}
+	if err := checkVersion(pr, expectedVersion); err != nil {
+		return domain.PullRequest{}, err
+	}
 
 	if pr.Status == domain.StatusMerged {
 		return pr, nil
 	}
 
-	now := time.Now().UTC()
-	updated, err := s.repo.UpdatePRStatusMerged(ctx, prID, &now)
+	now := s.now()
+	updated, err := s.repo.UpdatePRStatusMerged(ctx, prID, &now, pr.Version)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -58,11 +109,23 @@ func (s *Service) MergePR(ctx context.Context, prID string) (domain.PullRequest,
 	return updated, nil
 }
 
-func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string) (domain.PullRequest, string, error) {
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string, expectedVersion int64) (domain.PullRequest, string, error) {
+	var replacedBy string
+	pr, err := retryStale(expectedVersion, func() (pr domain.PullRequest, err error) {
+		pr, replacedBy, err = s.reassignReviewer(ctx, prID, oldUserID, expectedVersion)
+		return pr, err
+	})
+	return pr, replacedBy, err
+}
+
+func (s *Service) reassignReviewer(ctx context.Context, prID, oldUserID string, expectedVersion int64) (domain.PullRequest, string, error) {
 	pr, err := s.repo.GetPR(ctx, prID)
 	if err != nil {
 		return domain.PullRequest{}, "", domain.ErrNotFound
 	}
+	if err := checkVersion(pr, expectedVersion); err != nil {
+		return domain.PullRequest{}, "", err
+	}
 
 	if pr.Status == domain.StatusMerged {
 		return domain.PullRequest{}, "", domain.ErrPrMerged
@@ -85,17 +148,285 @@ func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string)
 	}
 
 	pr.AssignedReviewers = append(pr.AssignedReviewers, author.UserID)
-	candidates, err := s.repo.ListActiveTeamMembers(ctx, author.TeamName, pr.AssignedReviewers, 1)
+	candidates, err := s.pickReviewers(ctx, reviewerQuery{author: author, exclude: pr.AssignedReviewers, n: 1})
 	if err != nil || len(candidates) == 0 {
 		return domain.PullRequest{}, "", domain.ErrNoCandidate
 	}
 
-	newReviewer := candidates[0].UserID
+	newReviewer := candidates[0]
 
-	pr, err = s.repo.ReplaceReviewer(ctx, prID, oldUserID, newReviewer)
+	pr, err = s.repo.ReplaceReviewer(ctx, prID, oldUserID, newReviewer.assignment(s.now()), pr.Version)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
 
-	return pr, newReviewer, nil
+	if oldUser, err := s.repo.GetUser(ctx, oldUserID); err == nil {
+		s.syncCodeHost(pr, []string{newReviewer.user.Username}, []string{oldUser.Username})
+	}
+	s.publishReassignment(ctx, pr, oldUserID, newReviewer.user.UserID)
+
+	return pr, newReviewer.user.UserID, nil
+}
+
+// ReassignReviewerTo заменяет ревьювера на выбранного вручную пользователя.
+func (s *Service) ReassignReviewerTo(ctx context.Context, prID, oldUserID, newUserID string, expectedVersion int64) (domain.PullRequest, error) {
+	return retryStale(expectedVersion, func() (domain.PullRequest, error) {
+		return s.reassignReviewerTo(ctx, prID, oldUserID, newUserID, expectedVersion)
+	})
+}
+
+func (s *Service) reassignReviewerTo(ctx context.Context, prID, oldUserID, newUserID string, expectedVersion int64) (domain.PullRequest, error) {
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if err := checkVersion(pr, expectedVersion); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if pr.Status == domain.StatusMerged {
+		return domain.PullRequest{}, domain.ErrPrMerged
+	}
+	if !slices.Contains(pr.AssignedReviewers, oldUserID) {
+		return domain.PullRequest{}, domain.ErrNotAssigned
+	}
+	if slices.Contains(pr.AssignedReviewers, newUserID) {
+		return domain.PullRequest{}, domain.ErrAlreadyAssigned
+	}
+
+	newUser, err := s.manualReviewer(ctx, pr, newUserID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	now := s.now()
+	pr, err = s.repo.ReplaceReviewer(ctx, prID, oldUserID, domain.ReviewerAssignment{
+		UserID:     newUserID,
+		Source:     domain.SourceManual,
+		AssignedAt: &now,
+	}, pr.Version)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if oldUser, err := s.repo.GetUser(ctx, oldUserID); err == nil {
+		s.syncCodeHost(pr, []string{newUser.Username}, []string{oldUser.Username})
+	}
+	s.publishReassignment(ctx, pr, oldUserID, newUserID)
+	return pr, nil
+}
+
+// AddReviewer вручную добавляет ревьювера сверх назначенных, не превышая максимум команды автора.
+func (s *Service) AddReviewer(ctx context.Context, prID, userID string, expectedVersion int64) (domain.PullRequest, error) {
+	return retryStale(expectedVersion, func() (domain.PullRequest, error) {
+		return s.addReviewer(ctx, prID, userID, expectedVersion)
+	})
+}
+
+func (s *Service) addReviewer(ctx context.Context, prID, userID string, expectedVersion int64) (domain.PullRequest, error) {
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if err := checkVersion(pr, expectedVersion); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if pr.Status == domain.StatusMerged {
+		return domain.PullRequest{}, domain.ErrPrMerged
+	}
+	if slices.Contains(pr.AssignedReviewers, userID) {
+		return domain.PullRequest{}, domain.ErrAlreadyAssigned
+	}
+
+	user, err := s.manualReviewer(ctx, pr, userID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	team, err := s.repo.GetTeam(ctx, pr.AuthorTeam)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if _, upper := team.ReviewerBounds(); upper >= 0 && len(pr.AssignedReviewers) >= upper {
+		return domain.PullRequest{}, domain.ErrReviewerLimit
+	}
+
+	now := s.now()
+	pr, err = s.repo.AddReviewer(ctx, prID, domain.ReviewerAssignment{
+		UserID:     userID,
+		Source:     domain.SourceManual,
+		AssignedAt: &now,
+	}, pr.Version)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	s.syncCodeHost(pr, []string{user.Username}, nil)
+	s.publishAssignments(ctx, pr, []string{userID})
+	return pr, nil
+}
+
+// RemoveReviewer снимает ревьювера без замены, если у PR останется не меньше минимума команды автора.
+func (s *Service) RemoveReviewer(ctx context.Context, prID, userID string, expectedVersion int64) (domain.PullRequest, error) {
+	return retryStale(expectedVersion, func() (domain.PullRequest, error) {
+		return s.removeReviewer(ctx, prID, userID, expectedVersion)
+	})
+}
+
+func (s *Service) removeReviewer(ctx context.Context, prID, userID string, expectedVersion int64) (domain.PullRequest, error) {
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	if err := checkVersion(pr, expectedVersion); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if pr.Status == domain.StatusMerged {
+		return domain.PullRequest{}, domain.ErrPrMerged
+	}
+	if !slices.Contains(pr.AssignedReviewers, userID) {
+		return domain.PullRequest{}, domain.ErrNotAssigned
+	}
+
+	team, err := s.repo.GetTeam(ctx, pr.AuthorTeam)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if lower, _ := team.ReviewerBounds(); len(pr.AssignedReviewers)-1 < lower {
+		return domain.PullRequest{}, domain.ErrReviewerLimit
+	}
+
+	pr, err = s.repo.RemoveReviewer(ctx, prID, userID, pr.Version)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if user, err := s.repo.GetUser(ctx, userID); err == nil {
+		s.syncCodeHost(pr, nil, []string{user.Username})
+	}
+	s.publish(ctx, domain.Event{
+		Type:          domain.EventReviewerRemoved,
+		PullRequestID: pr.PullRequestID,
+		UserID:        userID,
+		TeamName:      pr.AuthorTeam,
+	})
+	return pr, nil
+}
+
+func (s *Service) publishReassignment(ctx context.Context, pr domain.PullRequest, oldUserID, newUserID string) {
+	s.publish(ctx, domain.Event{
+		Type:          domain.EventReviewerReassigned,
+		PullRequestID: pr.PullRequestID,
+		UserID:        oldUserID,
+		TeamName:      pr.AuthorTeam,
+		ReplacedBy:    newUserID,
+	})
+}
+
+// checkVersion сверяет версию PR с ожидаемой клиентом; 0 означает, что клиент её не передал.
+// В транзакцию изменения всегда передаётся версия, прочитанная сервисом: проверки статуса
+// и числа ревьюверов сделаны на ней и не должны устареть к моменту записи.
+func checkVersion(pr domain.PullRequest, expectedVersion int64) error {
+	if expectedVersion != 0 && pr.Version != expectedVersion {
+		return domain.ErrVersionMismatch
+	}
+	return nil
+}
+
+// staleRetries — сколько раз операция выполняется заново, если PR изменился между
+// чтением и записью.
+const staleRetries = 3
+
+// retryStale повторяет изменение PR на свежих данных, если PR изменил параллельный запрос.
+// Клиенту, передавшему ожидаемую версию, сразу возвращается ErrVersionMismatch; клиент без
+// версии её не передавал, поэтому, если попытки кончились, получает ErrConcurrentUpdate.
+func retryStale(expectedVersion int64, op func() (domain.PullRequest, error)) (domain.PullRequest, error) {
+	for attempt := 1; ; attempt++ {
+		pr, err := op()
+		if expectedVersion != 0 || !errors.Is(err, domain.ErrVersionMismatch) {
+			return pr, err
+		}
+		if attempt == staleRetries {
+			return domain.PullRequest{}, domain.ErrConcurrentUpdate
+		}
+	}
+}
+
+// manualReviewer проверяет, что пользователя можно назначить на PR вручную: он активен,
+// не автор и состоит в команде автора, её командах-партнёрах или резервных пулах.
+func (s *Service) manualReviewer(ctx context.Context, pr domain.PullRequest, userID string) (domain.User, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.UserID == pr.AuthorID {
+		return domain.User{}, domain.ErrReviewerIsAuthor
+	}
+	if !user.IsActive {
+		return domain.User{}, domain.ErrUserInactive
+	}
+	if user.TeamName == pr.AuthorTeam {
+		return user, nil
+	}
+
+	team, err := s.repo.GetTeam(ctx, pr.AuthorTeam)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if slices.Contains(team.PartnerTeams, user.TeamName) {
+		return user, nil
+	}
+	for _, poolName := range team.FallbackPools {
+		pool, err := s.repo.GetReviewerPool(ctx, poolName)
+		if err == nil && slices.Contains(pool.Members, userID) {
+			return user, nil
+		}
+	}
+	return domain.User{}, domain.ErrNotTeamMember
+}
+
+const (
+	defaultPRPageSize = 50
+	maxPRPageSize     = 200
+)
+
+func (s *Service) ListPRs(ctx context.Context, filter domain.PRListFilter) (domain.PRPage, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = domain.SortByCreatedAt
+	}
+	if !filter.SortBy.Valid() {
+		return domain.PRPage{}, domain.ErrInvalidPRFilter
+	}
+	if filter.Status != "" && filter.Status != domain.StatusOpen && filter.Status != domain.StatusMerged {
+		return domain.PRPage{}, domain.ErrInvalidPRFilter
+	}
+	if c := filter.Cursor; c != nil && (c.SortBy != filter.SortBy || c.Desc != filter.Desc) {
+		return domain.PRPage{}, domain.ErrInvalidCursor
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPRPageSize
+	}
+	if filter.Limit > maxPRPageSize {
+		filter.Limit = maxPRPageSize
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++ // лишняя строка показывает, есть ли следующая страница
+	prs, err := s.repo.ListPRs(ctx, filter)
+	if err != nil {
+		return domain.PRPage{}, err
+	}
+
+	page := domain.PRPage{PullRequests: prs}
+	if len(prs) > pageSize {
+		page.PullRequests = prs[:pageSize]
+		last := page.PullRequests[pageSize-1]
+		cursor := domain.PRCursor{SortBy: filter.SortBy, Desc: filter.Desc, PullRequestID: last.PullRequestID}
+		if filter.SortBy == domain.SortByMergedAt {
+			cursor.Value = *last.MergedAt
+		} else {
+			cursor.Value = *last.CreatedAt
+		}
+		page.NextCursor = cursor.Encode()
+	}
+	return page, nil
 }
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"slices"
+)
+
+// SubmitReview фиксирует вердикт назначенного ревьювера по открытому PR.
+func (s *Service) SubmitReview(ctx context.Context, prID, userID string, verdict domain.ReviewVerdict) (domain.Review, error) {
+	if !verdict.Valid() {
+		return domain.Review{}, domain.ErrInvalidVerdict
+	}
+
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		return domain.Review{}, domain.ErrNotFound
+	}
+	if pr.Status == domain.StatusMerged {
+		return domain.Review{}, domain.ErrPrMerged
+	}
+	if !slices.Contains(pr.AssignedReviewers, userID) {
+		return domain.Review{}, domain.ErrNotAssigned
+	}
+
+	return s.repo.AddReview(ctx, domain.Review{
+		PullRequestID: prID,
+		UserID:        userID,
+		Verdict:       verdict,
+		SubmittedAt:   s.now(),
+	})
+}
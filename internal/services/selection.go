@@ -0,0 +1,303 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type candidate struct {
+	user       domain.User
+	source     domain.ReviewerSource
+	sourceName string
+}
+
+func (c candidate) assignment(at time.Time) domain.ReviewerAssignment {
+	return domain.ReviewerAssignment{
+		UserID:     c.user.UserID,
+		Source:     c.source,
+		SourceName: c.sourceName,
+		AssignedAt: &at,
+	}
+}
+
+type reviewerQuery struct {
+	author       domain.User
+	changedFiles []string
+	exclude      []string
+	n            int
+	mode         domain.AssignmentMode
+	// pending — назначения, ещё не записанные в базу (при массовом создании PR);
+	// учитываются в нагрузке наравне с открытыми ревью.
+	pending map[string]int
+}
+
+// pickReviewers подбирает до n ревьюверов: сначала владельцев изменённых файлов
+// по CODEOWNERS, затем активных участников команды автора, а если их не хватает —
+// из команд-партнёров и общих пулов в порядке, заданном для команды.
+// Ревьюверы в отпуске и достигшие лимита открытых ревью пропускаются; внутри команды
+// и пула предпочтение отдаётся наименее загруженным, а в режиме WORKING_HOURS —
+// в первую очередь тем, у кого сейчас рабочее время.
+func (s *Service) pickReviewers(ctx context.Context, q reviewerQuery) ([]candidate, error) {
+	author, n := q.author, q.n
+	if q.mode == "" {
+		q.mode = s.assignmentMode
+	}
+
+	now := s.now()
+	picked := make([]candidate, 0, n)
+	excluded := append([]string{}, q.exclude...)
+	take := func(users []domain.User, source domain.ReviewerSource, sourceName string, leastLoaded bool) error {
+		eligible := make([]domain.User, 0, len(users))
+		for _, u := range users {
+			if u.IsActive && !slices.Contains(excluded, u.UserID) {
+				eligible = append(eligible, u)
+			}
+		}
+		if len(eligible) == 0 || len(picked) == n {
+			return nil
+		}
+
+		eligible, err := s.filterAvailable(ctx, eligible)
+		if err != nil {
+			return err
+		}
+
+		load, err := s.reviewLoad(ctx, eligible)
+		if err != nil {
+			return err
+		}
+		for userID, n := range q.pending {
+			if l, ok := load[userID]; ok {
+				l.OpenReviews += n
+				load[userID] = l
+			}
+		}
+		if leastLoaded {
+			sort.SliceStable(eligible, func(i, j int) bool {
+				return load[eligible[i].UserID].OpenReviews < load[eligible[j].UserID].OpenReviews
+			})
+		}
+		if q.mode == domain.AssignmentWorkingHours {
+			sort.SliceStable(eligible, func(i, j int) bool {
+				return eligible[i].IsWorkingAt(now) && !eligible[j].IsWorkingAt(now)
+			})
+		}
+
+		for _, u := range eligible {
+			if len(picked) == n {
+				break
+			}
+			if l, ok := load[u.UserID]; ok && !l.HasCapacity() {
+				continue
+			}
+			picked = append(picked, candidate{user: u, source: source, sourceName: sourceName})
+			excluded = append(excluded, u.UserID)
+		}
+		return nil
+	}
+
+	owners, err := s.codeOwnerCandidates(ctx, author.TeamName, q.changedFiles)
+	if err != nil {
+		return nil, err
+	}
+	if err := take(owners, domain.SourceCodeOwners, "", false); err != nil {
+		return nil, err
+	}
+
+	if len(picked) < n {
+		members, err := s.repo.ListActiveTeamMembers(ctx, author.TeamName, excluded, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := take(members, domain.SourceTeam, author.TeamName, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(picked) == n {
+		return picked, nil
+	}
+
+	team, err := s.repo.GetTeam(ctx, author.TeamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return picked, nil
+		}
+		return nil, err
+	}
+
+	for _, partner := range team.PartnerTeams {
+		if len(picked) == n {
+			break
+		}
+		members, err := s.repo.ListActiveTeamMembers(ctx, partner, excluded, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := take(members, domain.SourcePartnerTeam, partner, true); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pool := range team.FallbackPools {
+		if len(picked) == n {
+			break
+		}
+		members, err := s.repo.ListActivePoolMembers(ctx, pool, excluded, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := take(members, domain.SourcePool, pool, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return picked, nil
+}
+
+func (s *Service) filterAvailable(ctx context.Context, users []domain.User) ([]domain.User, error) {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.UserID)
+	}
+
+	windows, err := s.repo.ListUnavailableAt(ctx, ids, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return users, nil
+	}
+
+	away := make(map[string]bool, len(windows))
+	for _, w := range windows {
+		away[w.UserID] = true
+	}
+
+	available := make([]domain.User, 0, len(users))
+	for _, u := range users {
+		if !away[u.UserID] {
+			available = append(available, u)
+		}
+	}
+	return available, nil
+}
+
+func (s *Service) reviewLoad(ctx context.Context, users []domain.User) (map[string]domain.UserLoad, error) {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.UserID)
+	}
+
+	loads, err := s.repo.GetReviewLoad(ctx, domain.ReviewLoadFilter{UserIDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[string]domain.UserLoad, len(loads))
+	for _, l := range loads {
+		byUser[l.UserID] = l
+	}
+	return byUser, nil
+}
+
+// codeOwnerCandidates возвращает владельцев изменённых файлов,
+// упорядоченных по числу файлов, которыми они владеют.
+func (s *Service) codeOwnerCandidates(ctx context.Context, teamName string, changedFiles []string) ([]domain.User, error) {
+	if len(changedFiles) == 0 {
+		return nil, nil
+	}
+
+	co, err := s.loadCodeOwners(ctx, teamName)
+	if err != nil || len(co.Rules) == 0 {
+		return nil, err
+	}
+
+	var owners []string
+	filesOwned := make(map[string]int)
+	for _, f := range changedFiles {
+		for _, o := range co.Owners(f) {
+			if filesOwned[o] == 0 {
+				owners = append(owners, o)
+			}
+			filesOwned[o]++
+		}
+	}
+
+	var logins []string
+	for _, o := range owners {
+		if !strings.HasPrefix(o, "@") || !strings.Contains(o, "/") {
+			logins = append(logins, strings.TrimPrefix(o, "@"))
+		}
+	}
+	byLogin := make(map[string]domain.User)
+	if len(logins) > 0 {
+		users, err := s.repo.ListUsersByLogins(ctx, logins)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			byLogin[u.UserID] = u
+			byLogin[u.Username] = u
+		}
+	}
+
+	var candidates []domain.User
+	score := make(map[string]int)
+	add := func(u domain.User, n int) {
+		if _, ok := score[u.UserID]; !ok {
+			candidates = append(candidates, u)
+		}
+		score[u.UserID] += n
+	}
+
+	for _, o := range owners {
+		name := strings.TrimPrefix(o, "@")
+		if i := strings.Index(name, "/"); i >= 0 && strings.HasPrefix(o, "@") {
+			// @org/team — владельцем считается команда сервиса с таким же именем
+			members, err := s.repo.ListActiveTeamMembers(ctx, name[i+1:], nil, 0)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range members {
+				add(m, filesOwned[o])
+			}
+			continue
+		}
+		if u, ok := byLogin[name]; ok {
+			add(u, filesOwned[o])
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return score[candidates[i].UserID] > score[candidates[j].UserID]
+	})
+	return candidates, nil
+}
+
+// loadCodeOwners берёт CODEOWNERS команды, а при его отсутствии — глобальный.
+func (s *Service) loadCodeOwners(ctx context.Context, teamName string) (domain.CodeOwners, error) {
+	file, err := s.repo.GetCodeOwners(ctx, teamName)
+	if errors.Is(err, domain.ErrNotFound) && teamName != "" {
+		file, err = s.repo.GetCodeOwners(ctx, "")
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.CodeOwners{}, nil
+		}
+		return domain.CodeOwners{}, err
+	}
+
+	co, err := domain.ParseCodeOwners(file.Content)
+	if err != nil {
+		log.Printf("stored CODEOWNERS for team %q is invalid: %v", file.TeamName, err)
+		return domain.CodeOwners{}, nil
+	}
+	return co, nil
+}
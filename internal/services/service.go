@@ -1,13 +1,93 @@
 package services
 
 import (
+	"PRService/internal/domain"
 	"PRService/internal/ports"
+	"context"
+	"sync"
+	"time"
 )
 
 type Service struct {
 	repo ports.Repository
+	now  func() time.Time
+
+	assignmentMode domain.AssignmentMode
+	events         ports.EventPublisher
+
+	codeHost           ports.CodeHostClient
+	codeHostAttempts   int
+	codeHostRetryDelay time.Duration
+
+	// background отменяется в Close; на нём выполняются фоновые запросы сервиса.
+	background context.Context
+	stop       context.CancelFunc
+	syncs      sync.WaitGroup
+}
+
+type Option func(*Service)
+
+func NewService(repo ports.Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:               repo,
+		now:                func() time.Time { return time.Now().UTC() },
+		assignmentMode:     domain.AssignmentDefault,
+		codeHostAttempts:   5,
+		codeHostRetryDelay: time.Second,
+	}
+	s.background, s.stop = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Close прерывает повторы синхронизации с хостингом кода и дожидается начатых запросов.
+func (s *Service) Close() {
+	s.stop()
+	s.syncs.Wait()
+}
+
+// WithClock подменяет источник текущего времени (для тестов и фоновых задач).
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// WithAssignmentMode задаёт режим выбора ревьюверов, если он не указан в запросе.
+func WithAssignmentMode(mode domain.AssignmentMode) Option {
+	return func(s *Service) {
+		if mode.Valid() {
+			s.assignmentMode = mode
+		}
+	}
+}
+
+func WithEventPublisher(p ports.EventPublisher) Option {
+	return func(s *Service) {
+		s.events = p
+	}
+}
+
+func (s *Service) publish(ctx context.Context, event domain.Event) {
+	if s.events == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = s.now()
+	}
+	s.events.Publish(ctx, event)
 }
 
-func NewService(repo ports.Repository) *Service {
-	return &Service{repo: repo}
+// publishAssignments сообщает о назначении reviewers на PR.
+func (s *Service) publishAssignments(ctx context.Context, pr domain.PullRequest, reviewers []string) {
+	for _, userID := range reviewers {
+		s.publish(ctx, domain.Event{
+			Type:          domain.EventReviewerAssigned,
+			PullRequestID: pr.PullRequestID,
+			UserID:        userID,
+			TeamName:      pr.AuthorTeam,
+		})
+	}
 }
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+type SLAReport struct {
+	Reminded   int `json:"reminded"`
+	Reassigned int `json:"reassigned"`
+}
+
+func (s *Service) SetTeamReviewSLA(ctx context.Context, teamName string, sla *domain.ReviewSLA) (domain.Team, error) {
+	if sla != nil && sla.ReassignAfter > 0 && sla.ReassignAfter <= sla.ReminderAfter {
+		return domain.Team{}, domain.ErrInvalidSLA
+	}
+
+	if err := s.repo.SetTeamReviewSLA(ctx, teamName, sla); err != nil {
+		return domain.Team{}, err
+	}
+	return s.GetTeam(ctx, teamName)
+}
+
+// CheckReviewSLA находит назначения на открытые PR старше SLA команды автора:
+// ревьюверу отправляется напоминание, а после второго порога ревью
+// переназначается так же, как через ReassignReviewer. Если кандидата нет,
+// это запоминается, и следующая попытка будет не раньше, чем через срок переназначения.
+func (s *Service) CheckReviewSLA(ctx context.Context) (SLAReport, error) {
+	now := s.now()
+	overdue, err := s.repo.ListOverdueAssignments(ctx, now)
+	if err != nil {
+		return SLAReport{}, err
+	}
+
+	var report SLAReport
+	for _, a := range overdue {
+		sla := a.SLA()
+		if sla.ReassignAfter > 0 && !a.AssignedAt.Add(sla.ReassignAfter).After(now) {
+			_, replacedBy, err := s.ReassignReviewer(ctx, a.PullRequestID, a.UserID, 0)
+			if err == nil {
+				s.publish(ctx, domain.Event{
+					Type:          domain.EventReviewEscalated,
+					PullRequestID: a.PullRequestID,
+					UserID:        a.UserID,
+					TeamName:      a.TeamName,
+					ReplacedBy:    replacedBy,
+					At:            now,
+				})
+				report.Reassigned++
+				continue
+			}
+			if !errors.Is(err, domain.ErrNoCandidate) {
+				log.Printf("review SLA: failed to reassign PR %s from %s: %v", a.PullRequestID, a.UserID, err)
+			} else if err := s.repo.MarkReassignFailed(ctx, a.PullRequestID, a.UserID, now); err != nil {
+				return report, err
+			}
+		}
+
+		if a.RemindedAt != nil {
+			continue
+		}
+		s.publish(ctx, domain.Event{
+			Type:          domain.EventReviewReminder,
+			PullRequestID: a.PullRequestID,
+			UserID:        a.UserID,
+			TeamName:      a.TeamName,
+			At:            now,
+		})
+		if err := s.repo.MarkReviewReminded(ctx, a.PullRequestID, a.UserID, now); err != nil {
+			return report, err
+		}
+		report.Reminded++
+	}
+
+	return report, nil
+}
+
+// RunSLAScheduler периодически вызывает CheckReviewSLA до отмены ctx.
+func (s *Service) RunSLAScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CheckReviewSLA(ctx); err != nil {
+			log.Printf("review SLA scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,21 +3,114 @@ package services
 import (
 	"PRService/internal/domain"
 	"context"
+	"fmt"
+	"time"
 )
 
-func (s *Service) GetStats(ctx context.Context) (domain.Stats, error) {
-	reviewerStats, err := s.repo.GetReviewerStats(ctx)
+func validateStatsFilter(filter domain.StatsFilter) error {
+	if filter.Status != "" && filter.Status != domain.StatusOpen && filter.Status != domain.StatusMerged {
+		return domain.ErrInvalidStatsFilter
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return domain.ErrInvalidStatsFilter
+	}
+	return nil
+}
+
+func (s *Service) GetStats(ctx context.Context, filter domain.StatsFilter) (domain.Stats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return domain.Stats{}, err
+	}
+
+	reviewerStats, err := s.repo.GetReviewerStats(ctx, filter)
 	if err != nil {
 		return domain.Stats{}, err
 	}
 
-	prStats, err := s.repo.GetPRStats(ctx)
+	prStats, err := s.repo.GetPRStats(ctx, filter)
 	if err != nil {
 		return domain.Stats{}, err
 	}
 
-	return domain.Stats{
-		ReviewerAssignments: reviewerStats,
-		PRAssignments:       prStats,
+	teamStats, err := s.repo.GetTeamStats(ctx, filter)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+
+	stats := domain.Stats{
+		ReviewerAssignments:     make(map[string]int, len(reviewerStats)),
+		ReviewerOpenAssignments: make(map[string]int, len(reviewerStats)),
+		PRAssignments:           prStats,
+		Teams:                   teamStats,
+	}
+	for userID, c := range reviewerStats {
+		stats.ReviewerAssignments[userID] = c.Total
+		stats.ReviewerOpenAssignments[userID] = c.Open
+	}
+	return stats, nil
+}
+
+func (s *Service) GetCycleTimeStats(ctx context.Context, filter domain.StatsFilter) (domain.CycleTimeStats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return domain.CycleTimeStats{}, err
+	}
+
+	teams, weekly, err := s.repo.GetTeamCycleTimes(ctx, filter)
+	if err != nil {
+		return domain.CycleTimeStats{}, err
+	}
+
+	reviewers, err := s.repo.GetReviewerCycleTimes(ctx, filter)
+	if err != nil {
+		return domain.CycleTimeStats{}, err
+	}
+
+	return domain.CycleTimeStats{
+		Teams:     teams,
+		Reviewers: reviewers,
+		Weekly:    weekly,
 	}, nil
 }
+
+// fairnessWindow — период отчёта о справедливости по умолчанию.
+const fairnessWindow = 30 * 24 * time.Hour
+
+// GetFairnessReport сравнивает темп назначений ревьюверов внутри команд.
+// Команда здесь — команда ревьювера. Фильтр по статусу PR не поддерживается:
+// назначение засчитывается ревьюверу независимо от того, чем закончился PR.
+func (s *Service) GetFairnessReport(ctx context.Context, filter domain.StatsFilter) (domain.FairnessReport, error) {
+	if filter.Status != "" {
+		return domain.FairnessReport{}, fmt.Errorf("%w: status is not supported", domain.ErrInvalidStatsFilter)
+	}
+	to := s.now()
+	if filter.To != nil {
+		to = *filter.To
+	}
+	from := to.Add(-fairnessWindow)
+	if filter.From != nil {
+		from = *filter.From
+	}
+	if !to.After(from) {
+		return domain.FairnessReport{}, domain.ErrInvalidStatsFilter
+	}
+
+	activity, err := s.repo.ListReviewerActivity(ctx, from, to, filter.TeamName)
+	if err != nil {
+		return domain.FairnessReport{}, err
+	}
+
+	byTeam := make(map[string][]domain.ReviewerActivity)
+	for _, a := range activity {
+		byTeam[a.TeamName] = append(byTeam[a.TeamName], a)
+	}
+
+	report := domain.FairnessReport{
+		From:  from,
+		To:    to,
+		Teams: make(map[string]domain.TeamFairness, len(byTeam)),
+	}
+	for team, members := range byTeam {
+		report.Teams[team] = domain.NewTeamFairness(members)
+	}
+	return report, nil
+}
@@ -4,10 +4,21 @@ import (
 	"PRService/internal/domain"
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"time"
 )
 
 func (s *Service) CreateTeam(ctx context.Context, team domain.Team) error {
+	if err := validateReviewerLimits(team.MinReviewers, team.MaxReviewers); err != nil {
+		return err
+	}
+	for _, m := range team.Members {
+		if err := validateSchedule(m.Timezone, m.WorkingHours); err != nil {
+			return err
+		}
+	}
+
 	err := s.repo.CreateTeam(ctx, team)
 	if err != nil {
 		if errors.Is(err, domain.ErrTeamExists) {
@@ -20,10 +31,13 @@ func (s *Service) CreateTeam(ctx context.Context, team domain.Team) error {
 	users := make([]domain.User, 0, len(team.Members))
 	for _, m := range team.Members {
 		users = append(users, domain.User{
-			UserID:   m.UserID,
-			Username: m.Username,
-			TeamName: team.TeamName,
-			IsActive: m.IsActive,
+			UserID:         m.UserID,
+			Username:       m.Username,
+			TeamName:       team.TeamName,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: m.MaxOpenReviews,
+			Timezone:       m.Timezone,
+			WorkingHours:   m.WorkingHours,
 		})
 	}
 
@@ -35,10 +49,137 @@ func (s *Service) CreateTeam(ctx context.Context, team domain.Team) error {
 	return nil
 }
 
+const (
+	defaultTeamPageSize = 50
+	maxTeamPageSize     = 200
+)
+
+// ListTeams возвращает страницу команд без участников.
+func (s *Service) ListTeams(ctx context.Context, filter domain.TeamListFilter) (domain.TeamPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTeamPageSize
+	}
+	if filter.Limit > maxTeamPageSize {
+		filter.Limit = maxTeamPageSize
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++
+	teams, err := s.repo.ListTeamsPage(ctx, filter)
+	if err != nil {
+		return domain.TeamPage{}, err
+	}
+
+	page := domain.TeamPage{Teams: teams}
+	if len(teams) > pageSize {
+		page.Teams = teams[:pageSize]
+		page.NextCursor = domain.EncodeTeamCursor(page.Teams[pageSize-1].TeamName)
+	}
+	return page, nil
+}
+
 func (s *Service) GetTeam(ctx context.Context, name string) (domain.Team, error) {
 	team, err := s.repo.GetTeam(ctx, name)
 	if err != nil {
 		return domain.Team{}, domain.ErrNotFound
 	}
+
+	ids := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		ids = append(ids, m.UserID)
+	}
+	windows, err := s.repo.ListUnavailableAt(ctx, ids, s.now())
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	until := make(map[string]time.Time)
+	for _, w := range windows {
+		if w.EndsAt.After(until[w.UserID]) {
+			until[w.UserID] = w.EndsAt
+		}
+	}
+	for i := range team.Members {
+		end, away := until[team.Members[i].UserID]
+		team.Members[i].IsAvailable = !away
+		if away {
+			team.Members[i].UnavailableUntil = &end
+		}
+	}
+
 	return team, nil
 }
+
+func (s *Service) SetTeamFallback(ctx context.Context, teamName string, partnerTeams, pools []string) (domain.Team, error) {
+	if _, err := s.repo.GetTeam(ctx, teamName); err != nil {
+		return domain.Team{}, domain.ErrNotFound
+	}
+	for _, partner := range partnerTeams {
+		if _, err := s.repo.GetTeam(ctx, partner); err != nil {
+			return domain.Team{}, domain.ErrNotFound
+		}
+	}
+	for _, pool := range pools {
+		if _, err := s.repo.GetReviewerPool(ctx, pool); err != nil {
+			return domain.Team{}, domain.ErrNotFound
+		}
+	}
+
+	if err := s.repo.SetTeamFallback(ctx, teamName, partnerTeams, pools); err != nil {
+		return domain.Team{}, err
+	}
+	return s.repo.GetTeam(ctx, teamName)
+}
+
+func (s *Service) SaveReviewerPool(ctx context.Context, pool domain.ReviewerPool) (domain.ReviewerPool, error) {
+	if err := s.repo.SaveReviewerPool(ctx, pool); err != nil {
+		return domain.ReviewerPool{}, err
+	}
+	return s.repo.GetReviewerPool(ctx, pool.PoolName)
+}
+
+func (s *Service) GetReviewerPool(ctx context.Context, poolName string) (domain.ReviewerPool, error) {
+	return s.repo.GetReviewerPool(ctx, poolName)
+}
+
+func (s *Service) SetTeamMaxOpenReviews(ctx context.Context, teamName string, limit *int) (domain.Team, error) {
+	if err := s.repo.SetTeamMaxOpenReviews(ctx, teamName, limit); err != nil {
+		return domain.Team{}, err
+	}
+	return s.repo.GetTeam(ctx, teamName)
+}
+
+func (s *Service) SetTeamReviewerLimits(ctx context.Context, teamName string, minReviewers, maxReviewers *int) (domain.Team, error) {
+	if err := validateReviewerLimits(minReviewers, maxReviewers); err != nil {
+		return domain.Team{}, err
+	}
+	if err := s.repo.SetTeamReviewerLimits(ctx, teamName, minReviewers, maxReviewers); err != nil {
+		return domain.Team{}, err
+	}
+	return s.repo.GetTeam(ctx, teamName)
+}
+
+func validateReviewerLimits(minReviewers, maxReviewers *int) error {
+	if minReviewers != nil && *minReviewers < 0 {
+		return domain.ErrInvalidLimits
+	}
+	if maxReviewers != nil && *maxReviewers < 1 {
+		return domain.ErrInvalidLimits
+	}
+	if minReviewers != nil && maxReviewers != nil && *minReviewers > *maxReviewers {
+		return domain.ErrInvalidLimits
+	}
+	return nil
+}
+
+func validateSchedule(timezone string, hours *domain.WorkingHours) error {
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidSchedule, timezone)
+		}
+	}
+	if hours != nil {
+		return hours.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+func (s *Service) AddUnavailability(ctx context.Context, u domain.Unavailability) (domain.Unavailability, error) {
+	if u.Kind == "" {
+		u.Kind = domain.UnavailabilityVacation
+	}
+	if !u.Kind.Valid() || !u.EndsAt.After(u.StartsAt) {
+		return domain.Unavailability{}, domain.ErrInvalidUnavailability
+	}
+
+	if _, err := s.repo.GetUser(ctx, u.UserID); err != nil {
+		return domain.Unavailability{}, domain.ErrNotFound
+	}
+
+	return s.repo.AddUnavailability(ctx, u)
+}
+
+// ListUnavailability возвращает текущие и будущие периоды отсутствия пользователя.
+func (s *Service) ListUnavailability(ctx context.Context, userID string) ([]domain.Unavailability, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, domain.ErrNotFound
+	}
+	return s.repo.ListUnavailability(ctx, userID, s.now())
+}
+
+func (s *Service) DeleteUnavailability(ctx context.Context, id int64) error {
+	return s.repo.DeleteUnavailability(ctx, id)
+}
+
+// ReassignUnavailable переназначает открытые ревью пользователей, у которых начался
+// период отсутствия. Ревью, которые некому передать, остаются за пользователем и попадают
+// в результат, а период всё равно помечается обработанным: при следующем запуске кандидатов
+// не прибавится. Непредвиденные ошибки оставляют период необработанным до следующего запуска.
+func (s *Service) ReassignUnavailable(ctx context.Context) (map[string]string, error) {
+	windows, err := s.repo.ListStartedUnavailability(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]string)
+	for _, w := range windows {
+		prs, err := s.repo.ListPRsByReviewer(ctx, w.UserID)
+		if err != nil {
+			results[w.UserID] = fmt.Sprintf("failed to list PRs: %v", err)
+			continue
+		}
+
+		var failed []string
+		retry := false
+		for _, pr := range prs {
+			if pr.Status != domain.StatusOpen {
+				continue
+			}
+			if _, _, err := s.ReassignReviewer(ctx, pr.PullRequestID, w.UserID, 0); err != nil {
+				failed = append(failed, fmt.Sprintf("PR %s: %v", pr.PullRequestID, err))
+				// отсутствие кандидата и изменившийся PR повтор не исправит
+				if !errors.Is(err, domain.ErrNoCandidate) && !errors.Is(err, domain.ErrPrMerged) && !errors.Is(err, domain.ErrNotAssigned) {
+					retry = true
+				}
+			}
+		}
+		if retry {
+			results[w.UserID] = "failed to reassign " + strings.Join(failed, "; ")
+			continue
+		}
+
+		if err := s.repo.MarkUnavailabilityReassigned(ctx, w.ID, s.now()); err != nil {
+			results[w.UserID] = fmt.Sprintf("failed to mark window %d: %v", w.ID, err)
+			continue
+		}
+		if len(failed) > 0 {
+			results[w.UserID] = "not reassigned " + strings.Join(failed, "; ")
+			continue
+		}
+		results[w.UserID] = "success"
+	}
+
+	return results, nil
+}
+
+// RunUnavailabilityWatcher периодически вызывает ReassignUnavailable до отмены ctx.
+func (s *Service) RunUnavailabilityWatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := s.ReassignUnavailable(ctx)
+		if err != nil {
+			log.Printf("unavailability watcher: %v", err)
+		}
+		for userID, res := range results {
+			if res != "success" {
+				log.Printf("unavailability watcher: user %s: %s", userID, res)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
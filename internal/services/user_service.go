@@ -15,6 +15,30 @@ func (s *Service) SetActive(ctx context.Context, userID string, isActive bool) (
 	return user, nil
 }
 
+func (s *Service) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (domain.User, error) {
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return s.repo.SetUserMaxOpenReviews(ctx, userID, limit)
+}
+
+// GetReviewLoad возвращает нагрузку ревьюверов, сгруппированную по командам.
+func (s *Service) GetReviewLoad(ctx context.Context, teamName string) (map[string][]domain.UserLoad, error) {
+	loads, err := s.repo.GetReviewLoad(ctx, domain.ReviewLoadFilter{TeamName: teamName})
+	if err != nil {
+		return nil, err
+	}
+	if teamName != "" && len(loads) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	byTeam := make(map[string][]domain.UserLoad)
+	for _, l := range loads {
+		byTeam[l.TeamName] = append(byTeam[l.TeamName], l)
+	}
+	return byTeam, nil
+}
+
 func (s *Service) GetPRsForReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error) {
 	return s.repo.ListPRsByReviewer(ctx, userID)
 }
@@ -31,7 +55,7 @@ func (s *Service) DeactivateUsers(ctx context.Context, userIDs []string) (map[st
 
 		canDeactivate := true
 		for _, pr := range prs {
-			_, _, err := s.ReassignReviewer(ctx, pr.PullRequestID, userID)
+			_, _, err := s.ReassignReviewer(ctx, pr.PullRequestID, userID, 0)
 			if err != nil {
 				if errors.Is(err, domain.ErrNoCandidate) {
 					results[userID] = fmt.Sprintf("PR %s has no available replacement", pr.PullRequestID)
@@ -55,3 +79,66 @@ func (s *Service) DeactivateUsers(ctx context.Context, userIDs []string) (map[st
 
 	return results, nil
 }
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+)
+
+func (s *Service) GetUser(ctx context.Context, userID string) (domain.User, error) {
+	return s.repo.GetUser(ctx, userID)
+}
+
+func (s *Service) ListUsers(ctx context.Context, filter domain.UserListFilter) (domain.UserPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	if filter.Limit > maxUserPageSize {
+		filter.Limit = maxUserPageSize
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++
+	users, err := s.repo.ListUsers(ctx, filter)
+	if err != nil {
+		return domain.UserPage{}, err
+	}
+
+	page := domain.UserPage{Users: users}
+	if len(users) > pageSize {
+		page.Users = users[:pageSize]
+		page.NextCursor = domain.EncodeUserCursor(page.Users[pageSize-1].UserID)
+	}
+	return page, nil
+}
+
+// CreateUser добавляет одного пользователя в существующую команду.
+func (s *Service) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
+	if user.UserID == "" || user.Username == "" || user.TeamName == "" {
+		return domain.User{}, domain.ErrInvalidUser
+	}
+	if user.MaxOpenReviews != nil && *user.MaxOpenReviews < 0 {
+		return domain.User{}, domain.ErrInvalidUser
+	}
+	if err := validateSchedule(user.Timezone, user.WorkingHours); err != nil {
+		return domain.User{}, err
+	}
+	return s.repo.CreateUser(ctx, user)
+}
+
+func (s *Service) UpdateUser(ctx context.Context, userID string, update domain.UserUpdate) (domain.User, error) {
+	if update.Username != nil && *update.Username == "" {
+		return domain.User{}, domain.ErrInvalidUser
+	}
+	if update.MaxOpenReviews != nil && *update.MaxOpenReviews < 0 {
+		return domain.User{}, domain.ErrInvalidUser
+	}
+	timezone := ""
+	if update.Timezone != nil {
+		timezone = *update.Timezone
+	}
+	if err := validateSchedule(timezone, update.WorkingHours); err != nil {
+		return domain.User{}, err
+	}
+	return s.repo.UpdateUser(ctx, userID, update)
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Методы этого файла обращаются к /admin/* и требуют WithToken(ADMIN_TOKEN).
+// POST-запросы к ним не повторяются: у административных эндпоинтов нет ключей идемпотентности.
+
+// Export пишет выгрузку всех данных в NDJSON в w.
+func (c *Client) Export(ctx context.Context, w io.Writer) error {
+	resp, err := c.send(ctx, http.MethodGet, "/admin/export", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return newError(resp.StatusCode, string(msg))
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Import загружает выгрузку Export из r.
+func (c *Client) Import(ctx context.Context, r io.Reader, policy ConflictPolicy) (ImportStats, error) {
+	query := url.Values{}
+	setQuery(query, "on_conflict", string(policy))
+
+	resp, err := c.send(ctx, http.MethodPost, "/admin/import", query, r, http.Header{
+		"Content-Type": {"application/x-ndjson"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Stats ImportStats `json:"stats"`
+	}
+	err = decodeResponse(resp, &out)
+	return out.Stats, err
+}
+
+func (c *Client) MigrationStatus(ctx context.Context) (MigrationStatus, error) {
+	var status MigrationStatus
+	err := c.get(ctx, "/admin/migrations", nil, &status)
+	return status, err
+}
+
+// MigrateUp применяет недостающие миграции и возвращает новое состояние схемы.
+func (c *Client) MigrateUp(ctx context.Context) (MigrationStatus, error) {
+	var status MigrationStatus
+	err := c.do(ctx, request{method: http.MethodPost, path: "/admin/migrations/up"}, &status)
+	return status, err
+}
@@ -0,0 +1,208 @@
+// Package client — типизированный клиент HTTP API сервиса назначения ревьюверов.
+//
+// Ошибки API возвращаются как *Error; errors.Is сопоставляет их с ошибками пакета,
+// например ErrNotFound или ErrPrMerged.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	baseURL    string
+	token      string
+	http       *http.Client
+	attempts   int
+	retryDelay time.Duration
+}
+
+type Option func(*Client)
+
+// WithToken передаёт токен в заголовке Authorization: Bearer — для лимитов на токен и /admin/*.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http = hc
+		}
+	}
+}
+
+// WithRetry задаёт число попыток и начальную паузу между ними; пауза удваивается,
+// если сервер не прислал Retry-After. attempts = 1 отключает повторы.
+func WithRetry(attempts int, delay time.Duration) Option {
+	return func(c *Client) {
+		if attempts > 0 {
+			c.attempts = attempts
+		}
+		if delay > 0 {
+			c.retryDelay = delay
+		}
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		http:       &http.Client{Timeout: 30 * time.Second},
+		attempts:   3,
+		retryDelay: 200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	header http.Header
+	// retry разрешает повтор POST: запросы к API повторяются с тем же Idempotency-Key,
+	// а у /admin/* ключей идемпотентности нет.
+	retry bool
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, request{method: http.MethodGet, path: path, query: query, retry: true}, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, request{method: http.MethodPost, path: path, body: body, retry: true}, out)
+}
+
+// postVersioned передаёт ожидаемую версию PR в If-Match; 0 — без проверки.
+func (c *Client) postVersioned(ctx context.Context, path string, expectedVersion int64, body, out interface{}) error {
+	req := request{method: http.MethodPost, path: path, body: body, retry: true}
+	if expectedVersion != 0 {
+		req.header = http.Header{"If-Match": {`"` + strconv.FormatInt(expectedVersion, 10) + `"`}}
+	}
+	return c.do(ctx, req, out)
+}
+
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return err
+		}
+	}
+
+	header := req.header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if req.method == http.MethodPost && req.retry && header.Get("Idempotency-Key") == "" {
+		key, err := idempotencyKey()
+		if err != nil {
+			return err
+		}
+		header.Set("Idempotency-Key", key)
+	}
+
+	attempts := c.attempts
+	if !req.retry {
+		attempts = 1
+	}
+	delay := c.retryDelay
+	for attempt := 1; ; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		retry, retryAfter := false, delay
+		resp, err := c.send(ctx, req.method, req.path, req.query, reader, header)
+		if err != nil {
+			// сбой соединения: запрос мог не дойти до сервера
+			retry = ctx.Err() == nil
+		} else {
+			err = decodeResponse(resp, out)
+			var apiErr *Error
+			if errors.As(err, &apiErr) {
+				retry = apiErr.retryable()
+				if v, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && v >= 0 {
+					retryAfter = time.Duration(v) * time.Second
+				}
+			}
+		}
+		if err == nil || !retry || attempt >= attempts {
+			return err
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body io.Reader, header http.Header) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.http.Do(req)
+}
+
+// decodeResponse закрывает тело ответа; out == nil — тело не нужно.
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return newError(resp.StatusCode, string(msg))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func idempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error — ответ API с кодом не 2xx. Code — код ошибки из тела ответа (PR_NOT_FOUND,
+// NO_CANDIDATE, ...), Message — тело целиком.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func newError(status int, body string) *Error {
+	msg := strings.TrimSpace(body)
+	code, _, _ := strings.Cut(msg, ":")
+	return &Error{StatusCode: status, Code: code, Message: msg}
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap позволяет errors.Is(err, ErrNotFound) и подобные проверки.
+func (e *Error) Unwrap() []error {
+	return codeErrors[e.Code]
+}
+
+func (e *Error) retryable() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	// первый запрос с тем же ключом идемпотентности ещё выполняется
+	return e.Code == "IDEMPOTENCY_KEY_IN_PROGRESS"
+}
+
+// codeErrors сопоставляет коды ответов API ошибкам пакета. Обработчики отдают
+// ErrNotFound под кодом конкретной сущности, поэтому *_NOT_FOUND разворачиваются в него.
+var codeErrors = map[string][]error{
+	"TEAM_EXISTS":          {ErrTeamExists},
+	"USER_EXISTS":          {ErrUserExists},
+	"PR_EXISTS":            {ErrPrExists},
+	"PR_MERGED":            {ErrPrMerged},
+	"NOT_ASSIGNED":         {ErrNotAssigned},
+	"NO_CANDIDATE":         {ErrNoCandidate},
+	"ALREADY_ASSIGNED":     {ErrAlreadyAssigned},
+	"NOT_TEAM_MEMBER":      {ErrNotTeamMember},
+	"REVIEWER_IS_AUTHOR":   {ErrReviewerIsAuthor},
+	"USER_INACTIVE":        {ErrUserInactive},
+	"REVIEWER_LIMIT":       {ErrReviewerLimit},
+	"PR_VERSION_MISMATCH":  {ErrVersionMismatch},
+	"PR_CONCURRENT_UPDATE": {ErrConcurrentUpdate},
+	"IMPORT_CONFLICT":      {ErrImportConflict},
+
+	"NOT_FOUND":                {ErrNotFound},
+	"TEAM_NOT_FOUND":           {ErrTeamNotFound, ErrNotFound},
+	"USER_NOT_FOUND":           {ErrNotFound},
+	"PR_NOT_FOUND":             {ErrNotFound},
+	"POOL_NOT_FOUND":           {ErrNotFound},
+	"CODEOWNERS_NOT_FOUND":     {ErrNotFound},
+	"UNAVAILABILITY_NOT_FOUND": {ErrNotFound},
+	"AUTHOR_OR_TEAM_NOT_FOUND": {ErrNotFound},
+	"TEAM_OR_POOL_NOT_FOUND":   {ErrNotFound},
+
+	"INVALID_CODEOWNERS":      {ErrInvalidCodeOwners},
+	"INVALID_UNAVAILABILITY":  {ErrInvalidUnavailability},
+	"INVALID_SCHEDULE":        {ErrInvalidSchedule},
+	"INVALID_SLA":             {ErrInvalidSLA},
+	"INVALID_STATS_FILTER":    {ErrInvalidStatsFilter},
+	"INVALID_VERDICT":         {ErrInvalidVerdict},
+	"INVALID_CURSOR":          {ErrInvalidCursor},
+	"INVALID_PR_FILTER":       {ErrInvalidPRFilter},
+	"INVALID_USER":            {ErrInvalidUser},
+	"INVALID_REVIEWER_LIMITS": {ErrInvalidLimits},
+	"INVALID_EXPORT":          {ErrInvalidExport},
+	"INVALID_BULK_INPUT":      {ErrInvalidBulkInput},
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CreatePRRequest — тело /pullRequest/create. Repository и PRNumber задаются вместе
+// и включают синхронизацию ревьюверов с хостингом кода.
+type CreatePRRequest struct {
+	PullRequestID   string         `json:"pull_request_id"`
+	PullRequestName string         `json:"pull_request_name"`
+	AuthorID        string         `json:"author_id"`
+	Repository      *string        `json:"repository,omitempty"`
+	PRNumber        *int           `json:"pr_number,omitempty"`
+	ChangedFiles    []string       `json:"changed_files,omitempty"`
+	AssignmentMode  AssignmentMode `json:"assignment_mode,omitempty"`
+}
+
+type prResponse struct {
+	PR PullRequest `json:"pr"`
+}
+
+func (c *Client) CreatePR(ctx context.Context, req CreatePRRequest) (PullRequest, error) {
+	var resp prResponse
+	err := c.post(ctx, "/pullRequest/create", req, &resp)
+	return resp.PR, err
+}
+
+// BulkCreatePRs загружает PR пачкой; результат — "success" или причина отказа по каждому PR.
+func (c *Client) BulkCreatePRs(ctx context.Context, items []BulkPRItem) (map[string]string, error) {
+	var resp struct {
+		Results map[string]string `json:"results"`
+	}
+	err := c.post(ctx, "/pullRequest/bulkCreate", items, &resp)
+	return resp.Results, err
+}
+
+func (c *Client) GetPR(ctx context.Context, prID string) (PullRequest, error) {
+	var resp prResponse
+	err := c.get(ctx, "/pullRequest/get", url.Values{"pull_request_id": {prID}}, &resp)
+	return resp.PR, err
+}
+
+// ListPRs возвращает страницу PR. Следующая страница — filter.Cursor из
+// DecodePRCursor(page.NextCursor).
+func (c *Client) ListPRs(ctx context.Context, filter PRListFilter) (PRPage, error) {
+	query := url.Values{}
+	setQuery(query, "author_id", filter.AuthorID)
+	setQuery(query, "reviewer_id", filter.ReviewerID)
+	setQuery(query, "team_name", filter.TeamName)
+	setQuery(query, "status", string(filter.Status))
+	setQuery(query, "sort", string(filter.SortBy))
+	setTimeQuery(query, "created_from", filter.CreatedFrom)
+	setTimeQuery(query, "created_to", filter.CreatedTo)
+	setTimeQuery(query, "merged_from", filter.MergedFrom)
+	setTimeQuery(query, "merged_to", filter.MergedTo)
+	if filter.Desc {
+		query.Set("order", "desc")
+	} else {
+		query.Set("order", "asc")
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.Cursor != nil {
+		query.Set("cursor", filter.Cursor.Encode())
+	}
+
+	var page PRPage
+	err := c.get(ctx, "/pullRequest/list", query, &page)
+	return page, err
+}
+
+// MergePR мержит PR; expectedVersion != 0 — только если PR не менялся с этой версии.
+func (c *Client) MergePR(ctx context.Context, prID string, expectedVersion int64) (PullRequest, error) {
+	var resp prResponse
+	err := c.postVersioned(ctx, "/pullRequest/merge", expectedVersion, map[string]string{
+		"pull_request_id": prID,
+	}, &resp)
+	return resp.PR, err
+}
+
+// ReassignReviewer заменяет ревьювера автоматически подобранным и возвращает его id.
+func (c *Client) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, expectedVersion int64) (PullRequest, string, error) {
+	return c.reassign(ctx, prID, oldReviewerID, "", expectedVersion)
+}
+
+// ReassignReviewerTo заменяет ревьювера на newReviewerID.
+func (c *Client) ReassignReviewerTo(ctx context.Context, prID, oldReviewerID, newReviewerID string, expectedVersion int64) (PullRequest, error) {
+	pr, _, err := c.reassign(ctx, prID, oldReviewerID, newReviewerID, expectedVersion)
+	return pr, err
+}
+
+func (c *Client) reassign(ctx context.Context, prID, oldReviewerID, newReviewerID string, expectedVersion int64) (PullRequest, string, error) {
+	body := map[string]string{
+		"pull_request_id": prID,
+		"old_reviewer_id": oldReviewerID,
+	}
+	if newReviewerID != "" {
+		body["new_reviewer_id"] = newReviewerID
+	}
+
+	var resp struct {
+		PR         PullRequest `json:"pr"`
+		ReplacedBy string      `json:"replaced_by"`
+	}
+	err := c.postVersioned(ctx, "/pullRequest/reassign", expectedVersion, body, &resp)
+	return resp.PR, resp.ReplacedBy, err
+}
+
+func (c *Client) AddReviewer(ctx context.Context, prID, reviewerID string, expectedVersion int64) (PullRequest, error) {
+	var resp prResponse
+	err := c.postVersioned(ctx, "/pullRequest/reviewers/add", expectedVersion, map[string]string{
+		"pull_request_id": prID,
+		"reviewer_id":     reviewerID,
+	}, &resp)
+	return resp.PR, err
+}
+
+func (c *Client) RemoveReviewer(ctx context.Context, prID, reviewerID string, expectedVersion int64) (PullRequest, error) {
+	var resp prResponse
+	err := c.postVersioned(ctx, "/pullRequest/reviewers/remove", expectedVersion, map[string]string{
+		"pull_request_id": prID,
+		"reviewer_id":     reviewerID,
+	}, &resp)
+	return resp.PR, err
+}
+
+func (c *Client) SubmitReview(ctx context.Context, prID, reviewerID string, verdict ReviewVerdict) (Review, error) {
+	var resp struct {
+		Review Review `json:"review"`
+	}
+	err := c.post(ctx, "/pullRequest/review", map[string]string{
+		"pull_request_id": prID,
+		"reviewer_id":     reviewerID,
+		"verdict":         string(verdict),
+	}, &resp)
+	return resp.Review, err
+}
+
+func setTimeQuery(query url.Values, name string, t *time.Time) {
+	if t != nil {
+		query.Set(name, t.Format(time.RFC3339Nano))
+	}
+}
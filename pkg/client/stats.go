@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+func (c *Client) GetStats(ctx context.Context, filter StatsFilter) (Stats, error) {
+	var stats Stats
+	err := c.get(ctx, "/stats", statsQuery(filter), &stats)
+	return stats, err
+}
+
+func (c *Client) GetCycleTimeStats(ctx context.Context, filter StatsFilter) (CycleTimeStats, error) {
+	var stats CycleTimeStats
+	err := c.get(ctx, "/stats/cycle-time", statsQuery(filter), &stats)
+	return stats, err
+}
+
+func (c *Client) GetFairnessReport(ctx context.Context, filter StatsFilter) (FairnessReport, error) {
+	var report FairnessReport
+	err := c.get(ctx, "/stats/fairness", statsQuery(filter), &report)
+	return report, err
+}
+
+func statsQuery(filter StatsFilter) url.Values {
+	query := url.Values{}
+	setQuery(query, "team_name", filter.TeamName)
+	setQuery(query, "status", string(filter.Status))
+	setTimeQuery(query, "from", filter.From)
+	setTimeQuery(query, "to", filter.To)
+	return query
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+type teamResponse struct {
+	Team Team `json:"team"`
+}
+
+func (c *Client) CreateTeam(ctx context.Context, team Team) (Team, error) {
+	var resp teamResponse
+	err := c.post(ctx, "/team/add", team, &resp)
+	return resp.Team, err
+}
+
+func (c *Client) GetTeam(ctx context.Context, teamName string) (Team, error) {
+	var team Team
+	err := c.get(ctx, "/team/get", url.Values{"team_name": {teamName}}, &team)
+	return team, err
+}
+
+// SetTeamFallback задаёт партнёрские команды и пулы, из которых добираются ревьюверы.
+func (c *Client) SetTeamFallback(ctx context.Context, teamName string, partnerTeams, pools []string) (Team, error) {
+	var resp teamResponse
+	err := c.post(ctx, "/team/setFallback", map[string]interface{}{
+		"team_name":     teamName,
+		"partner_teams": partnerTeams,
+		"pools":         pools,
+	}, &resp)
+	return resp.Team, err
+}
+
+func (c *Client) SetTeamMaxOpenReviews(ctx context.Context, teamName string, maxOpenReviews *int) (Team, error) {
+	var resp teamResponse
+	err := c.post(ctx, "/team/setMaxOpenReviews", map[string]interface{}{
+		"team_name":                teamName,
+		"default_max_open_reviews": maxOpenReviews,
+	}, &resp)
+	return resp.Team, err
+}
+
+func (c *Client) SetTeamReviewerLimits(ctx context.Context, teamName string, minReviewers, maxReviewers *int) (Team, error) {
+	var resp teamResponse
+	err := c.post(ctx, "/team/setReviewerLimits", map[string]interface{}{
+		"team_name":     teamName,
+		"min_reviewers": minReviewers,
+		"max_reviewers": maxReviewers,
+	}, &resp)
+	return resp.Team, err
+}
+
+// SetTeamReviewSLA задаёт SLA ревью команды; nil отключает его.
+func (c *Client) SetTeamReviewSLA(ctx context.Context, teamName string, sla *ReviewSLA) (Team, error) {
+	var resp teamResponse
+	err := c.post(ctx, "/team/setReviewSLA", map[string]interface{}{
+		"team_name":  teamName,
+		"review_sla": sla,
+	}, &resp)
+	return resp.Team, err
+}
+
+func (c *Client) SaveReviewerPool(ctx context.Context, pool ReviewerPool) (ReviewerPool, error) {
+	var resp struct {
+		Pool ReviewerPool `json:"pool"`
+	}
+	err := c.post(ctx, "/pool/add", pool, &resp)
+	return resp.Pool, err
+}
+
+func (c *Client) GetReviewerPool(ctx context.Context, poolName string) (ReviewerPool, error) {
+	var pool ReviewerPool
+	err := c.get(ctx, "/pool/get", url.Values{"pool_name": {poolName}}, &pool)
+	return pool, err
+}
+
+func (c *Client) UploadCodeOwners(ctx context.Context, file CodeOwnersFile) (CodeOwners, error) {
+	var co CodeOwners
+	err := c.post(ctx, "/codeowners/upload", file, &co)
+	return co, err
+}
+
+func (c *Client) GetCodeOwners(ctx context.Context, teamName string) (CodeOwnersFile, error) {
+	var file CodeOwnersFile
+	err := c.get(ctx, "/codeowners/get", url.Values{"team_name": {teamName}}, &file)
+	return file, err
+}
@@ -0,0 +1,122 @@
+package client
+
+import "PRService/internal/domain"
+
+// Типы запросов и ответов API. Пакет domain внутренний и недоступен другим модулям,
+// поэтому клиент отдаёт его типы под своими именами.
+type (
+	Team               = domain.Team
+	TeamMember         = domain.TeamMember
+	ReviewSLA          = domain.ReviewSLA
+	WorkingHours       = domain.WorkingHours
+	ReviewerPool       = domain.ReviewerPool
+	CodeOwnersFile     = domain.CodeOwnersFile
+	CodeOwnersRule     = domain.CodeOwnersRule
+	CodeOwners         = domain.CodeOwners
+	User               = domain.User
+	UserUpdate         = domain.UserUpdate
+	UserListFilter     = domain.UserListFilter
+	UserPage           = domain.UserPage
+	UserLoad           = domain.UserLoad
+	Unavailability     = domain.Unavailability
+	UnavailabilityKind = domain.UnavailabilityKind
+	PullRequest        = domain.PullRequest
+	PullRequestStatus  = domain.PullRequestStatus
+	ReviewerAssignment = domain.ReviewerAssignment
+	ReviewerSource     = domain.ReviewerSource
+	AssignmentMode     = domain.AssignmentMode
+	BulkPRItem         = domain.BulkPRItem
+	PRListFilter       = domain.PRListFilter
+	PRSortField        = domain.PRSortField
+	PRCursor           = domain.PRCursor
+	PRPage             = domain.PRPage
+	Review             = domain.Review
+	ReviewVerdict      = domain.ReviewVerdict
+	StatsFilter        = domain.StatsFilter
+	Stats              = domain.Stats
+	TeamStats          = domain.TeamStats
+	CycleTimeStats     = domain.CycleTimeStats
+	CycleTimeMetrics   = domain.CycleTimeMetrics
+	CycleTimePoint     = domain.CycleTimePoint
+	DurationStats      = domain.DurationStats
+	FairnessReport     = domain.FairnessReport
+	TeamFairness       = domain.TeamFairness
+	FairnessOutlier    = domain.FairnessOutlier
+	MigrationStatus    = domain.MigrationStatus
+	ConflictPolicy     = domain.ConflictPolicy
+	ExportKind         = domain.ExportKind
+	ImportStats        = domain.ImportStats
+	ImportCounts       = domain.ImportCounts
+)
+
+const (
+	StatusOpen   = domain.StatusOpen
+	StatusMerged = domain.StatusMerged
+
+	SourceTeam        = domain.SourceTeam
+	SourceCodeOwners  = domain.SourceCodeOwners
+	SourcePartnerTeam = domain.SourcePartnerTeam
+	SourcePool        = domain.SourcePool
+	SourceManual      = domain.SourceManual
+
+	AssignmentDefault      = domain.AssignmentDefault
+	AssignmentWorkingHours = domain.AssignmentWorkingHours
+
+	VerdictApproved         = domain.VerdictApproved
+	VerdictChangesRequested = domain.VerdictChangesRequested
+	VerdictCommented        = domain.VerdictCommented
+
+	UnavailabilityVacation  = domain.UnavailabilityVacation
+	UnavailabilityOnCall    = domain.UnavailabilityOnCall
+	UnavailabilitySickLeave = domain.UnavailabilitySickLeave
+	UnavailabilityOther     = domain.UnavailabilityOther
+
+	SortByCreatedAt = domain.SortByCreatedAt
+	SortByMergedAt  = domain.SortByMergedAt
+
+	ConflictSkip      = domain.ConflictSkip
+	ConflictOverwrite = domain.ConflictOverwrite
+	ConflictFail      = domain.ConflictFail
+)
+
+// DecodePRCursor разбирает PRPage.NextCursor в курсор для PRListFilter.Cursor.
+func DecodePRCursor(s string) (*PRCursor, error) {
+	return domain.DecodePRCursor(s)
+}
+
+// DecodeUserCursor разбирает UserPage.NextCursor в UserListFilter.AfterUserID.
+func DecodeUserCursor(s string) (string, error) {
+	return domain.DecodeUserCursor(s)
+}
+
+// Ошибки, с которыми errors.Is сопоставляет *Error; это те же значения, что и в сервисе.
+var (
+	ErrNotFound              = domain.ErrNotFound
+	ErrTeamNotFound          = domain.ErrTeamNotFound
+	ErrTeamExists            = domain.ErrTeamExists
+	ErrUserExists            = domain.ErrUserExists
+	ErrPrExists              = domain.ErrPrExists
+	ErrPrMerged              = domain.ErrPrMerged
+	ErrNotAssigned           = domain.ErrNotAssigned
+	ErrNoCandidate           = domain.ErrNoCandidate
+	ErrAlreadyAssigned       = domain.ErrAlreadyAssigned
+	ErrNotTeamMember         = domain.ErrNotTeamMember
+	ErrReviewerIsAuthor      = domain.ErrReviewerIsAuthor
+	ErrUserInactive          = domain.ErrUserInactive
+	ErrReviewerLimit         = domain.ErrReviewerLimit
+	ErrVersionMismatch       = domain.ErrVersionMismatch
+	ErrConcurrentUpdate      = domain.ErrConcurrentUpdate
+	ErrImportConflict        = domain.ErrImportConflict
+	ErrInvalidCodeOwners     = domain.ErrInvalidCodeOwners
+	ErrInvalidUnavailability = domain.ErrInvalidUnavailability
+	ErrInvalidSchedule       = domain.ErrInvalidSchedule
+	ErrInvalidSLA            = domain.ErrInvalidSLA
+	ErrInvalidStatsFilter    = domain.ErrInvalidStatsFilter
+	ErrInvalidVerdict        = domain.ErrInvalidVerdict
+	ErrInvalidCursor         = domain.ErrInvalidCursor
+	ErrInvalidPRFilter       = domain.ErrInvalidPRFilter
+	ErrInvalidUser           = domain.ErrInvalidUser
+	ErrInvalidLimits         = domain.ErrInvalidLimits
+	ErrInvalidExport         = domain.ErrInvalidExport
+	ErrInvalidBulkInput      = domain.ErrInvalidBulkInput
+)
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"PRService/internal/domain"
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type userResponse struct {
+	User User `json:"user"`
+}
+
+func (c *Client) GetUser(ctx context.Context, userID string) (User, error) {
+	var resp userResponse
+	err := c.get(ctx, "/users/get", url.Values{"user_id": {userID}}, &resp)
+	return resp.User, err
+}
+
+// ListUsers возвращает страницу пользователей по user_id; следующая страница
+// начинается после filter.AfterUserID — последнего пользователя предыдущей.
+func (c *Client) ListUsers(ctx context.Context, filter UserListFilter) (UserPage, error) {
+	query := url.Values{}
+	setQuery(query, "team_name", filter.TeamName)
+	setQuery(query, "username_prefix", filter.UsernamePrefix)
+	if filter.AfterUserID != "" {
+		query.Set("cursor", domain.EncodeUserCursor(filter.AfterUserID))
+	}
+	if filter.IsActive != nil {
+		query.Set("is_active", strconv.FormatBool(*filter.IsActive))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var page UserPage
+	err := c.get(ctx, "/users/list", query, &page)
+	return page, err
+}
+
+func (c *Client) AddUser(ctx context.Context, user User) (User, error) {
+	var resp userResponse
+	err := c.post(ctx, "/users/add", user, &resp)
+	return resp.User, err
+}
+
+func (c *Client) UpdateUser(ctx context.Context, userID string, update UserUpdate) (User, error) {
+	var resp userResponse
+	err := c.post(ctx, "/users/update", struct {
+		UserID string `json:"user_id"`
+		UserUpdate
+	}{userID, update}, &resp)
+	return resp.User, err
+}
+
+func (c *Client) SetUserActive(ctx context.Context, userID string, isActive bool) (User, error) {
+	var resp userResponse
+	err := c.post(ctx, "/users/setIsActive", map[string]interface{}{
+		"user_id":   userID,
+		"is_active": isActive,
+	}, &resp)
+	return resp.User, err
+}
+
+// DeactivateUsers безопасно деактивирует пользователей; результат — "success" или причина отказа по каждому.
+func (c *Client) DeactivateUsers(ctx context.Context, userIDs []string) (map[string]string, error) {
+	var resp struct {
+		Results map[string]string `json:"results"`
+	}
+	err := c.post(ctx, "/users/deactivate", map[string][]string{"user_ids": userIDs}, &resp)
+	return resp.Results, err
+}
+
+// GetPRsForReviewer возвращает PR, на которые назначен пользователь.
+func (c *Client) GetPRsForReviewer(ctx context.Context, userID string) ([]PullRequest, error) {
+	var resp struct {
+		PullRequests []PullRequest `json:"pull_requests"`
+	}
+	err := c.get(ctx, "/users/getReview", url.Values{"user_id": {userID}}, &resp)
+	return resp.PullRequests, err
+}
+
+func (c *Client) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (User, error) {
+	var resp userResponse
+	err := c.post(ctx, "/users/setMaxOpenReviews", map[string]interface{}{
+		"user_id":          userID,
+		"max_open_reviews": maxOpenReviews,
+	}, &resp)
+	return resp.User, err
+}
+
+// GetReviewLoad возвращает нагрузку ревьюверов по командам; пустой teamName — все команды.
+func (c *Client) GetReviewLoad(ctx context.Context, teamName string) (map[string][]UserLoad, error) {
+	query := url.Values{}
+	setQuery(query, "team_name", teamName)
+
+	var resp struct {
+		Teams map[string][]UserLoad `json:"teams"`
+	}
+	err := c.get(ctx, "/users/load", query, &resp)
+	return resp.Teams, err
+}
+
+func (c *Client) AddUnavailability(ctx context.Context, window Unavailability) (Unavailability, error) {
+	var resp struct {
+		Unavailability Unavailability `json:"unavailability"`
+	}
+	err := c.post(ctx, "/users/addUnavailability", window, &resp)
+	return resp.Unavailability, err
+}
+
+func (c *Client) ListUnavailability(ctx context.Context, userID string) ([]Unavailability, error) {
+	var resp struct {
+		Unavailability []Unavailability `json:"unavailability"`
+	}
+	err := c.get(ctx, "/users/getUnavailability", url.Values{"user_id": {userID}}, &resp)
+	return resp.Unavailability, err
+}
+
+func (c *Client) DeleteUnavailability(ctx context.Context, id int64) error {
+	return c.post(ctx, "/users/removeUnavailability", map[string]int64{"id": id}, nil)
+}
+
+func setQuery(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
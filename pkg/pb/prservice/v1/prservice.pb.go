@@ -0,0 +1,2002 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: prservice/v1/prservice.proto
+
+// gRPC API сервиса назначения ревьюверов. Повторяет HTTP API (openapi.yml)
+// для команд, пользователей, PR и статистики.
+
+package prservicev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PullRequestStatus int32
+
+const (
+	PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED PullRequestStatus = 0
+	PullRequestStatus_PULL_REQUEST_STATUS_OPEN        PullRequestStatus = 1
+	PullRequestStatus_PULL_REQUEST_STATUS_MERGED      PullRequestStatus = 2
+)
+
+// Enum value maps for PullRequestStatus.
+var (
+	PullRequestStatus_name = map[int32]string{
+		0: "PULL_REQUEST_STATUS_UNSPECIFIED",
+		1: "PULL_REQUEST_STATUS_OPEN",
+		2: "PULL_REQUEST_STATUS_MERGED",
+	}
+	PullRequestStatus_value = map[string]int32{
+		"PULL_REQUEST_STATUS_UNSPECIFIED": 0,
+		"PULL_REQUEST_STATUS_OPEN":        1,
+		"PULL_REQUEST_STATUS_MERGED":      2,
+	}
+)
+
+func (x PullRequestStatus) Enum() *PullRequestStatus {
+	p := new(PullRequestStatus)
+	*p = x
+	return p
+}
+
+func (x PullRequestStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PullRequestStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_prservice_v1_prservice_proto_enumTypes[0].Descriptor()
+}
+
+func (PullRequestStatus) Type() protoreflect.EnumType {
+	return &file_prservice_v1_prservice_proto_enumTypes[0]
+}
+
+func (x PullRequestStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PullRequestStatus.Descriptor instead.
+func (PullRequestStatus) EnumDescriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{0}
+}
+
+type AssignmentEventType int32
+
+const (
+	AssignmentEventType_ASSIGNMENT_EVENT_TYPE_UNSPECIFIED AssignmentEventType = 0
+	AssignmentEventType_ASSIGNMENT_EVENT_TYPE_ASSIGNED    AssignmentEventType = 1
+	AssignmentEventType_ASSIGNMENT_EVENT_TYPE_REASSIGNED  AssignmentEventType = 2
+	AssignmentEventType_ASSIGNMENT_EVENT_TYPE_REMOVED     AssignmentEventType = 3
+)
+
+// Enum value maps for AssignmentEventType.
+var (
+	AssignmentEventType_name = map[int32]string{
+		0: "ASSIGNMENT_EVENT_TYPE_UNSPECIFIED",
+		1: "ASSIGNMENT_EVENT_TYPE_ASSIGNED",
+		2: "ASSIGNMENT_EVENT_TYPE_REASSIGNED",
+		3: "ASSIGNMENT_EVENT_TYPE_REMOVED",
+	}
+	AssignmentEventType_value = map[string]int32{
+		"ASSIGNMENT_EVENT_TYPE_UNSPECIFIED": 0,
+		"ASSIGNMENT_EVENT_TYPE_ASSIGNED":    1,
+		"ASSIGNMENT_EVENT_TYPE_REASSIGNED":  2,
+		"ASSIGNMENT_EVENT_TYPE_REMOVED":     3,
+	}
+)
+
+func (x AssignmentEventType) Enum() *AssignmentEventType {
+	p := new(AssignmentEventType)
+	*p = x
+	return p
+}
+
+func (x AssignmentEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AssignmentEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_prservice_v1_prservice_proto_enumTypes[1].Descriptor()
+}
+
+func (AssignmentEventType) Type() protoreflect.EnumType {
+	return &file_prservice_v1_prservice_proto_enumTypes[1]
+}
+
+func (x AssignmentEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AssignmentEventType.Descriptor instead.
+func (AssignmentEventType) EnumDescriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{1}
+}
+
+type TeamMember struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username       string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	IsActive       bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	MaxOpenReviews *int32                 `protobuf:"varint,4,opt,name=max_open_reviews,json=maxOpenReviews,proto3,oneof" json:"max_open_reviews,omitempty"`
+	IsAvailable    bool                   `protobuf:"varint,5,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TeamMember) Reset() {
+	*x = TeamMember{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamMember) ProtoMessage() {}
+
+func (x *TeamMember) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamMember.ProtoReflect.Descriptor instead.
+func (*TeamMember) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TeamMember) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TeamMember) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *TeamMember) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *TeamMember) GetMaxOpenReviews() int32 {
+	if x != nil && x.MaxOpenReviews != nil {
+		return *x.MaxOpenReviews
+	}
+	return 0
+}
+
+func (x *TeamMember) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Members       []*TeamMember          `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Team) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *Team) GetMembers() []*TeamMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type CreateTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTeamRequest) Reset() {
+	*x = CreateTeamRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTeamRequest) ProtoMessage() {}
+
+func (x *CreateTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTeamRequest.ProtoReflect.Descriptor instead.
+func (*CreateTeamRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTeamRequest) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type GetTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamRequest) Reset() {
+	*x = GetTeamRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamRequest) ProtoMessage() {}
+
+func (x *GetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTeamRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+type User struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username       string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	TeamName       string                 `protobuf:"bytes,3,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	IsActive       bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	MaxOpenReviews *int32                 `protobuf:"varint,5,opt,name=max_open_reviews,json=maxOpenReviews,proto3,oneof" json:"max_open_reviews,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *User) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *User) GetMaxOpenReviews() int32 {
+	if x != nil && x.MaxOpenReviews != nil {
+		return *x.MaxOpenReviews
+	}
+	return 0
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListUsersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TeamName       string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	UsernamePrefix string                 `protobuf:"bytes,2,opt,name=username_prefix,json=usernamePrefix,proto3" json:"username_prefix,omitempty"`
+	IsActive       *bool                  `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	Limit          int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor — next_cursor предыдущей страницы.
+	Cursor        string `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetUsernamePrefix() string {
+	if x != nil {
+		return x.UsernamePrefix
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SetUserActiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsActive      bool                   `protobuf:"varint,2,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserActiveRequest) Reset() {
+	*x = SetUserActiveRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserActiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserActiveRequest) ProtoMessage() {}
+
+func (x *SetUserActiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserActiveRequest.ProtoReflect.Descriptor instead.
+func (*SetUserActiveRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetUserActiveRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetUserActiveRequest) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+type DeactivateUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUsersRequest) Reset() {
+	*x = DeactivateUsersRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUsersRequest) ProtoMessage() {}
+
+func (x *DeactivateUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUsersRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateUsersRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeactivateUsersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type DeactivateUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// results — "success" или причина отказа по каждому пользователю.
+	Results       map[string]string `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUsersResponse) Reset() {
+	*x = DeactivateUsersResponse{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUsersResponse) ProtoMessage() {}
+
+func (x *DeactivateUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUsersResponse.ProtoReflect.Descriptor instead.
+func (*DeactivateUsersResponse) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeactivateUsersResponse) GetResults() map[string]string {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ReviewerAssignment struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// source — TEAM, CODEOWNERS, PARTNER_TEAM, POOL или MANUAL.
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	SourceName    string                 `protobuf:"bytes,3,opt,name=source_name,json=sourceName,proto3" json:"source_name,omitempty"`
+	AssignedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=assigned_at,json=assignedAt,proto3" json:"assigned_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewerAssignment) Reset() {
+	*x = ReviewerAssignment{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewerAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewerAssignment) ProtoMessage() {}
+
+func (x *ReviewerAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewerAssignment.ProtoReflect.Descriptor instead.
+func (*ReviewerAssignment) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReviewerAssignment) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReviewerAssignment) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ReviewerAssignment) GetSourceName() string {
+	if x != nil {
+		return x.SourceName
+	}
+	return ""
+}
+
+func (x *ReviewerAssignment) GetAssignedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AssignedAt
+	}
+	return nil
+}
+
+type PullRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId     string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName   string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId          string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	AuthorTeam        string                 `protobuf:"bytes,4,opt,name=author_team,json=authorTeam,proto3" json:"author_team,omitempty"`
+	Status            PullRequestStatus      `protobuf:"varint,5,opt,name=status,proto3,enum=prservice.v1.PullRequestStatus" json:"status,omitempty"`
+	AssignedReviewers []string               `protobuf:"bytes,6,rep,name=assigned_reviewers,json=assignedReviewers,proto3" json:"assigned_reviewers,omitempty"`
+	Assignments       []*ReviewerAssignment  `protobuf:"bytes,7,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	MergedAt          *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=merged_at,json=mergedAt,proto3" json:"merged_at,omitempty"`
+	Version           int64                  `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PullRequest) Reset() {
+	*x = PullRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequest) ProtoMessage() {}
+
+func (x *PullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequest.ProtoReflect.Descriptor instead.
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PullRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequest) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *PullRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *PullRequest) GetAuthorTeam() string {
+	if x != nil {
+		return x.AuthorTeam
+	}
+	return ""
+}
+
+func (x *PullRequest) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *PullRequest) GetAssignedReviewers() []string {
+	if x != nil {
+		return x.AssignedReviewers
+	}
+	return nil
+}
+
+func (x *PullRequest) GetAssignments() []*ReviewerAssignment {
+	if x != nil {
+		return x.Assignments
+	}
+	return nil
+}
+
+func (x *PullRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PullRequest) GetMergedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MergedAt
+	}
+	return nil
+}
+
+func (x *PullRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CreatePullRequestRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// changed_files — пути изменённых файлов для выбора ревьюверов по CODEOWNERS.
+	ChangedFiles  []string `protobuf:"bytes,4,rep,name=changed_files,json=changedFiles,proto3" json:"changed_files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePullRequestRequest) Reset() {
+	*x = CreatePullRequestRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePullRequestRequest) ProtoMessage() {}
+
+func (x *CreatePullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePullRequestRequest.ProtoReflect.Descriptor instead.
+func (*CreatePullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreatePullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetChangedFiles() []string {
+	if x != nil {
+		return x.ChangedFiles
+	}
+	return nil
+}
+
+type GetPullRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPullRequestRequest) Reset() {
+	*x = GetPullRequestRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPullRequestRequest) ProtoMessage() {}
+
+func (x *GetPullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPullRequestRequest.ProtoReflect.Descriptor instead.
+func (*GetPullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetPullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+type ListPullRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuthorId      string                 `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	ReviewerId    string                 `protobuf:"bytes,2,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	TeamName      string                 `protobuf:"bytes,3,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Status        PullRequestStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=prservice.v1.PullRequestStatus" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPullRequestsRequest) Reset() {
+	*x = ListPullRequestsRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPullRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPullRequestsRequest) ProtoMessage() {}
+
+func (x *ListPullRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPullRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListPullRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListPullRequestsRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ListPullRequestsRequest) GetReviewerId() string {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return ""
+}
+
+func (x *ListPullRequestsRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *ListPullRequestsRequest) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *ListPullRequestsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListPullRequestsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListPullRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequests  []*PullRequest         `protobuf:"bytes,1,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPullRequestsResponse) Reset() {
+	*x = ListPullRequestsResponse{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPullRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPullRequestsResponse) ProtoMessage() {}
+
+func (x *ListPullRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPullRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListPullRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListPullRequestsResponse) GetPullRequests() []*PullRequest {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+func (x *ListPullRequestsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type MergePullRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	// expected_version — версия PR, с которой клиент работал; 0 — без проверки.
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergePullRequestRequest) Reset() {
+	*x = MergePullRequestRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergePullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePullRequestRequest) ProtoMessage() {}
+
+func (x *MergePullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePullRequestRequest.ProtoReflect.Descriptor instead.
+func (*MergePullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MergePullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *MergePullRequestRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ReassignReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	OldReviewerId string                 `protobuf:"bytes,2,opt,name=old_reviewer_id,json=oldReviewerId,proto3" json:"old_reviewer_id,omitempty"`
+	// new_reviewer_id — замена, выбранная вручную; пустое значение — автоподбор.
+	NewReviewerId   string `protobuf:"bytes,3,opt,name=new_reviewer_id,json=newReviewerId,proto3" json:"new_reviewer_id,omitempty"`
+	ExpectedVersion int64  `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReassignReviewerRequest) Reset() {
+	*x = ReassignReviewerRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignReviewerRequest) ProtoMessage() {}
+
+func (x *ReassignReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignReviewerRequest.ProtoReflect.Descriptor instead.
+func (*ReassignReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReassignReviewerRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *ReassignReviewerRequest) GetOldReviewerId() string {
+	if x != nil {
+		return x.OldReviewerId
+	}
+	return ""
+}
+
+func (x *ReassignReviewerRequest) GetNewReviewerId() string {
+	if x != nil {
+		return x.NewReviewerId
+	}
+	return ""
+}
+
+func (x *ReassignReviewerRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ReassignReviewerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequest   *PullRequest           `protobuf:"bytes,1,opt,name=pull_request,json=pullRequest,proto3" json:"pull_request,omitempty"`
+	ReplacedBy    string                 `protobuf:"bytes,2,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignReviewerResponse) Reset() {
+	*x = ReassignReviewerResponse{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignReviewerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignReviewerResponse) ProtoMessage() {}
+
+func (x *ReassignReviewerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignReviewerResponse.ProtoReflect.Descriptor instead.
+func (*ReassignReviewerResponse) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReassignReviewerResponse) GetPullRequest() *PullRequest {
+	if x != nil {
+		return x.PullRequest
+	}
+	return nil
+}
+
+func (x *ReassignReviewerResponse) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Status        PullRequestStatus      `protobuf:"varint,2,opt,name=status,proto3,enum=prservice.v1.PullRequestStatus" json:"status,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetStatsRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *GetStatsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetStatsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type TeamStats struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	PullRequests       int32                  `protobuf:"varint,1,opt,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
+	OpenPullRequests   int32                  `protobuf:"varint,2,opt,name=open_pull_requests,json=openPullRequests,proto3" json:"open_pull_requests,omitempty"`
+	MergedPullRequests int32                  `protobuf:"varint,3,opt,name=merged_pull_requests,json=mergedPullRequests,proto3" json:"merged_pull_requests,omitempty"`
+	Assignments        int32                  `protobuf:"varint,4,opt,name=assignments,proto3" json:"assignments,omitempty"`
+	OpenAssignments    int32                  `protobuf:"varint,5,opt,name=open_assignments,json=openAssignments,proto3" json:"open_assignments,omitempty"`
+	Reviewers          int32                  `protobuf:"varint,6,opt,name=reviewers,proto3" json:"reviewers,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TeamStats) Reset() {
+	*x = TeamStats{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamStats) ProtoMessage() {}
+
+func (x *TeamStats) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamStats.ProtoReflect.Descriptor instead.
+func (*TeamStats) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TeamStats) GetPullRequests() int32 {
+	if x != nil {
+		return x.PullRequests
+	}
+	return 0
+}
+
+func (x *TeamStats) GetOpenPullRequests() int32 {
+	if x != nil {
+		return x.OpenPullRequests
+	}
+	return 0
+}
+
+func (x *TeamStats) GetMergedPullRequests() int32 {
+	if x != nil {
+		return x.MergedPullRequests
+	}
+	return 0
+}
+
+func (x *TeamStats) GetAssignments() int32 {
+	if x != nil {
+		return x.Assignments
+	}
+	return 0
+}
+
+func (x *TeamStats) GetOpenAssignments() int32 {
+	if x != nil {
+		return x.OpenAssignments
+	}
+	return 0
+}
+
+func (x *TeamStats) GetReviewers() int32 {
+	if x != nil {
+		return x.Reviewers
+	}
+	return 0
+}
+
+type Stats struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	ReviewerAssignments     map[string]int32       `protobuf:"bytes,1,rep,name=reviewer_assignments,json=reviewerAssignments,proto3" json:"reviewer_assignments,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	ReviewerOpenAssignments map[string]int32       `protobuf:"bytes,2,rep,name=reviewer_open_assignments,json=reviewerOpenAssignments,proto3" json:"reviewer_open_assignments,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	PrAssignments           map[string]int32       `protobuf:"bytes,3,rep,name=pr_assignments,json=prAssignments,proto3" json:"pr_assignments,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Teams                   map[string]*TeamStats  `protobuf:"bytes,4,rep,name=teams,proto3" json:"teams,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Stats) GetReviewerAssignments() map[string]int32 {
+	if x != nil {
+		return x.ReviewerAssignments
+	}
+	return nil
+}
+
+func (x *Stats) GetReviewerOpenAssignments() map[string]int32 {
+	if x != nil {
+		return x.ReviewerOpenAssignments
+	}
+	return nil
+}
+
+func (x *Stats) GetPrAssignments() map[string]int32 {
+	if x != nil {
+		return x.PrAssignments
+	}
+	return nil
+}
+
+func (x *Stats) GetTeams() map[string]*TeamStats {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type WatchAssignmentEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Пустые поля не фильтруют.
+	TeamName      string `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	UserId        string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAssignmentEventsRequest) Reset() {
+	*x = WatchAssignmentEventsRequest{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAssignmentEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAssignmentEventsRequest) ProtoMessage() {}
+
+func (x *WatchAssignmentEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAssignmentEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAssignmentEventsRequest) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WatchAssignmentEventsRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *WatchAssignmentEventsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type AssignmentEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          AssignmentEventType    `protobuf:"varint,1,opt,name=type,proto3,enum=prservice.v1.AssignmentEventType" json:"type,omitempty"`
+	PullRequestId string                 `protobuf:"bytes,2,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	// user_id — назначенный ревьювер, а для REASSIGNED и REMOVED — снятый.
+	UserId   string `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TeamName string `protobuf:"bytes,4,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	// replaced_by — новый ревьювер для REASSIGNED.
+	ReplacedBy    string                 `protobuf:"bytes,5,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignmentEvent) Reset() {
+	*x = AssignmentEvent{}
+	mi := &file_prservice_v1_prservice_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignmentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentEvent) ProtoMessage() {}
+
+func (x *AssignmentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_prservice_v1_prservice_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentEvent.ProtoReflect.Descriptor instead.
+func (*AssignmentEvent) Descriptor() ([]byte, []int) {
+	return file_prservice_v1_prservice_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AssignmentEvent) GetType() AssignmentEventType {
+	if x != nil {
+		return x.Type
+	}
+	return AssignmentEventType_ASSIGNMENT_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *AssignmentEvent) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *AssignmentEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssignmentEvent) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *AssignmentEvent) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+func (x *AssignmentEvent) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+var File_prservice_v1_prservice_proto protoreflect.FileDescriptor
+
+const file_prservice_v1_prservice_proto_rawDesc = "" +
+	"\n" +
+	"\x1cprservice/v1/prservice.proto\x12\fprservice.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc5\x01\n" +
+	"\n" +
+	"TeamMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tis_active\x18\x03 \x01(\bR\bisActive\x12-\n" +
+	"\x10max_open_reviews\x18\x04 \x01(\x05H\x00R\x0emaxOpenReviews\x88\x01\x01\x12!\n" +
+	"\fis_available\x18\x05 \x01(\bR\visAvailableB\x13\n" +
+	"\x11_max_open_reviews\"W\n" +
+	"\x04Team\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x122\n" +
+	"\amembers\x18\x02 \x03(\v2\x18.prservice.v1.TeamMemberR\amembers\";\n" +
+	"\x11CreateTeamRequest\x12&\n" +
+	"\x04team\x18\x01 \x01(\v2\x12.prservice.v1.TeamR\x04team\"-\n" +
+	"\x0eGetTeamRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\"\xb9\x01\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tteam_name\x18\x03 \x01(\tR\bteamName\x12\x1b\n" +
+	"\tis_active\x18\x04 \x01(\bR\bisActive\x12-\n" +
+	"\x10max_open_reviews\x18\x05 \x01(\x05H\x00R\x0emaxOpenReviews\x88\x01\x01B\x13\n" +
+	"\x11_max_open_reviews\")\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xb6\x01\n" +
+	"\x10ListUsersRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x12'\n" +
+	"\x0fusername_prefix\x18\x02 \x01(\tR\x0eusernamePrefix\x12 \n" +
+	"\tis_active\x18\x03 \x01(\bH\x00R\bisActive\x88\x01\x01\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursorB\f\n" +
+	"\n" +
+	"_is_active\"^\n" +
+	"\x11ListUsersResponse\x12(\n" +
+	"\x05users\x18\x01 \x03(\v2\x12.prservice.v1.UserR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"L\n" +
+	"\x14SetUserActiveRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tis_active\x18\x02 \x01(\bR\bisActive\"3\n" +
+	"\x16DeactivateUsersRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\"\xa3\x01\n" +
+	"\x17DeactivateUsersResponse\x12L\n" +
+	"\aresults\x18\x01 \x03(\v22.prservice.v1.DeactivateUsersResponse.ResultsEntryR\aresults\x1a:\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa3\x01\n" +
+	"\x12ReviewerAssignment\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x1f\n" +
+	"\vsource_name\x18\x03 \x01(\tR\n" +
+	"sourceName\x12;\n" +
+	"\vassigned_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"assignedAt\"\xd9\x03\n" +
+	"\vPullRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x12\x1f\n" +
+	"\vauthor_team\x18\x04 \x01(\tR\n" +
+	"authorTeam\x127\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1f.prservice.v1.PullRequestStatusR\x06status\x12-\n" +
+	"\x12assigned_reviewers\x18\x06 \x03(\tR\x11assignedReviewers\x12B\n" +
+	"\vassignments\x18\a \x03(\v2 .prservice.v1.ReviewerAssignmentR\vassignments\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tmerged_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bmergedAt\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\x03R\aversion\"\xb0\x01\n" +
+	"\x18CreatePullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x12#\n" +
+	"\rchanged_files\x18\x04 \x03(\tR\fchangedFiles\"?\n" +
+	"\x15GetPullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\"\xdb\x01\n" +
+	"\x17ListPullRequestsRequest\x12\x1b\n" +
+	"\tauthor_id\x18\x01 \x01(\tR\bauthorId\x12\x1f\n" +
+	"\vreviewer_id\x18\x02 \x01(\tR\n" +
+	"reviewerId\x12\x1b\n" +
+	"\tteam_name\x18\x03 \x01(\tR\bteamName\x127\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1f.prservice.v1.PullRequestStatusR\x06status\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\"{\n" +
+	"\x18ListPullRequestsResponse\x12>\n" +
+	"\rpull_requests\x18\x01 \x03(\v2\x19.prservice.v1.PullRequestR\fpullRequests\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"l\n" +
+	"\x17MergePullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"\xbc\x01\n" +
+	"\x17ReassignReviewerRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12&\n" +
+	"\x0fold_reviewer_id\x18\x02 \x01(\tR\roldReviewerId\x12&\n" +
+	"\x0fnew_reviewer_id\x18\x03 \x01(\tR\rnewReviewerId\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"y\n" +
+	"\x18ReassignReviewerResponse\x12<\n" +
+	"\fpull_request\x18\x01 \x01(\v2\x19.prservice.v1.PullRequestR\vpullRequest\x12\x1f\n" +
+	"\vreplaced_by\x18\x02 \x01(\tR\n" +
+	"replacedBy\"\xc3\x01\n" +
+	"\x0fGetStatsRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x127\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1f.prservice.v1.PullRequestStatusR\x06status\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\xfb\x01\n" +
+	"\tTeamStats\x12#\n" +
+	"\rpull_requests\x18\x01 \x01(\x05R\fpullRequests\x12,\n" +
+	"\x12open_pull_requests\x18\x02 \x01(\x05R\x10openPullRequests\x120\n" +
+	"\x14merged_pull_requests\x18\x03 \x01(\x05R\x12mergedPullRequests\x12 \n" +
+	"\vassignments\x18\x04 \x01(\x05R\vassignments\x12)\n" +
+	"\x10open_assignments\x18\x05 \x01(\x05R\x0fopenAssignments\x12\x1c\n" +
+	"\treviewers\x18\x06 \x01(\x05R\treviewers\"\x84\x05\n" +
+	"\x05Stats\x12_\n" +
+	"\x14reviewer_assignments\x18\x01 \x03(\v2,.prservice.v1.Stats.ReviewerAssignmentsEntryR\x13reviewerAssignments\x12l\n" +
+	"\x19reviewer_open_assignments\x18\x02 \x03(\v20.prservice.v1.Stats.ReviewerOpenAssignmentsEntryR\x17reviewerOpenAssignments\x12M\n" +
+	"\x0epr_assignments\x18\x03 \x03(\v2&.prservice.v1.Stats.PrAssignmentsEntryR\rprAssignments\x124\n" +
+	"\x05teams\x18\x04 \x03(\v2\x1e.prservice.v1.Stats.TeamsEntryR\x05teams\x1aF\n" +
+	"\x18ReviewerAssignmentsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aJ\n" +
+	"\x1cReviewerOpenAssignmentsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a@\n" +
+	"\x12PrAssignmentsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aQ\n" +
+	"\n" +
+	"TeamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.prservice.v1.TeamStatsR\x05value:\x028\x01\"T\n" +
+	"\x1cWatchAssignmentEventsRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xf3\x01\n" +
+	"\x0fAssignmentEvent\x125\n" +
+	"\x04type\x18\x01 \x01(\x0e2!.prservice.v1.AssignmentEventTypeR\x04type\x12&\n" +
+	"\x0fpull_request_id\x18\x02 \x01(\tR\rpullRequestId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tteam_name\x18\x04 \x01(\tR\bteamName\x12\x1f\n" +
+	"\vreplaced_by\x18\x05 \x01(\tR\n" +
+	"replacedBy\x12*\n" +
+	"\x02at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02at*v\n" +
+	"\x11PullRequestStatus\x12#\n" +
+	"\x1fPULL_REQUEST_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PULL_REQUEST_STATUS_OPEN\x10\x01\x12\x1e\n" +
+	"\x1aPULL_REQUEST_STATUS_MERGED\x10\x02*\xa9\x01\n" +
+	"\x13AssignmentEventType\x12%\n" +
+	"!ASSIGNMENT_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eASSIGNMENT_EVENT_TYPE_ASSIGNED\x10\x01\x12$\n" +
+	" ASSIGNMENT_EVENT_TYPE_REASSIGNED\x10\x02\x12!\n" +
+	"\x1dASSIGNMENT_EVENT_TYPE_REMOVED\x10\x032\xab\b\n" +
+	"\tPRService\x12A\n" +
+	"\n" +
+	"CreateTeam\x12\x1f.prservice.v1.CreateTeamRequest\x1a\x12.prservice.v1.Team\x12;\n" +
+	"\aGetTeam\x12\x1c.prservice.v1.GetTeamRequest\x1a\x12.prservice.v1.Team\x12;\n" +
+	"\aGetUser\x12\x1c.prservice.v1.GetUserRequest\x1a\x12.prservice.v1.User\x12L\n" +
+	"\tListUsers\x12\x1e.prservice.v1.ListUsersRequest\x1a\x1f.prservice.v1.ListUsersResponse\x12G\n" +
+	"\rSetUserActive\x12\".prservice.v1.SetUserActiveRequest\x1a\x12.prservice.v1.User\x12^\n" +
+	"\x0fDeactivateUsers\x12$.prservice.v1.DeactivateUsersRequest\x1a%.prservice.v1.DeactivateUsersResponse\x12V\n" +
+	"\x11CreatePullRequest\x12&.prservice.v1.CreatePullRequestRequest\x1a\x19.prservice.v1.PullRequest\x12P\n" +
+	"\x0eGetPullRequest\x12#.prservice.v1.GetPullRequestRequest\x1a\x19.prservice.v1.PullRequest\x12a\n" +
+	"\x10ListPullRequests\x12%.prservice.v1.ListPullRequestsRequest\x1a&.prservice.v1.ListPullRequestsResponse\x12T\n" +
+	"\x10MergePullRequest\x12%.prservice.v1.MergePullRequestRequest\x1a\x19.prservice.v1.PullRequest\x12a\n" +
+	"\x10ReassignReviewer\x12%.prservice.v1.ReassignReviewerRequest\x1a&.prservice.v1.ReassignReviewerResponse\x12>\n" +
+	"\bGetStats\x12\x1d.prservice.v1.GetStatsRequest\x1a\x13.prservice.v1.Stats\x12d\n" +
+	"\x15WatchAssignmentEvents\x12*.prservice.v1.WatchAssignmentEventsRequest\x1a\x1d.prservice.v1.AssignmentEvent0\x01B+Z)PRService/pkg/pb/prservice/v1;prservicev1b\x06proto3"
+
+var (
+	file_prservice_v1_prservice_proto_rawDescOnce sync.Once
+	file_prservice_v1_prservice_proto_rawDescData []byte
+)
+
+func file_prservice_v1_prservice_proto_rawDescGZIP() []byte {
+	file_prservice_v1_prservice_proto_rawDescOnce.Do(func() {
+		file_prservice_v1_prservice_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_prservice_v1_prservice_proto_rawDesc), len(file_prservice_v1_prservice_proto_rawDesc)))
+	})
+	return file_prservice_v1_prservice_proto_rawDescData
+}
+
+var file_prservice_v1_prservice_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_prservice_v1_prservice_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_prservice_v1_prservice_proto_goTypes = []any{
+	(PullRequestStatus)(0),               // 0: prservice.v1.PullRequestStatus
+	(AssignmentEventType)(0),             // 1: prservice.v1.AssignmentEventType
+	(*TeamMember)(nil),                   // 2: prservice.v1.TeamMember
+	(*Team)(nil),                         // 3: prservice.v1.Team
+	(*CreateTeamRequest)(nil),            // 4: prservice.v1.CreateTeamRequest
+	(*GetTeamRequest)(nil),               // 5: prservice.v1.GetTeamRequest
+	(*User)(nil),                         // 6: prservice.v1.User
+	(*GetUserRequest)(nil),               // 7: prservice.v1.GetUserRequest
+	(*ListUsersRequest)(nil),             // 8: prservice.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),            // 9: prservice.v1.ListUsersResponse
+	(*SetUserActiveRequest)(nil),         // 10: prservice.v1.SetUserActiveRequest
+	(*DeactivateUsersRequest)(nil),       // 11: prservice.v1.DeactivateUsersRequest
+	(*DeactivateUsersResponse)(nil),      // 12: prservice.v1.DeactivateUsersResponse
+	(*ReviewerAssignment)(nil),           // 13: prservice.v1.ReviewerAssignment
+	(*PullRequest)(nil),                  // 14: prservice.v1.PullRequest
+	(*CreatePullRequestRequest)(nil),     // 15: prservice.v1.CreatePullRequestRequest
+	(*GetPullRequestRequest)(nil),        // 16: prservice.v1.GetPullRequestRequest
+	(*ListPullRequestsRequest)(nil),      // 17: prservice.v1.ListPullRequestsRequest
+	(*ListPullRequestsResponse)(nil),     // 18: prservice.v1.ListPullRequestsResponse
+	(*MergePullRequestRequest)(nil),      // 19: prservice.v1.MergePullRequestRequest
+	(*ReassignReviewerRequest)(nil),      // 20: prservice.v1.ReassignReviewerRequest
+	(*ReassignReviewerResponse)(nil),     // 21: prservice.v1.ReassignReviewerResponse
+	(*GetStatsRequest)(nil),              // 22: prservice.v1.GetStatsRequest
+	(*TeamStats)(nil),                    // 23: prservice.v1.TeamStats
+	(*Stats)(nil),                        // 24: prservice.v1.Stats
+	(*WatchAssignmentEventsRequest)(nil), // 25: prservice.v1.WatchAssignmentEventsRequest
+	(*AssignmentEvent)(nil),              // 26: prservice.v1.AssignmentEvent
+	nil,                                  // 27: prservice.v1.DeactivateUsersResponse.ResultsEntry
+	nil,                                  // 28: prservice.v1.Stats.ReviewerAssignmentsEntry
+	nil,                                  // 29: prservice.v1.Stats.ReviewerOpenAssignmentsEntry
+	nil,                                  // 30: prservice.v1.Stats.PrAssignmentsEntry
+	nil,                                  // 31: prservice.v1.Stats.TeamsEntry
+	(*timestamppb.Timestamp)(nil),        // 32: google.protobuf.Timestamp
+}
+var file_prservice_v1_prservice_proto_depIdxs = []int32{
+	2,  // 0: prservice.v1.Team.members:type_name -> prservice.v1.TeamMember
+	3,  // 1: prservice.v1.CreateTeamRequest.team:type_name -> prservice.v1.Team
+	6,  // 2: prservice.v1.ListUsersResponse.users:type_name -> prservice.v1.User
+	27, // 3: prservice.v1.DeactivateUsersResponse.results:type_name -> prservice.v1.DeactivateUsersResponse.ResultsEntry
+	32, // 4: prservice.v1.ReviewerAssignment.assigned_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: prservice.v1.PullRequest.status:type_name -> prservice.v1.PullRequestStatus
+	13, // 6: prservice.v1.PullRequest.assignments:type_name -> prservice.v1.ReviewerAssignment
+	32, // 7: prservice.v1.PullRequest.created_at:type_name -> google.protobuf.Timestamp
+	32, // 8: prservice.v1.PullRequest.merged_at:type_name -> google.protobuf.Timestamp
+	0,  // 9: prservice.v1.ListPullRequestsRequest.status:type_name -> prservice.v1.PullRequestStatus
+	14, // 10: prservice.v1.ListPullRequestsResponse.pull_requests:type_name -> prservice.v1.PullRequest
+	14, // 11: prservice.v1.ReassignReviewerResponse.pull_request:type_name -> prservice.v1.PullRequest
+	0,  // 12: prservice.v1.GetStatsRequest.status:type_name -> prservice.v1.PullRequestStatus
+	32, // 13: prservice.v1.GetStatsRequest.from:type_name -> google.protobuf.Timestamp
+	32, // 14: prservice.v1.GetStatsRequest.to:type_name -> google.protobuf.Timestamp
+	28, // 15: prservice.v1.Stats.reviewer_assignments:type_name -> prservice.v1.Stats.ReviewerAssignmentsEntry
+	29, // 16: prservice.v1.Stats.reviewer_open_assignments:type_name -> prservice.v1.Stats.ReviewerOpenAssignmentsEntry
+	30, // 17: prservice.v1.Stats.pr_assignments:type_name -> prservice.v1.Stats.PrAssignmentsEntry
+	31, // 18: prservice.v1.Stats.teams:type_name -> prservice.v1.Stats.TeamsEntry
+	1,  // 19: prservice.v1.AssignmentEvent.type:type_name -> prservice.v1.AssignmentEventType
+	32, // 20: prservice.v1.AssignmentEvent.at:type_name -> google.protobuf.Timestamp
+	23, // 21: prservice.v1.Stats.TeamsEntry.value:type_name -> prservice.v1.TeamStats
+	4,  // 22: prservice.v1.PRService.CreateTeam:input_type -> prservice.v1.CreateTeamRequest
+	5,  // 23: prservice.v1.PRService.GetTeam:input_type -> prservice.v1.GetTeamRequest
+	7,  // 24: prservice.v1.PRService.GetUser:input_type -> prservice.v1.GetUserRequest
+	8,  // 25: prservice.v1.PRService.ListUsers:input_type -> prservice.v1.ListUsersRequest
+	10, // 26: prservice.v1.PRService.SetUserActive:input_type -> prservice.v1.SetUserActiveRequest
+	11, // 27: prservice.v1.PRService.DeactivateUsers:input_type -> prservice.v1.DeactivateUsersRequest
+	15, // 28: prservice.v1.PRService.CreatePullRequest:input_type -> prservice.v1.CreatePullRequestRequest
+	16, // 29: prservice.v1.PRService.GetPullRequest:input_type -> prservice.v1.GetPullRequestRequest
+	17, // 30: prservice.v1.PRService.ListPullRequests:input_type -> prservice.v1.ListPullRequestsRequest
+	19, // 31: prservice.v1.PRService.MergePullRequest:input_type -> prservice.v1.MergePullRequestRequest
+	20, // 32: prservice.v1.PRService.ReassignReviewer:input_type -> prservice.v1.ReassignReviewerRequest
+	22, // 33: prservice.v1.PRService.GetStats:input_type -> prservice.v1.GetStatsRequest
+	25, // 34: prservice.v1.PRService.WatchAssignmentEvents:input_type -> prservice.v1.WatchAssignmentEventsRequest
+	3,  // 35: prservice.v1.PRService.CreateTeam:output_type -> prservice.v1.Team
+	3,  // 36: prservice.v1.PRService.GetTeam:output_type -> prservice.v1.Team
+	6,  // 37: prservice.v1.PRService.GetUser:output_type -> prservice.v1.User
+	9,  // 38: prservice.v1.PRService.ListUsers:output_type -> prservice.v1.ListUsersResponse
+	6,  // 39: prservice.v1.PRService.SetUserActive:output_type -> prservice.v1.User
+	12, // 40: prservice.v1.PRService.DeactivateUsers:output_type -> prservice.v1.DeactivateUsersResponse
+	14, // 41: prservice.v1.PRService.CreatePullRequest:output_type -> prservice.v1.PullRequest
+	14, // 42: prservice.v1.PRService.GetPullRequest:output_type -> prservice.v1.PullRequest
+	18, // 43: prservice.v1.PRService.ListPullRequests:output_type -> prservice.v1.ListPullRequestsResponse
+	14, // 44: prservice.v1.PRService.MergePullRequest:output_type -> prservice.v1.PullRequest
+	21, // 45: prservice.v1.PRService.ReassignReviewer:output_type -> prservice.v1.ReassignReviewerResponse
+	24, // 46: prservice.v1.PRService.GetStats:output_type -> prservice.v1.Stats
+	26, // 47: prservice.v1.PRService.WatchAssignmentEvents:output_type -> prservice.v1.AssignmentEvent
+	35, // [35:48] is the sub-list for method output_type
+	22, // [22:35] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_prservice_v1_prservice_proto_init() }
+func file_prservice_v1_prservice_proto_init() {
+	if File_prservice_v1_prservice_proto != nil {
+		return
+	}
+	file_prservice_v1_prservice_proto_msgTypes[0].OneofWrappers = []any{}
+	file_prservice_v1_prservice_proto_msgTypes[4].OneofWrappers = []any{}
+	file_prservice_v1_prservice_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_prservice_v1_prservice_proto_rawDesc), len(file_prservice_v1_prservice_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_prservice_v1_prservice_proto_goTypes,
+		DependencyIndexes: file_prservice_v1_prservice_proto_depIdxs,
+		EnumInfos:         file_prservice_v1_prservice_proto_enumTypes,
+		MessageInfos:      file_prservice_v1_prservice_proto_msgTypes,
+	}.Build()
+	File_prservice_v1_prservice_proto = out.File
+	file_prservice_v1_prservice_proto_goTypes = nil
+	file_prservice_v1_prservice_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"PRService/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// seedReviewerPRs создаёт команду и count PR, на каждый из которых назначены три ревьювера.
+// Возвращает первого ревьювера.
+func seedReviewerPRs(b *testing.B, count int) string {
+	b.Helper()
+	ctx := context.Background()
+
+	teamName := uniqueName("bench_team")
+	if err := repo.CreateTeam(ctx, domain.Team{TeamName: teamName}); err != nil {
+		b.Fatal(err)
+	}
+	users := make([]domain.User, 4)
+	for i := range users {
+		users[i] = domain.User{
+			UserID:   uniqueName(fmt.Sprintf("bench_u%d", i)),
+			Username: fmt.Sprintf("bench%d", i),
+			TeamName: teamName,
+			IsActive: true,
+		}
+	}
+	if err := repo.UpsertUsers(ctx, users); err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < count; i++ {
+		now := time.Now()
+		pr := domain.PullRequest{
+			PullRequestID:   uniqueName("bench_pr"),
+			PullRequestName: fmt.Sprintf("bench PR %d", i),
+			AuthorID:        users[0].UserID,
+			Status:          domain.StatusOpen,
+			CreatedAt:       &now,
+		}
+		var assignments []domain.ReviewerAssignment
+		for _, u := range users[1:] {
+			assignments = append(assignments, domain.ReviewerAssignment{UserID: u.UserID, Source: domain.SourceTeam, AssignedAt: &now})
+		}
+		if err := repo.CreatePR(ctx, pr, assignments); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return users[1].UserID
+}
+
+// BenchmarkListPRsByReviewer сравнивает выборку одним запросом с прежней схемой,
+// где ревьюверы каждого PR догружались отдельным обращением к базе.
+func BenchmarkListPRsByReviewer(b *testing.B) {
+	ctx := context.Background()
+	db := sqlx.MustConnect("pgx", os.Getenv("DATABASE_URL"))
+	b.Cleanup(func() { _ = db.Close() })
+
+	for _, count := range []int{10, 100, 500} {
+		reviewerID := seedReviewerPRs(b, count)
+
+		b.Run(fmt.Sprintf("single_query/%d", count), func(b *testing.B) {
+			for b.Loop() {
+				prs, err := repo.ListPRsByReviewer(ctx, reviewerID)
+				if err != nil || len(prs) != count {
+					b.Fatalf("got %d PRs, err %v", len(prs), err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("per_pr_lookup/%d", count), func(b *testing.B) {
+			for b.Loop() {
+				prs, err := legacyListPRsByReviewer(ctx, db, reviewerID)
+				if err != nil || len(prs) != count {
+					b.Fatalf("got %d PRs, err %v", len(prs), err)
+				}
+			}
+		})
+	}
+}
+
+// legacyListPRsByReviewer повторяет прежнюю выборку: список PR без ревьюверов,
+// затем отдельный запрос к pr_reviewers на каждый PR.
+func legacyListPRsByReviewer(ctx context.Context, db *sqlx.DB, userID string) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	err := db.SelectContext(ctx, &prs,
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status,
+		        pr.created_at, pr.merged_at, pr.repository, pr.pr_number
+		 FROM pull_requests pr
+		 JOIN pr_reviewers r ON pr.pull_request_id = r.pull_request_id
+		 WHERE r.user_id=$1`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range prs {
+		if err := legacyLoadAssignments(ctx, db, &prs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return prs, nil
+}
+
+// legacyGetPR повторяет прежний GetPR: строка PR и отдельный запрос ревьюверов.
+func legacyGetPR(ctx context.Context, db *sqlx.DB, prID string) (domain.PullRequest, error) {
+	var pr domain.PullRequest
+	err := db.GetContext(ctx, &pr,
+		`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at,
+		        repository, pr_number
+		 FROM pull_requests WHERE pull_request_id=$1`,
+		prID,
+	)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := legacyLoadAssignments(ctx, db, &pr); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return pr, nil
+}
+
+func legacyLoadAssignments(ctx context.Context, db *sqlx.DB, pr *domain.PullRequest) error {
+	var assignments []domain.ReviewerAssignment
+	err := db.SelectContext(ctx, &assignments,
+		`SELECT user_id, source, source_name, assigned_at
+		 FROM pr_reviewers WHERE pull_request_id=$1
+		 ORDER BY assigned_at, user_id`,
+		pr.PullRequestID,
+	)
+	if err != nil {
+		return err
+	}
+	pr.Assignments = assignments
+	for _, a := range assignments {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, a.UserID)
+	}
+	return nil
+}
+
+func BenchmarkGetPR(b *testing.B) {
+	ctx := context.Background()
+	db := sqlx.MustConnect("pgx", os.Getenv("DATABASE_URL"))
+	b.Cleanup(func() { _ = db.Close() })
+
+	reviewerID := seedReviewerPRs(b, 1)
+	prs, err := repo.ListPRsByReviewer(ctx, reviewerID)
+	if err != nil || len(prs) != 1 {
+		b.Fatalf("got %d PRs, err %v", len(prs), err)
+	}
+	prID := prs[0].PullRequestID
+
+	b.Run("single_query", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := repo.GetPR(ctx, prID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("per_pr_lookup", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := legacyGetPR(ctx, db, prID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"PRService/pkg/client"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := client.New(server.URL)
+
+	teamName := uniqueName("team")
+	author, reviewer1, reviewer2 := uniqueName("u"), uniqueName("u"), uniqueName("u")
+	team, err := c.CreateTeam(ctx, client.Team{
+		TeamName: teamName,
+		Members: []client.TeamMember{
+			{UserID: author, Username: "Alice", IsActive: true},
+			{UserID: reviewer1, Username: "Bob", IsActive: true},
+			{UserID: reviewer2, Username: "Carol", IsActive: true},
+		},
+	})
+	if err != nil || team.TeamName != teamName {
+		t.Fatalf("failed to create team: %+v, %v", team, err)
+	}
+	if _, err := c.CreateTeam(ctx, client.Team{TeamName: teamName}); !errors.Is(err, client.ErrTeamExists) {
+		t.Fatalf("expected ErrTeamExists, got %v", err)
+	}
+	if got, err := c.GetTeam(ctx, teamName); err != nil || len(got.Members) != 3 {
+		t.Fatalf("unexpected team %+v, %v", got, err)
+	}
+
+	_, err = c.GetTeam(ctx, uniqueName("missing"))
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "TEAM_NOT_FOUND" {
+		t.Fatalf("expected TEAM_NOT_FOUND, got %v", err)
+	}
+	if !errors.Is(err, client.ErrNotFound) || !errors.Is(err, client.ErrTeamNotFound) {
+		t.Fatalf("TEAM_NOT_FOUND must match ErrNotFound and ErrTeamNotFound, got %v", err)
+	}
+
+	prID := uniqueName("pr")
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: prID, PullRequestName: "Client PR", AuthorID: author})
+	if err != nil || len(pr.AssignedReviewers) != 2 || pr.Version != 1 {
+		t.Fatalf("unexpected PR %+v, %v", pr, err)
+	}
+	if _, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: prID, PullRequestName: "Client PR", AuthorID: author}); !errors.Is(err, client.ErrPrExists) {
+		t.Fatalf("expected ErrPrExists, got %v", err)
+	}
+	if _, err := c.GetPR(ctx, uniqueName("missing")); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	reviews, err := c.GetPRsForReviewer(ctx, reviewer1)
+	if err != nil || len(reviews) != 1 || reviews[0].PullRequestID != prID {
+		t.Fatalf("unexpected reviews %+v, %v", reviews, err)
+	}
+	page, err := c.ListPRs(ctx, client.PRListFilter{TeamName: teamName, Desc: true})
+	if err != nil || len(page.PullRequests) != 1 {
+		t.Fatalf("unexpected PR page %+v, %v", page, err)
+	}
+
+	if _, err := c.SubmitReview(ctx, prID, reviewer1, client.ReviewVerdict("MAYBE")); !errors.Is(err, client.ErrInvalidVerdict) {
+		t.Fatalf("expected ErrInvalidVerdict, got %v", err)
+	}
+	if _, err := c.SubmitReview(ctx, prID, reviewer1, client.VerdictApproved); err != nil {
+		t.Fatalf("failed to submit review: %v", err)
+	}
+
+	// отзыв поднял версию, поэтому слияние по первой версии отклоняется
+	if _, err := c.MergePR(ctx, prID, pr.Version); !errors.Is(err, client.ErrVersionMismatch) {
+		t.Fatalf("expected ErrVersionMismatch, got %v", err)
+	}
+	current, err := c.GetPR(ctx, prID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := c.MergePR(ctx, prID, current.Version)
+	if err != nil || merged.Status != client.StatusMerged {
+		t.Fatalf("failed to merge: %+v, %v", merged, err)
+	}
+	if _, _, err := c.ReassignReviewer(ctx, prID, reviewer1, 0); !errors.Is(err, client.ErrPrMerged) {
+		t.Fatalf("expected ErrPrMerged, got %v", err)
+	}
+
+	// автор не ревьюит ни одного PR, поэтому деактивируется без переназначений
+	results, err := c.DeactivateUsers(ctx, []string{author})
+	if err != nil || results[author] != "success" {
+		t.Fatalf("unexpected deactivation results %v, %v", results, err)
+	}
+	user, err := c.GetUser(ctx, author)
+	if err != nil || user.IsActive {
+		t.Fatalf("expected inactive user, got %+v, %v", user, err)
+	}
+
+	stats, err := c.GetStats(ctx, client.StatsFilter{TeamName: teamName})
+	if err != nil || stats.Teams[teamName].MergedPullRequests != 1 {
+		t.Fatalf("unexpected stats %+v, %v", stats, err)
+	}
+
+	if _, err := c.MigrationStatus(ctx); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without admin token, got %v", err)
+	}
+	status, err := client.New(server.URL, client.WithToken(adminToken)).MigrationStatus(ctx)
+	if err != nil || !status.UpToDate() {
+		t.Fatalf("unexpected migration status %+v, %v", status, err)
+	}
+}
+
+// flakyServer пропускает запросы к настоящим обработчикам, но теряет ответ на первые
+// failures из них, отдавая вместо него status.
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	keys     []string
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	fail := f.failures > 0
+	if fail {
+		f.failures--
+	}
+	f.keys = append(f.keys, r.Header.Get("Idempotency-Key"))
+	f.mu.Unlock()
+
+	if !fail {
+		server.Config.Handler.ServeHTTP(w, r)
+		return
+	}
+	server.Config.Handler.ServeHTTP(httptest.NewRecorder(), r)
+	w.Header().Set("Retry-After", "0")
+	w.WriteHeader(f.status)
+}
+
+// reset задаёт число следующих сбоев и возвращает ключи идемпотентности уже полученных запросов.
+func (f *flakyServer) reset(failures, status int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := f.keys
+	f.failures, f.status, f.keys = failures, status, nil
+	return keys
+}
+
+func TestClientRetries(t *testing.T) {
+	ctx := context.Background()
+	_, members := createTeam(t)
+
+	flaky := &flakyServer{}
+	flaky.reset(2, http.StatusBadGateway)
+	srv := httptest.NewServer(flaky)
+	defer srv.Close()
+
+	c := client.New(srv.URL, client.WithRetry(3, 10*time.Millisecond))
+
+	// первые два ответа потеряны, но PR уже создан: повтор с тем же ключом
+	// получает сохранённый ответ, а не PR_EXISTS
+	prID := uniqueName("pr")
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: prID, PullRequestName: "Retried", AuthorID: members[0]})
+	if err != nil || pr.PullRequestID != prID {
+		t.Fatalf("expected PR after retries, got %+v, %v", pr, err)
+	}
+	keys := flaky.reset(5, http.StatusServiceUnavailable)
+	if len(keys) != 3 || keys[0] == "" || keys[0] != keys[2] {
+		t.Fatalf("expected 3 attempts with the same Idempotency-Key, got %q", keys)
+	}
+
+	_, err = c.GetPR(ctx, prID)
+	var apiErr *client.Error
+	keys = flaky.reset(0, 0)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || len(keys) != 3 {
+		t.Fatalf("expected 503 after 3 attempts, got %v after %d", err, len(keys))
+	}
+
+	// ошибки, которые не исправятся повтором, возвращаются сразу
+	_, err = c.GetPR(ctx, uniqueName("missing"))
+	if keys := flaky.reset(0, 0); !errors.Is(err, client.ErrNotFound) || len(keys) != 1 {
+		t.Fatalf("expected single attempt with ErrNotFound, got %v after %d", err, len(keys))
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := c.GetPR(cancelled, prID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
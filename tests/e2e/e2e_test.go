@@ -2,13 +2,22 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	httphandler "PRService/internal/adapters/http"
+	"PRService/internal/domain"
+	"PRService/internal/services"
 )
 
 func postJSON(t *testing.T, url string, payload interface{}) *http.Response {
@@ -20,6 +29,23 @@ func postJSON(t *testing.T, url string, payload interface{}) *http.Response {
 	return resp
 }
 
+func postJSONWithHeaders(t *testing.T, url string, payload interface{}, headers map[string]string) *http.Response {
+	data, _ := json.Marshal(payload)
+	req, err := http.NewRequest(http.MethodPost, server.URL+url, bytes.NewBuffer(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", url, err)
+	}
+	return resp
+}
+
 func getJSON(t *testing.T, url string) *http.Response {
 	resp, err := http.Get(server.URL + url)
 	if err != nil {
@@ -63,6 +89,25 @@ func createTeam(t *testing.T) (string, []string) {
 	return teamName, []string{user1, user2}
 }
 
+// createTeamWithMembers создаёт команду из n активных участников.
+func createTeamWithMembers(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	teamName := uniqueName("team")
+	members := make([]string, n)
+	memberList := make([]map[string]interface{}, n)
+	for i := range members {
+		members[i] = uniqueName("u")
+		memberList[i] = map[string]interface{}{
+			"user_id": members[i], "username": fmt.Sprintf("member%d", i), "is_active": true,
+		}
+	}
+	resp := postJSON(t, "/team/add", map[string]interface{}{"team_name": teamName, "members": memberList})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create team: %d", resp.StatusCode)
+	}
+	return teamName, members
+}
+
 func createPR(t *testing.T, prID, authorID string) {
 	pr := map[string]string{
 		"pull_request_id":   prID,
@@ -118,6 +163,105 @@ func TestSetUserActive(t *testing.T) {
 	}
 }
 
+func TestUserDirectory(t *testing.T) {
+	teamName, members := createTeam(t)
+	otherTeam, _ := createTeam(t)
+
+	prefix := uniqueName("dir")
+	newUser := map[string]interface{}{
+		"user_id":   uniqueName("u"),
+		"username":  prefix + "_carol",
+		"team_name": teamName,
+		"is_active": true,
+		"timezone":  "Europe/Berlin",
+	}
+	resp := postJSON(t, "/users/add", newUser)
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to add user: %d", resp.StatusCode)
+	}
+	resp = postJSON(t, "/users/add", newUser)
+	if resp.StatusCode != 409 {
+		t.Fatalf("expected 409 for duplicate user, got %d", resp.StatusCode)
+	}
+	resp = postJSON(t, "/users/add", map[string]interface{}{
+		"user_id": uniqueName("u"), "username": "x", "team_name": uniqueName("missing"), "is_active": true,
+	})
+	if resp.StatusCode != 404 {
+		t.Fatalf("expected 404 for unknown team, got %d", resp.StatusCode)
+	}
+
+	implicitID := uniqueName("u")
+	resp = postJSON(t, "/users/add", map[string]interface{}{
+		"user_id": implicitID, "username": prefix + "_dave", "team_name": otherTeam,
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to add user without is_active: %d", resp.StatusCode)
+	}
+	resp = getJSON(t, fmt.Sprintf("/users/get?user_id=%s", implicitID))
+	if body := readBody(t, resp); !bytes.Contains(body, []byte(`"is_active":true`)) {
+		t.Fatalf("expected user without is_active to be active: %s", body)
+	}
+
+	userID := newUser["user_id"].(string)
+	resp = getJSON(t, fmt.Sprintf("/users/get?user_id=%s", userID))
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	body := readBody(t, resp)
+	if !bytes.Contains(body, []byte(`"timezone":"Europe/Berlin"`)) {
+		t.Fatalf("unexpected user: %s", body)
+	}
+
+	resp = postJSON(t, "/users/update", map[string]interface{}{
+		"user_id":   userID,
+		"team_name": otherTeam,
+		"is_active": false,
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to update user: %d", resp.StatusCode)
+	}
+	var updated struct {
+		User domain.User `json:"user"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.User.TeamName != otherTeam || updated.User.IsActive || updated.User.Username != prefix+"_carol" {
+		t.Fatalf("unexpected updated user: %+v", updated.User)
+	}
+
+	var page domain.UserPage
+	resp = getJSON(t, fmt.Sprintf("/users/list?username_prefix=%s&is_active=false", strings.ToUpper(prefix)))
+	if err := json.Unmarshal(readBody(t, resp), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Users) != 1 || page.Users[0].UserID != userID {
+		t.Fatalf("expected only %s by prefix, got %+v", userID, page.Users)
+	}
+
+	page = domain.UserPage{}
+	resp = getJSON(t, fmt.Sprintf("/users/list?team_name=%s&limit=1", teamName))
+	if err := json.Unmarshal(readBody(t, resp), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Users) != 1 || page.NextCursor == "" {
+		t.Fatalf("expected first page with cursor, got %+v", page)
+	}
+	next := domain.UserPage{}
+	resp = getJSON(t, fmt.Sprintf("/users/list?team_name=%s&limit=1&cursor=%s", teamName, page.NextCursor))
+	if err := json.Unmarshal(readBody(t, resp), &next); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Users) != 1 || next.NextCursor != "" || next.Users[0].UserID == page.Users[0].UserID {
+		t.Fatalf("expected second member of %v, got %+v", members, next)
+	}
+
+	resp = getJSON(t, "/users/get?user_id=missing_user")
+	if resp.StatusCode != 404 {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
 func TestStats(t *testing.T) {
 	teamName := uniqueName("team")
 	team := map[string]interface{}{
@@ -153,6 +297,232 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestFilteredStats(t *testing.T) {
+	teamName, members := createTeam(t)
+	openPR := uniqueName("pr_open")
+	mergedPR := uniqueName("pr_merged")
+	createPR(t, openPR, members[0])
+	createPR(t, mergedPR, members[0])
+
+	resp := postJSON(t, "/pullRequest/merge", map[string]string{"pull_request_id": mergedPR})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to merge PR: %d", resp.StatusCode)
+	}
+
+	var stats domain.Stats
+	resp = getJSON(t, fmt.Sprintf("/stats?team_name=%s", teamName))
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(readBody(t, resp), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Teams) != 1 {
+		t.Fatalf("expected stats for one team, got %v", stats.Teams)
+	}
+	team := stats.Teams[teamName]
+	if team.PullRequests != 2 || team.OpenPullRequests != 1 || team.MergedPullRequests != 1 {
+		t.Fatalf("unexpected team stats: %+v", team)
+	}
+	if stats.ReviewerAssignments[members[1]] != 2 || stats.ReviewerOpenAssignments[members[1]] != 1 {
+		t.Fatalf("expected 2 total and 1 open assignment for %s, got %d/%d",
+			members[1], stats.ReviewerAssignments[members[1]], stats.ReviewerOpenAssignments[members[1]])
+	}
+
+	stats = domain.Stats{}
+	resp = getJSON(t, fmt.Sprintf("/stats?team_name=%s&status=MERGED", teamName))
+	if err := json.Unmarshal(readBody(t, resp), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stats.PRAssignments[openPR]; ok {
+		t.Fatal("open PR must be filtered out by status")
+	}
+	if _, ok := stats.PRAssignments[mergedPR]; !ok {
+		t.Fatal("merged PR missing from filtered stats")
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	stats = domain.Stats{}
+	resp = getJSON(t, fmt.Sprintf("/stats?team_name=%s&from=%s", teamName, future))
+	if err := json.Unmarshal(readBody(t, resp), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Teams) != 0 || len(stats.PRAssignments) != 0 {
+		t.Fatalf("expected no stats after %s, got %+v", future, stats)
+	}
+
+	resp = getJSON(t, "/stats?status=CLOSED")
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for unknown status, got %d", resp.StatusCode)
+	}
+}
+
+func TestReviewCycleTime(t *testing.T) {
+	teamName, members := createTeam(t)
+	prID := uniqueName("pr_cycle")
+	createPR(t, prID, members[0])
+
+	resp := postJSON(t, "/pullRequest/review", map[string]string{
+		"pull_request_id": prID,
+		"reviewer_id":     members[0],
+		"verdict":         "APPROVED",
+	})
+	if resp.StatusCode != 409 {
+		t.Fatalf("expected 409 for author review, got %d", resp.StatusCode)
+	}
+
+	for _, verdict := range []string{"CHANGES_REQUESTED", "APPROVED"} {
+		resp = postJSON(t, "/pullRequest/review", map[string]string{
+			"pull_request_id": prID,
+			"reviewer_id":     members[1],
+			"verdict":         verdict,
+		})
+		if resp.StatusCode != 201 {
+			t.Fatalf("failed to submit %s review: %d", verdict, resp.StatusCode)
+		}
+	}
+
+	resp = postJSON(t, "/pullRequest/merge", map[string]string{"pull_request_id": prID})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to merge PR: %d", resp.StatusCode)
+	}
+
+	var stats domain.CycleTimeStats
+	resp = getJSON(t, fmt.Sprintf("/stats/cycle-time?team_name=%s", teamName))
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(readBody(t, resp), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	team, ok := stats.Teams[teamName]
+	if !ok {
+		t.Fatalf("no cycle time for team %s: %+v", teamName, stats)
+	}
+	for name, d := range map[string]domain.DurationStats{
+		"first review": team.FirstReview,
+		"approval":     team.Approval,
+		"merge":        team.Merge,
+	} {
+		if d.Count != 1 || d.Median == nil || d.P90 == nil {
+			t.Fatalf("unexpected %s stats: %+v", name, d)
+		}
+	}
+	if *team.FirstReview.Median > *team.Approval.Median {
+		t.Fatal("first review must not be later than approval")
+	}
+
+	if stats.Reviewers[members[1]].Approval.Count != 1 {
+		t.Fatalf("expected approval for reviewer %s, got %+v", members[1], stats.Reviewers[members[1]])
+	}
+	if len(stats.Weekly[teamName]) != 1 {
+		t.Fatalf("expected one weekly point, got %+v", stats.Weekly[teamName])
+	}
+}
+
+func TestFairnessReport(t *testing.T) {
+	teamName, members := createTeam(t)
+	createPR(t, uniqueName("pr_fair"), members[0])
+
+	var report domain.FairnessReport
+	resp := getJSON(t, fmt.Sprintf("/stats/fairness?team_name=%s", teamName))
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(readBody(t, resp), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	team, ok := report.Teams[teamName]
+	if !ok {
+		t.Fatalf("no fairness metrics for team %s: %+v", teamName, report)
+	}
+	if team.Reviewers != 2 || team.Assignments != 1 {
+		t.Fatalf("expected 2 reviewers and 1 assignment, got %+v", team)
+	}
+	// один ревьювер получил всё, другой ничего: Gini для двух человек равен 0.5
+	if team.Gini < 0.49 || team.Gini > 0.51 {
+		t.Fatalf("expected gini 0.5, got %f", team.Gini)
+	}
+	if team.MaxMinRatio != nil {
+		t.Fatalf("max/min ratio must be empty when someone has no reviews, got %f", *team.MaxMinRatio)
+	}
+
+	resp = getJSON(t, "/stats/fairness?from=2025-02-01&to=2025-01-01")
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for inverted window, got %d", resp.StatusCode)
+	}
+	resp = getJSON(t, "/stats/fairness?status=OPEN")
+	if body := readBody(t, resp); resp.StatusCode != 400 || !bytes.Contains(body, []byte("INVALID_STATS_FILTER")) {
+		t.Fatalf("expected 400 INVALID_STATS_FILTER for status, got %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestListPRs(t *testing.T) {
+	teamName, members := createTeam(t)
+	prIDs := []string{uniqueName("pr_list"), uniqueName("pr_list"), uniqueName("pr_list")}
+	for _, prID := range prIDs {
+		createPR(t, prID, members[0])
+	}
+
+	listPage := func(url string) domain.PRPage {
+		t.Helper()
+		resp := getJSON(t, url)
+		if resp.StatusCode != 200 {
+			t.Fatalf("GET %s: expected 200, got %d", url, resp.StatusCode)
+		}
+		var page domain.PRPage
+		if err := json.Unmarshal(readBody(t, resp), &page); err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+
+	first := listPage(fmt.Sprintf("/pullRequest/list?author_id=%s&order=asc&limit=2", members[0]))
+	if len(first.PullRequests) != 2 || first.NextCursor == "" {
+		t.Fatalf("expected first page of 2 with cursor, got %+v", first)
+	}
+	second := listPage(fmt.Sprintf("/pullRequest/list?author_id=%s&order=asc&limit=2&cursor=%s", members[0], first.NextCursor))
+	if len(second.PullRequests) != 1 || second.NextCursor != "" {
+		t.Fatalf("expected last page of 1 without cursor, got %+v", second)
+	}
+
+	var got []string
+	for _, pr := range append(first.PullRequests, second.PullRequests...) {
+		got = append(got, pr.PullRequestID)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(prIDs) {
+		t.Fatalf("expected PRs in creation order %v, got %v", prIDs, got)
+	}
+	if len(first.PullRequests[0].AssignedReviewers) != 1 || first.PullRequests[0].AssignedReviewers[0] != members[1] {
+		t.Fatalf("expected reviewer %s, got %v", members[1], first.PullRequests[0].AssignedReviewers)
+	}
+
+	byReviewer := listPage(fmt.Sprintf("/pullRequest/list?reviewer_id=%s&team_name=%s", members[1], teamName))
+	if len(byReviewer.PullRequests) != 3 {
+		t.Fatalf("expected 3 PRs for reviewer, got %d", len(byReviewer.PullRequests))
+	}
+
+	resp := postJSON(t, "/pullRequest/merge", map[string]string{"pull_request_id": prIDs[1]})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to merge PR: %d", resp.StatusCode)
+	}
+	merged := listPage(fmt.Sprintf("/pullRequest/list?team_name=%s&status=MERGED&sort=merged_at", teamName))
+	if len(merged.PullRequests) != 1 || merged.PullRequests[0].PullRequestID != prIDs[1] {
+		t.Fatalf("expected only merged PR %s, got %+v", prIDs[1], merged.PullRequests)
+	}
+
+	resp = getJSON(t, "/pullRequest/list?cursor=garbage")
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for invalid cursor, got %d", resp.StatusCode)
+	}
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/list?sort=merged_at&cursor=%s", first.NextCursor))
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for cursor from another sort, got %d", resp.StatusCode)
+	}
+}
+
 func TestGetUserPRs(t *testing.T) {
 	_, members := createTeam(t)
 	prID := uniqueName("pr")
@@ -182,6 +552,47 @@ func TestCreatePR(t *testing.T) {
 	}
 }
 
+func TestGetPR(t *testing.T) {
+	teamName, members := createTeam(t)
+	prID := uniqueName("pr_get")
+	createPR(t, prID, members[0])
+
+	resp := postJSON(t, "/pullRequest/review", map[string]string{
+		"pull_request_id": prID,
+		"reviewer_id":     members[1],
+		"verdict":         "APPROVED",
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to submit review: %d", resp.StatusCode)
+	}
+
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", prID))
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got struct {
+		PR domain.PullRequest `json:"pr"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.PR.AuthorTeam != teamName || got.PR.CreatedAt == nil {
+		t.Fatalf("unexpected PR: %+v", got.PR)
+	}
+	if len(got.PR.Assignments) != 1 {
+		t.Fatalf("expected one assignment, got %+v", got.PR.Assignments)
+	}
+	a := got.PR.Assignments[0]
+	if a.UserID != members[1] || a.Verdict == nil || *a.Verdict != domain.VerdictApproved || a.ReviewedAt == nil {
+		t.Fatalf("expected approved review by %s, got %+v", members[1], a)
+	}
+
+	resp = getJSON(t, "/pullRequest/get?pull_request_id=missing_pr")
+	if resp.StatusCode != 404 {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
 func TestMergePR(t *testing.T) {
 	_, members := createTeam(t)
 	prID := uniqueName("pr_merge")
@@ -242,3 +653,1262 @@ func TestReassignReviewer(t *testing.T) {
 		t.Fatal("reviewer was not replaced with expected candidate u203")
 	}
 }
+
+func TestCodeHostRejectionIsNotRetried(t *testing.T) {
+	_, members := createTeam(t)
+	repository := "acme/" + uniqueName("rejected-repo")
+	resp := postJSON(t, "/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   uniqueName("pr"),
+		"pull_request_name": "Unknown reviewer login",
+		"author_id":         members[0],
+		"repository":        repository,
+		"pr_number":         7,
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/7/requested_reviewers", repository)
+	if _, ok := fakeGitHub.waitFor(http.MethodPost, path, 2*time.Second); !ok {
+		t.Fatal("reviewers were not requested on the code host")
+	}
+	// повторы идут с задержкой 10ms, так что за это время их было бы уже несколько
+	time.Sleep(200 * time.Millisecond)
+	if n := fakeGitHub.count(http.MethodPost, path); n != 1 {
+		t.Fatalf("expected a rejected request not to be retried, got %d requests", n)
+	}
+}
+
+func TestManualReviewers(t *testing.T) {
+	teamName, members := createTeamWithMembers(t, 4)
+	_, outsiders := createTeam(t)
+
+	resp := postJSON(t, "/team/setReviewerLimits", map[string]interface{}{
+		"team_name": teamName, "min_reviewers": 2, "max_reviewers": 3,
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set reviewer limits: %d", resp.StatusCode)
+	}
+
+	prID := uniqueName("pr_manual")
+	createPR(t, prID, members[0])
+
+	getPR := func() domain.PullRequest {
+		t.Helper()
+		var got struct {
+			PR domain.PullRequest `json:"pr"`
+		}
+		resp := getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", prID))
+		if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got.PR
+	}
+
+	pr := getPR()
+	if len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 auto-assigned reviewers, got %v", pr.AssignedReviewers)
+	}
+	var free string
+	for _, m := range members[1:] {
+		if !slices.Contains(pr.AssignedReviewers, m) {
+			free = m
+		}
+	}
+
+	change := func(url string, body map[string]string, want int) {
+		t.Helper()
+		resp := postJSON(t, url, body)
+		if resp.StatusCode != want {
+			t.Fatalf("%s %v: expected %d, got %d: %s", url, body, want, resp.StatusCode, readBody(t, resp))
+		}
+	}
+
+	change("/pullRequest/reviewers/add", map[string]string{"pull_request_id": prID, "reviewer_id": outsiders[0]}, 409)
+	change("/pullRequest/reviewers/add", map[string]string{"pull_request_id": prID, "reviewer_id": free}, 200)
+
+	pr = getPR()
+	if len(pr.AssignedReviewers) != 3 || pr.Assignments[2].UserID != free || pr.Assignments[2].Source != domain.SourceManual {
+		t.Fatalf("expected %s added manually, got %+v", free, pr.Assignments)
+	}
+
+	change("/pullRequest/reviewers/remove", map[string]string{"pull_request_id": prID, "reviewer_id": free}, 200)
+	change("/pullRequest/reviewers/remove", map[string]string{"pull_request_id": prID, "reviewer_id": pr.AssignedReviewers[0]}, 409)
+
+	resp = postJSON(t, "/pullRequest/reassign", map[string]string{
+		"pull_request_id": prID, "old_reviewer_id": pr.AssignedReviewers[0], "new_reviewer_id": members[0],
+	})
+	if body := readBody(t, resp); resp.StatusCode != 409 || !bytes.Contains(body, []byte("REVIEWER_IS_AUTHOR")) {
+		t.Fatalf("expected 409 REVIEWER_IS_AUTHOR for the author, got %d: %s", resp.StatusCode, body)
+	}
+	change("/pullRequest/reassign", map[string]string{
+		"pull_request_id": prID, "old_reviewer_id": pr.AssignedReviewers[0], "new_reviewer_id": free,
+	}, 200)
+
+	pr = getPR()
+	if !slices.Contains(pr.AssignedReviewers, free) || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected %s to replace reviewer, got %v", free, pr.AssignedReviewers)
+	}
+
+	resp = postJSON(t, "/team/setReviewerLimits", map[string]interface{}{
+		"team_name": teamName, "min_reviewers": 3, "max_reviewers": 3,
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to raise reviewer limits: %d", resp.StatusCode)
+	}
+	prID = uniqueName("pr_manual")
+	createPR(t, prID, members[0])
+	if pr = getPR(); len(pr.AssignedReviewers) != 3 {
+		t.Fatalf("expected min_reviewers=3 to be auto-assigned, got %v", pr.AssignedReviewers)
+	}
+}
+
+// TestConcurrentReviewerRemoval проверяет, что параллельные снятия не опускают PR ниже
+// минимума команды: проверка идёт на версии PR, с которой выполняется запись.
+func TestConcurrentReviewerRemoval(t *testing.T) {
+	teamName, members := createTeamWithMembers(t, 4)
+	resp := postJSON(t, "/team/setReviewerLimits", map[string]interface{}{
+		"team_name": teamName, "min_reviewers": 2, "max_reviewers": 3,
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set reviewer limits: %d", resp.StatusCode)
+	}
+
+	prID := uniqueName("pr_race")
+	createPR(t, prID, members[0])
+	var created struct {
+		PR domain.PullRequest `json:"pr"`
+	}
+	if err := json.Unmarshal(readBody(t, getJSON(t, "/pullRequest/get?pull_request_id="+prID)), &created); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range members[1:] {
+		if !slices.Contains(created.PR.AssignedReviewers, m) {
+			if resp := postJSON(t, "/pullRequest/reviewers/add", map[string]string{"pull_request_id": prID, "reviewer_id": m}); resp.StatusCode != 200 {
+				t.Fatalf("failed to add third reviewer: %d", resp.StatusCode)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	statuses := make([]int, 3)
+	for i, m := range members[1:] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := postJSON(t, "/pullRequest/reviewers/remove", map[string]string{"pull_request_id": prID, "reviewer_id": m})
+			readBody(t, resp)
+			statuses[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+
+	if n := slices.Index(statuses, 200); n < 0 || slices.Index(statuses[n+1:], 200) >= 0 {
+		t.Fatalf("expected exactly one removal to succeed, got %v", statuses)
+	}
+	var got struct {
+		PR domain.PullRequest `json:"pr"`
+	}
+	if err := json.Unmarshal(readBody(t, getJSON(t, "/pullRequest/get?pull_request_id="+prID)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.PR.AssignedReviewers) != 2 {
+		t.Fatalf("PR dropped below min_reviewers: %v", got.PR.AssignedReviewers)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	_, members := createTeam(t)
+	key := map[string]string{"Idempotency-Key": uniqueName("key")}
+	pr := map[string]string{
+		"pull_request_id":   uniqueName("pr_idem"),
+		"pull_request_name": "Idempotent PR",
+		"author_id":         members[0],
+	}
+
+	first := postJSONWithHeaders(t, "/pullRequest/create", pr, key)
+	if first.StatusCode != 201 || first.Header.Get("ETag") == "" {
+		t.Fatalf("expected 201 with ETag, got %d %q", first.StatusCode, first.Header.Get("ETag"))
+	}
+	firstBody := readBody(t, first)
+
+	retry := postJSONWithHeaders(t, "/pullRequest/create", pr, key)
+	if retry.StatusCode != 201 {
+		t.Fatalf("expected replayed 201, got %d", retry.StatusCode)
+	}
+	if retry.Header.Get("Idempotent-Replayed") != "true" {
+		t.Fatal("retry was not served from the idempotency store")
+	}
+	if retry.Header.Get("ETag") != first.Header.Get("ETag") {
+		t.Fatalf("replayed ETag %q differs from the original %q", retry.Header.Get("ETag"), first.Header.Get("ETag"))
+	}
+	if !bytes.Equal(readBody(t, retry), firstBody) {
+		t.Fatal("replayed body differs from the original response")
+	}
+
+	// тот же ключ другого API-токена — отдельный ключ, запрос доходит до обработчика
+	resp := postJSONWithHeaders(t, "/pullRequest/create", pr, map[string]string{
+		"Idempotency-Key": key["Idempotency-Key"], "Authorization": "Bearer " + uniqueName("token"),
+	})
+	if body := readBody(t, resp); resp.StatusCode != 409 || !bytes.Contains(body, []byte("PR_EXISTS")) {
+		t.Fatalf("expected PR_EXISTS for another token's key, got %d: %s", resp.StatusCode, body)
+	}
+
+	resp = postJSONWithHeaders(t, "/pullRequest/create?dry_run=1", pr, key)
+	if body := readBody(t, resp); resp.StatusCode != 409 || !bytes.Contains(body, []byte("IDEMPOTENCY_KEY_REUSED")) {
+		t.Fatalf("expected IDEMPOTENCY_KEY_REUSED for a different query, got %d: %s", resp.StatusCode, body)
+	}
+
+	pr["pull_request_name"] = "Another payload"
+	resp = postJSONWithHeaders(t, "/pullRequest/create", pr, key)
+	if resp.StatusCode != 409 {
+		t.Fatalf("expected 409 for reused key, got %d", resp.StatusCode)
+	}
+	if body := readBody(t, resp); !bytes.Contains(body, []byte("IDEMPOTENCY_KEY_REUSED")) {
+		t.Fatalf("unexpected error body: %s", body)
+	}
+
+	// без ключа запрос доходит до обработчика, а не до сохранённого ответа
+	resp = postJSON(t, "/pullRequest/create", pr)
+	body := readBody(t, resp)
+	if resp.StatusCode != 409 || !bytes.Contains(body, []byte("PR_EXISTS")) || resp.Header.Get("Idempotent-Replayed") != "" {
+		t.Fatalf("expected PR_EXISTS from the handler, got %d %q: %s", resp.StatusCode, resp.Header.Get("Idempotent-Replayed"), body)
+	}
+}
+
+// TestIdempotencyKeyAbandonedReservation проверяет, что ключ, занятый упавшим процессом,
+// перезанимается ретраем после истечения аренды, а не висит до конца TTL.
+func TestIdempotencyKeyAbandonedReservation(t *testing.T) {
+	_, members := createTeam(t)
+	key := uniqueName("key")
+	pr := map[string]string{
+		"pull_request_id":   uniqueName("pr_idem"),
+		"pull_request_name": "Abandoned PR",
+		"author_id":         members[0],
+	}
+	payload, _ := json.Marshal(pr)
+	fingerprint := httphandler.IdempotencyFingerprint(http.MethodPost, "/pullRequest/create", payload)
+
+	ctx := context.Background()
+	now := time.Now()
+	if _, reserved, err := repo.ReserveIdempotencyKey(ctx, "", key, fingerprint, "crashed", now.Add(time.Minute), now.Add(time.Hour)); err != nil || !reserved {
+		t.Fatalf("failed to reserve key: %v %v", reserved, err)
+	}
+	resp := postJSONWithHeaders(t, "/pullRequest/create", pr, map[string]string{"Idempotency-Key": key})
+	if body := readBody(t, resp); resp.StatusCode != 409 || !bytes.Contains(body, []byte("IDEMPOTENCY_KEY_IN_PROGRESS")) {
+		t.Fatalf("expected IDEMPOTENCY_KEY_IN_PROGRESS while the lease is active, got %d: %s", resp.StatusCode, body)
+	}
+
+	// аренда истекла: владелец не продлевал её
+	if err := repo.ExtendIdempotencyKey(ctx, "", key, "crashed", now.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	resp = postJSONWithHeaders(t, "/pullRequest/create", pr, map[string]string{"Idempotency-Key": key})
+	if body := readBody(t, resp); resp.StatusCode != 201 {
+		t.Fatalf("expected the retry to take over the abandoned key, got %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestPRVersionETag(t *testing.T) {
+	_, members := createTeamWithMembers(t, 4)
+
+	prID := uniqueName("pr_etag")
+	resp := postJSON(t, "/pullRequest/create", map[string]string{
+		"pull_request_id":   prID,
+		"pull_request_name": "ETag PR",
+		"author_id":         members[0],
+	})
+	if resp.StatusCode != 201 || resp.Header.Get("ETag") != `"1"` {
+		t.Fatalf("expected 201 with ETag \"1\", got %d %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", prID))
+	etag := resp.Header.Get("ETag")
+	var got struct {
+		PR domain.PullRequest `json:"pr"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	if etag != `"1"` || got.PR.Version != 1 || len(got.PR.AssignedReviewers) != 2 {
+		t.Fatalf("unexpected PR state: etag=%q %+v", etag, got.PR)
+	}
+	var free string
+	for _, m := range members[1:] {
+		if !slices.Contains(got.PR.AssignedReviewers, m) {
+			free = m
+		}
+	}
+
+	// два лида прочитали одну версию PR и переназначают ревьюверов одновременно
+	resp = postJSONWithHeaders(t, "/pullRequest/reassign", map[string]string{
+		"pull_request_id": prID, "old_reviewer_id": got.PR.AssignedReviewers[0], "new_reviewer_id": free,
+	}, map[string]string{"If-Match": etag})
+	if resp.StatusCode != 200 || resp.Header.Get("ETag") != `"2"` {
+		t.Fatalf("expected 200 with ETag \"2\", got %d %q: %s", resp.StatusCode, resp.Header.Get("ETag"), readBody(t, resp))
+	}
+
+	resp = postJSONWithHeaders(t, "/pullRequest/reassign", map[string]string{
+		"pull_request_id": prID, "old_reviewer_id": got.PR.AssignedReviewers[1],
+	}, map[string]string{"If-Match": etag})
+	if resp.StatusCode != 412 {
+		t.Fatalf("expected 412 for stale If-Match, got %d", resp.StatusCode)
+	}
+
+	resp = postJSONWithHeaders(t, "/pullRequest/merge", map[string]string{"pull_request_id": prID},
+		map[string]string{"If-Match": "W/\"2\""})
+	if resp.StatusCode != 412 {
+		t.Fatalf("expected 412 for weak If-Match, got %d", resp.StatusCode)
+	}
+
+	resp = postJSONWithHeaders(t, "/pullRequest/merge", map[string]string{"pull_request_id": prID},
+		map[string]string{"If-Match": `"2"`})
+	if resp.StatusCode != 200 || resp.Header.Get("ETag") != `"3"` {
+		t.Fatalf("expected merge with ETag \"3\", got %d %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+
+	resp = postJSON(t, "/pullRequest/merge", map[string]string{"pull_request_id": prID})
+	if resp.StatusCode != 200 || resp.Header.Get("ETag") != `"3"` {
+		t.Fatalf("repeated merge without If-Match must not change the PR, got %d %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	// токены считаются в общей корзине в Postgres, IP — в памяти
+	limited := httptest.NewServer(httphandler.RateLimit(repo, httphandler.RateLimitConfig{
+		PerToken: domain.RateLimit{PerSecond: 0.1, Burst: 2},
+	})(ok))
+	defer limited.Close()
+	byIP := httptest.NewServer(httphandler.RateLimit(httphandler.NewMemoryRateLimitStore(), httphandler.RateLimitConfig{
+		PerIP: domain.RateLimit{PerSecond: 0.1, Burst: 1},
+	})(httphandler.MaxBodySize(16)(ok)))
+	defer byIP.Close()
+
+	do := func(url, token, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		return resp
+	}
+
+	token := uniqueName("token")
+	for i := 0; i < 2; i++ {
+		if resp := do(limited.URL, token, "{}"); resp.StatusCode != 200 {
+			t.Fatalf("request %d within burst: expected 200, got %d", i, resp.StatusCode)
+		}
+	}
+	resp := do(limited.URL, token, "{}")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after burst, got %d", resp.StatusCode)
+	}
+	if retry := resp.Header.Get("Retry-After"); retry != "10" {
+		t.Fatalf("expected Retry-After 10, got %q", retry)
+	}
+	if resp := do(limited.URL, uniqueName("token"), "{}"); resp.StatusCode != 200 {
+		t.Fatalf("other token must have its own bucket, got %d", resp.StatusCode)
+	}
+	if resp := do(limited.URL, "", "{}"); resp.StatusCode != 200 {
+		t.Fatalf("anonymous requests are not limited by token, got %d", resp.StatusCode)
+	}
+
+	if resp := do(byIP.URL, "", strings.Repeat("x", 17)); resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for large body, got %d", resp.StatusCode)
+	}
+	if resp := do(byIP.URL, "", "{}"); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for second request from the same IP, got %d", resp.StatusCode)
+	}
+
+	// общий сервер тестов собран тем же NewRouter, что и прод, с лимитом тела 1 MiB
+	if resp := do(server.URL+"/team/add", "", strings.Repeat("x", 1<<20+1)); resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 from the API router, got %d", resp.StatusCode)
+	}
+}
+
+func TestChunkedBodyTooLarge(t *testing.T) {
+	handler := &httphandler.Handler{}
+	limited := httptest.NewServer(httphandler.MaxBodySize(16)(http.HandlerFunc(handler.CreateTeam)))
+	defer limited.Close()
+
+	// io.MultiReader скрывает длину, и тело уходит chunked без Content-Length
+	body := io.MultiReader(strings.NewReader(`{"team_name":"`), strings.NewReader(strings.Repeat("x", 64)+`"}`))
+	resp, err := http.Post(limited.URL, "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(readBody(t, resp)); resp.StatusCode != http.StatusRequestEntityTooLarge || !strings.Contains(got, "REQUEST_TOO_LARGE") {
+		t.Fatalf("expected 413 REQUEST_TOO_LARGE, got %d %s", resp.StatusCode, got)
+	}
+
+	resp, err = http.Post(limited.URL, "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readBody(t, resp); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed body, got %d", resp.StatusCode)
+	}
+}
+
+func TestBulkCreatePRs(t *testing.T) {
+	_, members := createTeam(t)
+	existing := uniqueName("pr_bulk_existing")
+	createPR(t, existing, members[0])
+
+	auto, manual := uniqueName("pr_bulk_auto"), uniqueName("pr_bulk_manual")
+	lines := []map[string]interface{}{
+		{"pull_request_id": auto, "pull_request_name": "Auto", "author_id": members[0]},
+		{"pull_request_id": manual, "pull_request_name": "Manual", "author_id": members[1], "reviewers": []string{members[0]},
+			"created_at": "2025-01-02T03:04:05Z"},
+		{"pull_request_id": auto, "pull_request_name": "Again", "author_id": members[0]},
+		{"pull_request_id": existing, "pull_request_name": "Existing", "author_id": members[0]},
+		{"pull_request_id": uniqueName("pr_bulk_orphan"), "pull_request_name": "Orphan", "author_id": uniqueName("ghost")},
+		{"pull_request_name": "No id", "author_id": members[0]},
+	}
+	var ndjson bytes.Buffer
+	for _, l := range lines {
+		b, _ := json.Marshal(l)
+		ndjson.Write(b)
+		ndjson.WriteByte('\n')
+	}
+
+	resp, err := http.Post(server.URL+"/pullRequest/bulkCreate", "application/x-ndjson", &ndjson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got struct {
+		Results map[string]string `json:"results"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		auto:                                 "success",
+		manual:                               "success",
+		auto + "#3":                          "duplicate pull_request_id in request",
+		existing:                             "PR already exists",
+		lines[4]["pull_request_id"].(string): "author not found",
+		"#6":                                 "pull_request_id, pull_request_name and author_id are required",
+	}
+	for k, v := range want {
+		if got.Results[k] != v {
+			t.Errorf("result for %s: expected %q, got %q", k, v, got.Results[k])
+		}
+	}
+
+	var pr struct {
+		PR domain.PullRequest `json:"pr"`
+	}
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", manual))
+	if err := json.Unmarshal(readBody(t, resp), &pr); err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.PR.Assignments) != 1 || pr.PR.Assignments[0].UserID != members[0] || pr.PR.Assignments[0].Source != domain.SourceManual {
+		t.Fatalf("expected pre-chosen reviewer %s, got %+v", members[0], pr.PR.Assignments)
+	}
+	if pr.PR.CreatedAt == nil || !pr.PR.CreatedAt.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("expected original created_at, got %v", pr.PR.CreatedAt)
+	}
+
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", auto))
+	if err := json.Unmarshal(readBody(t, resp), &pr); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(pr.PR.AssignedReviewers, []string{members[1]}) {
+		t.Fatalf("expected auto-assigned reviewer %s, got %v", members[1], pr.PR.AssignedReviewers)
+	}
+
+	array := uniqueName("pr_bulk_array")
+	resp = postJSON(t, "/pullRequest/bulkCreate", []map[string]string{
+		{"pull_request_id": array, "pull_request_name": "Array", "author_id": members[1]},
+	})
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Results[array] != "success" {
+		t.Fatalf("expected JSON array item to be created, got %v", got.Results)
+	}
+
+	// тело больше общего лимита в 1 MiB принимается: загрузка читается потоком
+	large := uniqueName("pr_bulk_large")
+	body := fmt.Sprintf(`{"pull_request_id":%q,"pull_request_name":"Large","author_id":%q}`, large, members[1]) +
+		strings.Repeat("\n", 1<<20+1)
+	resp, err = http.Post(server.URL+"/pullRequest/bulkCreate", "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Results[large] != "success" {
+		t.Fatalf("expected item from large body to be created, got %v", got.Results)
+	}
+
+	// PR до невалидного элемента уже записаны
+	beforeBad := uniqueName("pr_bulk_before_bad")
+	body = fmt.Sprintf(`{"pull_request_id":%q,"pull_request_name":"Before","author_id":%q}`, beforeBad, members[1]) + "\n{bad"
+	resp, err = http.Post(server.URL+"/pullRequest/bulkCreate", "application/x-ndjson", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readBody(t, resp); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed item, got %d", resp.StatusCode)
+	}
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", beforeBad))
+	if readBody(t, resp); resp.StatusCode != 200 {
+		t.Fatalf("expected PR before malformed item to be created, got %d", resp.StatusCode)
+	}
+}
+
+func TestBulkCreatePRsReviewerChecks(t *testing.T) {
+	_, members := createTeam(t)
+	limitedTeam, outsiders := createTeam(t)
+	if resp := postJSON(t, "/users/setIsActive", map[string]interface{}{"user_id": members[1], "is_active": false}); resp.StatusCode != 200 {
+		t.Fatalf("failed to deactivate user: %d", resp.StatusCode)
+	}
+	resp := postJSON(t, "/team/setReviewerLimits", map[string]interface{}{
+		"team_name": limitedTeam, "min_reviewers": 1, "max_reviewers": 1,
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set reviewer limits: %d", resp.StatusCode)
+	}
+
+	inactive, outsider, tooMany := uniqueName("pr_bulk_inactive"), uniqueName("pr_bulk_outsider"), uniqueName("pr_bulk_too_many")
+	resp = postJSON(t, "/pullRequest/bulkCreate", []map[string]interface{}{
+		{"pull_request_id": inactive, "pull_request_name": "Inactive", "author_id": members[0], "reviewers": []string{members[1]}},
+		{"pull_request_id": outsider, "pull_request_name": "Outsider", "author_id": members[0], "reviewers": []string{outsiders[0]}},
+		{"pull_request_id": tooMany, "pull_request_name": "Too many", "author_id": outsiders[0], "reviewers": []string{outsiders[1], members[0]}},
+	})
+	var got struct {
+		Results map[string]string `json:"results"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		inactive: fmt.Sprintf("reviewer %s is inactive", members[1]),
+		outsider: fmt.Sprintf("reviewer %s is not in author's team, partner teams or pools", outsiders[0]),
+		tooMany:  "at most 1 reviewers allowed",
+	}
+	for k, v := range want {
+		if got.Results[k] != v {
+			t.Errorf("result for %s: expected %q, got %q", k, v, got.Results[k])
+		}
+	}
+	if resp := getJSON(t, "/pullRequest/get?pull_request_id="+inactive); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("rejected PR must not be created, got %d", resp.StatusCode)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	teamName, members := createTeam(t)
+	prID := uniqueName("pr_export")
+	createPR(t, prID, members[0])
+	resp := postJSON(t, "/pullRequest/review", map[string]string{
+		"pull_request_id": prID, "reviewer_id": members[1], "verdict": "APPROVED",
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to submit review: %d", resp.StatusCode)
+	}
+
+	if resp := getJSON(t, "/admin/export"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without admin token, got %d", resp.StatusCode)
+	}
+
+	admin := func(method, url string, body io.Reader) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+url, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp = admin(http.MethodGet, "/admin/export", nil)
+	if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("expected NDJSON export, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	lines := strings.Split(strings.TrimSpace(string(readBody(t, resp))), "\n")
+
+	var header domain.ExportRecord
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil || header.Kind != domain.ExportHeader || header.Version != domain.ExportFormatVersion {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+
+	// записи этой команды — как выгрузка окружения, в котором есть только она
+	own := []string{lines[0]}
+	var exported domain.ExportRecord
+	for _, line := range lines[1:] {
+		var rec domain.ExportRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid export line %q: %v", line, err)
+		}
+		switch {
+		case rec.User != nil && rec.User.TeamName == teamName:
+			if rec.UserCreatedAt == nil {
+				t.Fatalf("user export is missing created_at: %q", line)
+			}
+			own = append(own, line)
+		case rec.Team != nil && rec.Team.TeamName == teamName:
+			own = append(own, line)
+		case rec.PullRequest != nil && rec.PullRequest.PullRequestID == prID:
+			own = append(own, line)
+			exported = rec
+		}
+	}
+	if len(own) != 5 || len(exported.Reviews) != 1 || exported.Reviews[0].Verdict != domain.VerdictApproved {
+		t.Fatalf("export is missing team data or review history: %d records, %+v", len(own), exported)
+	}
+	dump := strings.Join(own, "\n") + "\n"
+
+	resp = admin(http.MethodPost, "/admin/import?on_conflict=fail", strings.NewReader(dump))
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for existing data with on_conflict=fail, got %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Stats map[domain.ExportKind]domain.ImportCounts `json:"stats"`
+	}
+	resp = admin(http.MethodPost, "/admin/import", strings.NewReader(dump))
+	if err := json.Unmarshal(readBody(t, resp), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Stats[domain.ExportUser].Skipped != 2 || result.Stats[domain.ExportPullRequest].Skipped != 1 {
+		t.Fatalf("expected everything to be skipped, got %+v", result.Stats)
+	}
+
+	// резервной команды нет в выгрузке, overwrite должен её снять
+	partnerName, _ := createTeam(t)
+	resp = postJSON(t, "/team/setFallback", map[string]interface{}{
+		"team_name": teamName, "partner_teams": []string{partnerName},
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set fallback: %d", resp.StatusCode)
+	}
+	// лимита нет в выгрузке, overwrite должен снять и его
+	resp = postJSON(t, "/users/setMaxOpenReviews", map[string]interface{}{"user_id": members[0], "max_open_reviews": 5})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set max_open_reviews: %d", resp.StatusCode)
+	}
+
+	// PR под новым id ведёт себя как запись из другого окружения
+	copyID := uniqueName("pr_imported")
+	dump = strings.ReplaceAll(dump, prID, copyID)
+	resp = admin(http.MethodPost, "/admin/import?on_conflict=overwrite", strings.NewReader(dump))
+	if err := json.Unmarshal(readBody(t, resp), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Stats[domain.ExportPullRequest].Created != 1 || result.Stats[domain.ExportUser].Overwritten != 2 {
+		t.Fatalf("unexpected import stats %+v", result.Stats)
+	}
+
+	var team domain.Team
+	if err := json.Unmarshal(readBody(t, getJSON(t, "/team/get?team_name="+teamName)), &team); err != nil {
+		t.Fatal(err)
+	}
+	if len(team.PartnerTeams) != 0 {
+		t.Fatalf("overwrite must clear fallbacks missing from the dump, got %v", team.PartnerTeams)
+	}
+	var user struct {
+		User domain.User `json:"user"`
+	}
+	if err := json.Unmarshal(readBody(t, getJSON(t, "/users/get?user_id="+members[0])), &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.User.MaxOpenReviews != nil {
+		t.Fatalf("overwrite must clear max_open_reviews missing from the dump, got %d", *user.User.MaxOpenReviews)
+	}
+
+	var got struct {
+		PR domain.PullRequest `json:"pr"`
+	}
+	resp = getJSON(t, fmt.Sprintf("/pullRequest/get?pull_request_id=%s", copyID))
+	if err := json.Unmarshal(readBody(t, resp), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.PR.Assignments) != 1 || got.PR.Assignments[0].Verdict == nil || *got.PR.Assignments[0].Verdict != domain.VerdictApproved {
+		t.Fatalf("imported PR lost reviewers or verdicts: %+v", got.PR.Assignments)
+	}
+
+	if resp := admin(http.MethodPost, "/admin/import", strings.NewReader(`{"kind":"header","version":99}`)); resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for unsupported version, got %d", resp.StatusCode)
+	}
+}
+
+func TestCodeHostReviewerSync(t *testing.T) {
+	teamName := uniqueName("team")
+	members := []string{uniqueName("u"), uniqueName("u"), uniqueName("u"), uniqueName("u")}
+	logins := map[string]string{}
+	payload := make([]map[string]interface{}, 0, len(members))
+	for i, m := range members {
+		logins[m] = fmt.Sprintf("login-%d", i)
+		payload = append(payload, map[string]interface{}{"user_id": m, "username": logins[m], "is_active": true})
+	}
+	resp := postJSON(t, "/team/add", map[string]interface{}{"team_name": teamName, "members": payload})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create team: %d", resp.StatusCode)
+	}
+
+	repository := "acme/" + uniqueName("repo")
+	prID := uniqueName("pr")
+	resp = postJSON(t, "/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Sync reviewers",
+		"author_id":         members[0],
+		"repository":        repository,
+		"pr_number":         42,
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &created); err != nil {
+		t.Fatal(err)
+	}
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", created.PR.AssignedReviewers)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/42/requested_reviewers", repository)
+	req, ok := fakeGitHub.waitFor(http.MethodPost, path, 2*time.Second)
+	if !ok {
+		t.Fatal("reviewers were not requested on the code host")
+	}
+	if len(req.Reviewers) != 2 {
+		t.Fatalf("unexpected requested reviewers: %v", req.Reviewers)
+	}
+
+	old := created.PR.AssignedReviewers[0]
+	resp = postJSON(t, "/pullRequest/reassign", map[string]string{
+		"pull_request_id": prID,
+		"old_reviewer_id": old,
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	req, ok = fakeGitHub.waitFor(http.MethodDelete, path, 2*time.Second)
+	if !ok {
+		t.Fatal("replaced reviewer was not removed on the code host")
+	}
+	if len(req.Reviewers) != 1 || req.Reviewers[0] != logins[old] {
+		t.Fatalf("expected %s to be removed, got %v", logins[old], req.Reviewers)
+	}
+}
+
+func TestCodeOwnersReviewerSelection(t *testing.T) {
+	teamName := uniqueName("team")
+	author, docsOwner, goOwner, other := uniqueName("u"), uniqueName("u"), uniqueName("u"), uniqueName("u")
+	resp := postJSON(t, "/team/add", map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": author, "is_active": true},
+			{"user_id": other, "username": other, "is_active": true},
+			{"user_id": docsOwner, "username": docsOwner, "is_active": true},
+			{"user_id": goOwner, "username": goOwner, "is_active": true},
+		},
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create team: %d", resp.StatusCode)
+	}
+
+	resp = postJSON(t, "/codeowners/upload", map[string]string{
+		"team_name": teamName,
+		"content":   fmt.Sprintf("# owners\n*.go @%s\n/docs/ @%s\n", goOwner, docsOwner),
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to upload CODEOWNERS: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	prID := uniqueName("pr")
+	resp = postJSON(t, "/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Touch owned files",
+		"author_id":         author,
+		"changed_files":     []string{"docs/intro.md", "cmd/main.go"},
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+	body := readBody(t, resp)
+	if !bytes.Contains(body, []byte(docsOwner)) || !bytes.Contains(body, []byte(goOwner)) {
+		t.Fatalf("code owners were not assigned: %s", body)
+	}
+
+	resp = postJSON(t, "/codeowners/upload", map[string]string{
+		"team_name": teamName,
+		"content":   "!negated @someone\n",
+	})
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for invalid CODEOWNERS, got %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+}
+
+func TestFallbackReviewerPools(t *testing.T) {
+	teamName, members := createTeam(t)
+	partnerName, partners := createTeam(t)
+	_, pooled := createTeam(t)
+
+	poolName := uniqueName("pool")
+	resp := postJSON(t, "/pool/add", map[string]interface{}{
+		"pool_name": poolName,
+		"members":   []string{pooled[0]},
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create pool: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	resp = postJSON(t, "/team/setFallback", map[string]interface{}{
+		"team_name":     teamName,
+		"partner_teams": []string{partnerName},
+		"pools":         []string{poolName},
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set fallback: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	prID := uniqueName("pr")
+	createPR(t, prID, members[0])
+	resp = getJSON(t, fmt.Sprintf("/users/getReview?user_id=%s", members[1]))
+	body := readBody(t, resp)
+	if !bytes.Contains(body, []byte(`"source":"PARTNER_TEAM"`)) || !bytes.Contains(body, []byte(partnerName)) {
+		t.Fatalf("expected a reviewer drawn from the partner team: %s", body)
+	}
+
+	for _, p := range partners {
+		resp = postJSON(t, "/users/setIsActive", map[string]interface{}{"user_id": p, "is_active": false})
+		readBody(t, resp)
+	}
+
+	prID = uniqueName("pr")
+	createPR(t, prID, members[0])
+	resp = getJSON(t, fmt.Sprintf("/users/getReview?user_id=%s", pooled[0]))
+	body = readBody(t, resp)
+	if !bytes.Contains(body, []byte(prID)) || !bytes.Contains(body, []byte(`"source":"POOL"`)) {
+		t.Fatalf("expected a reviewer drawn from the pool: %s", body)
+	}
+}
+
+func TestReviewCapacity(t *testing.T) {
+	teamName := uniqueName("team")
+	author, busy, free := uniqueName("u"), uniqueName("u"), uniqueName("u")
+	resp := postJSON(t, "/team/add", map[string]interface{}{
+		"team_name":                teamName,
+		"default_max_open_reviews": 5,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": busy, "username": "Busy", "is_active": true, "max_open_reviews": 0},
+			{"user_id": free, "username": "Free", "is_active": true},
+		},
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create team: %d", resp.StatusCode)
+	}
+
+	prID := uniqueName("pr")
+	createPR(t, prID, author)
+
+	resp = getJSON(t, fmt.Sprintf("/users/load?team_name=%s", teamName))
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var load struct {
+		Teams map[string][]struct {
+			UserID         string `json:"user_id"`
+			OpenReviews    int    `json:"open_reviews"`
+			MaxOpenReviews *int   `json:"max_open_reviews"`
+		} `json:"teams"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &load); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range load.Teams[teamName] {
+		switch l.UserID {
+		case busy:
+			if l.OpenReviews != 0 {
+				t.Fatalf("over-capacity reviewer was assigned: %+v", l)
+			}
+		case free:
+			if l.OpenReviews != 1 || l.MaxOpenReviews == nil || *l.MaxOpenReviews != 5 {
+				t.Fatalf("unexpected load for free reviewer: %+v", l)
+			}
+		}
+	}
+}
+
+// TestUnavailabilityWithoutCandidate проверяет, что период отсутствия помечается обработанным,
+// даже если ревью некому передать, и не разбирается заново при каждом запуске.
+func TestUnavailabilityWithoutCandidate(t *testing.T) {
+	_, members := createTeam(t)
+	prID := uniqueName("pr")
+	createPR(t, prID, members[0])
+
+	// период начнётся позже, чтобы его не обработал фоновый наблюдатель тестового сервера
+	now := time.Now().UTC()
+	resp := postJSON(t, "/users/addUnavailability", map[string]interface{}{
+		"user_id":   members[1],
+		"starts_at": now.Add(time.Hour),
+		"ends_at":   now.Add(48 * time.Hour),
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to add unavailability: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	later := services.NewService(repo, services.WithClock(func() time.Time { return now.Add(2 * time.Hour) }))
+	results, err := later.ReassignUnavailable(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := results[members[1]]; !strings.Contains(res, prID) || !strings.Contains(res, "NO_CANDIDATE") {
+		t.Fatalf("expected NO_CANDIDATE for %s, got %q", prID, res)
+	}
+
+	results, err = later.ReassignUnavailable(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, ok := results[members[1]]; ok {
+		t.Fatalf("expected the window to be marked as handled, got %q", res)
+	}
+}
+
+func TestUnavailabilityReassignsOpenReviews(t *testing.T) {
+	teamName, members := createTeamWithMembers(t, 4)
+
+	prID := uniqueName("pr")
+	resp := postJSON(t, "/pullRequest/create", map[string]string{
+		"pull_request_id":   prID,
+		"pull_request_name": "Before vacation",
+		"author_id":         members[0],
+	})
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &created); err != nil || len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("unexpected create response: %v %v", created, err)
+	}
+	away := created.PR.AssignedReviewers[0]
+
+	now := time.Now().UTC()
+	resp = postJSON(t, "/users/addUnavailability", map[string]interface{}{
+		"user_id":   away,
+		"kind":      "VACATION",
+		"starts_at": now.Add(-time.Minute),
+		"ends_at":   now.Add(24 * time.Hour),
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to add unavailability: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		resp = getJSON(t, fmt.Sprintf("/users/getReview?user_id=%s", away))
+		if !bytes.Contains(readBody(t, resp), []byte(prID)) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("open review of unavailable user was not reassigned")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	resp = getJSON(t, fmt.Sprintf("/team/get?team_name=%s", teamName))
+	var team struct {
+		Members []struct {
+			UserID      string `json:"user_id"`
+			IsAvailable bool   `json:"is_available"`
+		} `json:"members"`
+	}
+	if err := json.Unmarshal(readBody(t, resp), &team); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range team.Members {
+		if (m.UserID == away) == m.IsAvailable {
+			t.Fatalf("unexpected availability for %s: %v", m.UserID, m.IsAvailable)
+		}
+	}
+}
+
+func TestCreateTeamWithoutSchedule(t *testing.T) {
+	teamName := uniqueName("team")
+	plain, scheduled := uniqueName("u"), uniqueName("u")
+	resp := postJSON(t, "/team/add", map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": plain, "username": "Plain", "is_active": true},
+			{"user_id": scheduled, "username": "Scheduled", "is_active": true, "timezone": "Europe/Moscow",
+				"working_hours": map[string]interface{}{"start": "09:00", "end": "18:00"}},
+		},
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create team with a member without schedule: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	var team struct {
+		Members []map[string]json.RawMessage `json:"members"`
+	}
+	if err := json.Unmarshal(readBody(t, getJSON(t, "/team/get?team_name="+teamName)), &team); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range team.Members {
+		switch string(m["user_id"]) {
+		case `"` + plain + `"`:
+			// отсутствие расписания и часового пояса не подменяется значениями по умолчанию
+			if _, ok := m["working_hours"]; ok {
+				t.Fatalf("member without schedule got working hours: %s", m["working_hours"])
+			}
+			if _, ok := m["timezone"]; ok {
+				t.Fatalf("member without timezone got one: %s", m["timezone"])
+			}
+		case `"` + scheduled + `"`:
+			if !bytes.Contains(m["working_hours"], []byte(`"start":"09:00"`)) || string(m["timezone"]) != `"Europe/Moscow"` {
+				t.Fatalf("schedule was not saved: %s %s", m["timezone"], m["working_hours"])
+			}
+		}
+	}
+}
+
+func TestWorkingHoursAssignment(t *testing.T) {
+	now := time.Now().UTC()
+	offDay := (int(now.Weekday())+3)%7 + 1 // ни сегодня, ни вчера
+	asleep := map[string]interface{}{"start": "00:00", "end": "23:59", "days": []int{offDay}}
+
+	teamName := uniqueName("team")
+	author, sleeper1, sleeper2, awake := uniqueName("u"), uniqueName("u"), uniqueName("u"), uniqueName("u")
+	resp := postJSON(t, "/team/add", map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": sleeper1, "username": "Sleeper1", "is_active": true, "timezone": "Asia/Tokyo", "working_hours": asleep},
+			{"user_id": sleeper2, "username": "Sleeper2", "is_active": true, "timezone": "America/New_York", "working_hours": asleep},
+			// без расписания пользователь доступен в любое время, в том числе в 23:59
+			{"user_id": awake, "username": "Awake", "is_active": true},
+		},
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create team: %d", resp.StatusCode)
+	}
+
+	prID := uniqueName("pr")
+	resp = postJSON(t, "/pullRequest/create", map[string]string{
+		"pull_request_id":   prID,
+		"pull_request_name": "Late night fix",
+		"author_id":         author,
+		"assignment_mode":   "WORKING_HOURS",
+	})
+	if resp.StatusCode != 201 {
+		t.Fatalf("failed to create PR: %d", resp.StatusCode)
+	}
+	body := readBody(t, resp)
+	if !bytes.Contains(body, []byte(awake)) {
+		t.Fatalf("reviewer within working hours was not preferred: %s", body)
+	}
+
+	resp = postJSON(t, "/team/add", map[string]interface{}{
+		"team_name": uniqueName("team"),
+		"members": []map[string]interface{}{
+			{"user_id": uniqueName("u"), "username": "Lost", "is_active": true, "timezone": "Mars/Olympus"},
+		},
+	})
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400 for unknown timezone, got %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+}
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event domain.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) count(eventType domain.EventType, prID string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, e := range p.events {
+		if e.Type == eventType && e.PullRequestID == prID {
+			n++
+		}
+	}
+	return n
+}
+
+func TestReviewSLAEscalation(t *testing.T) {
+	teamName, members := createTeamWithMembers(t, 4)
+
+	resp := postJSON(t, "/team/setReviewSLA", map[string]interface{}{
+		"team_name":  teamName,
+		"review_sla": map[string]string{"reminder_after": "1h", "reassign_after": "2h"},
+	})
+	if resp.StatusCode != 200 {
+		t.Fatalf("failed to set review SLA: %d", resp.StatusCode)
+	}
+	readBody(t, resp)
+
+	prID := uniqueName("pr")
+	createPR(t, prID, members[0])
+
+	events := &recordingPublisher{}
+	offset := 90 * time.Minute
+	clock := func() time.Time { return time.Now().UTC().Add(offset) }
+	svc := services.NewService(repo, services.WithClock(clock), services.WithEventPublisher(events))
+
+	if _, err := svc.CheckReviewSLA(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := events.count(domain.EventReviewReminder, prID); n != 2 {
+		t.Fatalf("expected 2 reminders, got %d", n)
+	}
+
+	if _, err := svc.CheckReviewSLA(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := events.count(domain.EventReviewReminder, prID); n != 2 {
+		t.Fatalf("reminders must not repeat, got %d", n)
+	}
+
+	offset = 3 * time.Hour
+	if _, err := svc.CheckReviewSLA(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := events.count(domain.EventReviewEscalated, prID); n != 1 {
+		t.Fatalf("expected 1 escalation (one spare reviewer), got %d", n)
+	}
+
+	// напомненные назначения и неудачное переназначение не выбираются на каждом запуске
+	overdue := func() []domain.OverdueAssignment {
+		all, err := repo.ListOverdueAssignments(context.Background(), clock())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var own []domain.OverdueAssignment
+		for _, a := range all {
+			if a.PullRequestID == prID {
+				own = append(own, a)
+			}
+		}
+		return own
+	}
+	if rows := overdue(); len(rows) != 0 {
+		t.Fatalf("expected handled assignments to be skipped, got %+v", rows)
+	}
+	offset = 5*time.Hour + time.Minute
+	retried := false
+	for _, a := range overdue() {
+		retried = retried || a.RemindedAt != nil
+	}
+	if !retried {
+		t.Fatal("expected the failed reassignment to be retried after the reassign interval")
+	}
+}
+
+func TestMigrationStatus(t *testing.T) {
+	if resp := getJSON(t, "/admin/migrations"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without admin token, got %d", resp.StatusCode)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		path := "/admin/migrations"
+		if method == http.MethodPost {
+			path += "/up"
+		}
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf("%s %s: expected 200, got %d", method, path, resp.StatusCode)
+		}
+
+		var status domain.MigrationStatus
+		if err := json.Unmarshal(readBody(t, resp), &status); err != nil {
+			t.Fatal(err)
+		}
+		// TestMain уже применил все миграции
+		if !status.UpToDate() || status.Latest == 0 {
+			t.Fatalf("%s %s: expected up to date schema, got %+v", method, path, status)
+		}
+	}
+}
+
+func TestMigrateDownAndGoto(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() {
+		if err := repo.Migrate(); err != nil {
+			t.Fatalf("failed to restore schema: %v", err)
+		}
+	})
+
+	status, err := repo.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := status.Latest
+
+	if err := repo.MigrateDown(1); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	if status, err = repo.MigrationStatus(ctx); err != nil || status.Version != latest-1 || status.UpToDate() {
+		t.Fatalf("expected version %d after rollback, got %+v (%v)", latest-1, status, err)
+	}
+
+	if err := repo.MigrateTo(latest); err != nil {
+		t.Fatalf("failed to migrate to %d: %v", latest, err)
+	}
+	if status, err = repo.MigrationStatus(ctx); err != nil || !status.UpToDate() {
+		t.Fatalf("expected up to date schema, got %+v (%v)", status, err)
+	}
+
+	// force не выполняет миграции, а только переписывает версию
+	if err := repo.ForceMigrationVersion(int(latest)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MigrateDown(0); err == nil {
+		t.Fatal("expected error for zero steps")
+	}
+}
@@ -0,0 +1,399 @@
+package e2e
+
+import (
+	"PRService/internal/adapters/graphql"
+	"PRService/internal/domain"
+	"PRService/internal/ports"
+	"PRService/internal/services"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string            `json:"message"`
+		Extensions map[string]string `json:"extensions"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, query string, variables map[string]interface{}, data interface{}) graphqlResponse {
+	t.Helper()
+	resp := postJSON(t, "/graphql", map[string]interface{}{"query": query, "variables": variables})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected graphql status %d", resp.StatusCode)
+	}
+	var out graphqlResponse
+	if err := json.Unmarshal(readBody(t, resp), &out); err != nil {
+		t.Fatal(err)
+	}
+	if data != nil && len(out.Data) > 0 && string(out.Data) != "null" {
+		if err := json.Unmarshal(out.Data, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out
+}
+
+func TestGraphQL(t *testing.T) {
+	teamName := uniqueName("team")
+	author, reviewer1, reviewer2, reviewer3 := uniqueName("u"), uniqueName("u"), uniqueName("u"), uniqueName("u")
+
+	var created struct {
+		CreateTeam struct {
+			TeamName string
+			Members  []struct{ UserID string }
+		}
+	}
+	out := postGraphQL(t, `mutation($team: TeamInput!) { createTeam(team: $team) { teamName members { userId } } }`,
+		map[string]interface{}{"team": map[string]interface{}{
+			"teamName": teamName,
+			"members": []map[string]interface{}{
+				{"userId": author, "username": "Alice", "isActive": true},
+				{"userId": reviewer1, "username": "Bob", "isActive": true},
+				{"userId": reviewer2, "username": "Carol", "isActive": true},
+				{"userId": reviewer3, "username": "Dave", "isActive": true},
+			},
+		}}, &created)
+	if len(out.Errors) > 0 || created.CreateTeam.TeamName != teamName || len(created.CreateTeam.Members) != 4 {
+		t.Fatalf("unexpected createTeam result %+v, %+v", created, out.Errors)
+	}
+
+	out = postGraphQL(t, `mutation($team: TeamInput!) { createTeam(team: $team) { teamName } }`,
+		map[string]interface{}{"team": map[string]interface{}{"teamName": teamName, "members": []interface{}{}}}, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "TEAM_EXISTS" {
+		t.Fatalf("expected TEAM_EXISTS, got %+v", out.Errors)
+	}
+
+	prID := uniqueName("pr")
+	var pr struct {
+		CreatePullRequest struct {
+			Version   int
+			Reviewers []struct{ UserID string }
+		}
+	}
+	out = postGraphQL(t, `mutation($id: ID!, $author: ID!) {
+		createPullRequest(pullRequestId: $id, pullRequestName: "GraphQL PR", authorId: $author) { version reviewers { userId } }
+	}`, map[string]interface{}{"id": prID, "author": author}, &pr)
+	if len(out.Errors) > 0 || len(pr.CreatePullRequest.Reviewers) != 2 {
+		t.Fatalf("unexpected createPullRequest result %+v, %+v", pr, out.Errors)
+	}
+
+	// дашборд: команда, участники, их открытые ревью и авторы PR одним запросом
+	var dashboard struct {
+		Team struct {
+			Members []struct {
+				UserID      string
+				OpenReviews []struct {
+					PullRequestID string
+					Status        string
+					Author        struct{ Username string }
+				}
+			}
+		}
+	}
+	out = postGraphQL(t, `query($team: String!) {
+		team(teamName: $team) { members { userId openReviews { pullRequestId status author { username } } } }
+	}`, map[string]interface{}{"team": teamName}, &dashboard)
+	if len(out.Errors) > 0 || len(dashboard.Team.Members) != 4 {
+		t.Fatalf("unexpected dashboard %+v, %+v", dashboard, out.Errors)
+	}
+	reviews := 0
+	for _, m := range dashboard.Team.Members {
+		for _, r := range m.OpenReviews {
+			if r.PullRequestID != prID || r.Status != "OPEN" || r.Author.Username != "Alice" {
+				t.Fatalf("unexpected open review %+v of %s", r, m.UserID)
+			}
+			reviews++
+		}
+	}
+	if reviews != 2 {
+		t.Fatalf("expected 2 open reviews, got %d", reviews)
+	}
+
+	old := pr.CreatePullRequest.Reviewers[0].UserID
+	out = postGraphQL(t, `mutation($id: ID!, $old: ID!) { reassignReviewer(pullRequestId: $id, oldReviewerId: $old, expectedVersion: 100) { replacedBy { userId } } }`,
+		map[string]interface{}{"id": prID, "old": old}, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "PR_VERSION_MISMATCH" {
+		t.Fatalf("expected PR_VERSION_MISMATCH, got %+v", out.Errors)
+	}
+
+	var reassigned struct {
+		ReassignReviewer struct {
+			PullRequest struct{ Reviewers []struct{ UserID string } }
+			ReplacedBy  struct{ UserID string }
+		}
+	}
+	out = postGraphQL(t, `mutation($id: ID!, $old: ID!) {
+		reassignReviewer(pullRequestId: $id, oldReviewerId: $old) { pullRequest { reviewers { userId } } replacedBy { userId } }
+	}`, map[string]interface{}{"id": prID, "old": old}, &reassigned)
+	if len(out.Errors) > 0 || reassigned.ReassignReviewer.ReplacedBy.UserID == "" || reassigned.ReassignReviewer.ReplacedBy.UserID == old {
+		t.Fatalf("unexpected reassign result %+v, %+v", reassigned, out.Errors)
+	}
+	for _, r := range reassigned.ReassignReviewer.PullRequest.Reviewers {
+		if r.UserID == old {
+			t.Fatalf("old reviewer %s is still assigned", old)
+		}
+	}
+
+	var merged struct {
+		MergePullRequest struct {
+			Status   string
+			MergedAt *string
+		}
+	}
+	out = postGraphQL(t, `mutation($id: ID!) { mergePullRequest(pullRequestId: $id) { status mergedAt } }`,
+		map[string]interface{}{"id": prID}, &merged)
+	if len(out.Errors) > 0 || merged.MergePullRequest.Status != "MERGED" || merged.MergePullRequest.MergedAt == nil {
+		t.Fatalf("unexpected merge result %+v, %+v", merged, out.Errors)
+	}
+
+	var stats struct {
+		Stats struct {
+			Teams []struct {
+				TeamName           string
+				MergedPullRequests int
+			}
+		}
+		User        *struct{ Username string }
+		PullRequest *struct{ PullRequestID string }
+	}
+	out = postGraphQL(t, `query($team: String!, $missing: ID!) {
+		stats(teamName: $team) { teams { teamName mergedPullRequests } }
+		user(userId: $missing) { username }
+		pullRequest(pullRequestId: $missing) { pullRequestId }
+	}`, map[string]interface{}{"team": teamName, "missing": uniqueName("missing")}, &stats)
+	if len(out.Errors) > 0 || len(stats.Stats.Teams) != 1 || stats.Stats.Teams[0].MergedPullRequests != 1 {
+		t.Fatalf("unexpected stats %+v, %+v", stats, out.Errors)
+	}
+	if stats.User != nil || stats.PullRequest != nil {
+		t.Fatalf("missing objects must resolve to null, got %+v", stats)
+	}
+}
+
+func TestGraphQLTeamsPagination(t *testing.T) {
+	createTeam(t)
+	createTeam(t)
+
+	type teamPage struct {
+		Teams struct {
+			Teams      []struct{ TeamName string }
+			NextCursor *string
+		}
+	}
+	var first teamPage
+	out := postGraphQL(t, `{ teams(first: 1) { teams { teamName } nextCursor } }`, nil, &first)
+	if len(out.Errors) > 0 || len(first.Teams.Teams) != 1 || first.Teams.NextCursor == nil {
+		t.Fatalf("unexpected first page %+v, %+v", first, out.Errors)
+	}
+
+	var next teamPage
+	out = postGraphQL(t, `query($after: String) { teams(first: 1, after: $after) { teams { teamName } } }`,
+		map[string]interface{}{"after": *first.Teams.NextCursor}, &next)
+	if len(out.Errors) > 0 || len(next.Teams.Teams) != 1 || next.Teams.Teams[0].TeamName <= first.Teams.Teams[0].TeamName {
+		t.Fatalf("unexpected next page %+v after %+v, %+v", next, first, out.Errors)
+	}
+
+	out = postGraphQL(t, `{ teams(after: "!") { teams { teamName } } }`, nil, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "INVALID_CURSOR" {
+		t.Fatalf("expected INVALID_CURSOR, got %+v", out.Errors)
+	}
+}
+
+func TestGraphQLTeamSettingsMutations(t *testing.T) {
+	teamName, members := createTeam(t)
+	partner, _ := createTeam(t)
+
+	var limits struct {
+		SetTeamMaxOpenReviews struct{ MaxOpenReviews *int }
+		SetTeamReviewerLimits struct{ MinReviewers, MaxReviewers *int }
+	}
+	out := postGraphQL(t, `mutation($team: String!) {
+		setTeamMaxOpenReviews(teamName: $team, maxOpenReviews: 3) { maxOpenReviews }
+		setTeamReviewerLimits(teamName: $team, minReviewers: 1, maxReviewers: 2) { minReviewers maxReviewers }
+	}`, map[string]interface{}{"team": teamName}, &limits)
+	if len(out.Errors) > 0 || limits.SetTeamMaxOpenReviews.MaxOpenReviews == nil || *limits.SetTeamMaxOpenReviews.MaxOpenReviews != 3 ||
+		limits.SetTeamReviewerLimits.MaxReviewers == nil || *limits.SetTeamReviewerLimits.MaxReviewers != 2 {
+		t.Fatalf("unexpected limits result %+v, %+v", limits, out.Errors)
+	}
+
+	var sla struct {
+		SetTeamReviewSLA struct {
+			ReviewSLA *struct{ ReminderAfter string }
+		}
+	}
+	out = postGraphQL(t, `mutation($team: String!) {
+		setTeamReviewSLA(teamName: $team, sla: {reminderAfter: "2h", reassignAfter: "8h"}) { reviewSla { reminderAfter } }
+	}`, map[string]interface{}{"team": teamName}, &sla)
+	if len(out.Errors) > 0 || sla.SetTeamReviewSLA.ReviewSLA == nil || sla.SetTeamReviewSLA.ReviewSLA.ReminderAfter != "2h0m0s" {
+		t.Fatalf("unexpected SLA result %+v, %+v", sla, out.Errors)
+	}
+	out = postGraphQL(t, `mutation($team: String!) { setTeamReviewSLA(teamName: $team, sla: {reminderAfter: "soon"}) { teamName } }`,
+		map[string]interface{}{"team": teamName}, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "INVALID_SLA" {
+		t.Fatalf("expected INVALID_SLA, got %+v", out.Errors)
+	}
+
+	var fallback struct {
+		SetTeamFallback struct{ PartnerTeams []string }
+	}
+	out = postGraphQL(t, `mutation($team: String!, $partner: String!) {
+		setTeamFallback(teamName: $team, partnerTeams: [$partner], pools: []) { partnerTeams }
+	}`, map[string]interface{}{"team": teamName, "partner": partner}, &fallback)
+	if len(out.Errors) > 0 || len(fallback.SetTeamFallback.PartnerTeams) != 1 || fallback.SetTeamFallback.PartnerTeams[0] != partner {
+		t.Fatalf("unexpected fallback result %+v, %+v", fallback, out.Errors)
+	}
+
+	var codeOwners struct {
+		UploadCodeOwners []struct {
+			Pattern string
+			Owners  []string
+		}
+	}
+	out = postGraphQL(t, `mutation($team: String!, $content: String!) {
+		uploadCodeOwners(teamName: $team, content: $content) { pattern owners }
+	}`, map[string]interface{}{"team": teamName, "content": "/api/ " + members[1] + "\n"}, &codeOwners)
+	if len(out.Errors) > 0 || len(codeOwners.UploadCodeOwners) != 1 || codeOwners.UploadCodeOwners[0].Pattern != "/api/" {
+		t.Fatalf("unexpected codeowners result %+v, %+v", codeOwners, out.Errors)
+	}
+
+	var added struct {
+		AddUnavailability struct {
+			ID   string
+			Kind string
+			User struct{ UserID string }
+		}
+	}
+	out = postGraphQL(t, `mutation($user: ID!) {
+		addUnavailability(userId: $user, kind: VACATION, startsAt: "2030-01-01T00:00:00Z", endsAt: "2030-01-10T00:00:00Z") { id kind user { userId } }
+	}`, map[string]interface{}{"user": members[1]}, &added)
+	if len(out.Errors) > 0 || added.AddUnavailability.Kind != "VACATION" || added.AddUnavailability.User.UserID != members[1] {
+		t.Fatalf("unexpected addUnavailability result %+v, %+v", added, out.Errors)
+	}
+	out = postGraphQL(t, `mutation($user: ID!) {
+		addUnavailability(userId: $user, startsAt: "2030-01-10T00:00:00Z", endsAt: "2030-01-01T00:00:00Z") { id }
+	}`, map[string]interface{}{"user": members[1]}, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "INVALID_UNAVAILABILITY" {
+		t.Fatalf("expected INVALID_UNAVAILABILITY, got %+v", out.Errors)
+	}
+
+	var removed struct{ RemoveUnavailability bool }
+	out = postGraphQL(t, `mutation($id: ID!) { removeUnavailability(id: $id) }`,
+		map[string]interface{}{"id": added.AddUnavailability.ID}, &removed)
+	if len(out.Errors) > 0 || !removed.RemoveUnavailability {
+		t.Fatalf("unexpected removeUnavailability result %+v, %+v", removed, out.Errors)
+	}
+	out = postGraphQL(t, `mutation($id: ID!) { removeUnavailability(id: $id) }`,
+		map[string]interface{}{"id": added.AddUnavailability.ID}, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND, got %+v", out.Errors)
+	}
+}
+
+// countingRepo считает обращения резолверов к репозиторию, чтобы проверить, что даталоадеры
+// собирают вложенные поля в один запрос на уровень, а не по запросу на объект.
+type countingRepo struct {
+	ports.Repository
+	usersByIDs, teamsByNames, usersByTeams, prsByIDs, openPRsByReviewers atomic.Int32
+	single                                                               atomic.Int32
+}
+
+func (r *countingRepo) GetUsersByIDs(ctx context.Context, ids []string) ([]domain.User, error) {
+	r.usersByIDs.Add(1)
+	return r.Repository.GetUsersByIDs(ctx, ids)
+}
+
+func (r *countingRepo) GetTeamsByNames(ctx context.Context, names []string) ([]domain.Team, error) {
+	r.teamsByNames.Add(1)
+	return r.Repository.GetTeamsByNames(ctx, names)
+}
+
+func (r *countingRepo) ListUsersByTeams(ctx context.Context, names []string) ([]domain.User, error) {
+	r.usersByTeams.Add(1)
+	return r.Repository.ListUsersByTeams(ctx, names)
+}
+
+func (r *countingRepo) GetPRsByIDs(ctx context.Context, ids []string) ([]domain.PullRequest, error) {
+	r.prsByIDs.Add(1)
+	return r.Repository.GetPRsByIDs(ctx, ids)
+}
+
+func (r *countingRepo) ListOpenPRsByReviewers(ctx context.Context, ids []string) ([]domain.PullRequest, error) {
+	r.openPRsByReviewers.Add(1)
+	return r.Repository.ListOpenPRsByReviewers(ctx, ids)
+}
+
+func (r *countingRepo) GetUser(ctx context.Context, userID string) (domain.User, error) {
+	r.single.Add(1)
+	return r.Repository.GetUser(ctx, userID)
+}
+
+func (r *countingRepo) GetTeam(ctx context.Context, teamName string) (domain.Team, error) {
+	r.single.Add(1)
+	return r.Repository.GetTeam(ctx, teamName)
+}
+
+func (r *countingRepo) GetPR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	r.single.Add(1)
+	return r.Repository.GetPR(ctx, prID)
+}
+
+func TestGraphQLDataloaderBatching(t *testing.T) {
+	teamName, members := createTeamWithMembers(t, 4)
+	for _, author := range members {
+		createPR(t, uniqueName("pr"), author)
+	}
+
+	counting := &countingRepo{Repository: repo}
+	gqlServer := httptest.NewServer(graphql.NewHandler(services.NewService(counting), counting))
+	defer gqlServer.Close()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"query":     `query($team: String!) { team(teamName: $team) { members { userId openReviews { author { username } } } } }`,
+		"variables": map[string]interface{}{"team": teamName},
+	})
+	resp, err := http.Post(gqlServer.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out graphqlResponse
+	if err := json.Unmarshal(readBody(t, resp), &out); err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		Team struct {
+			Members []struct {
+				OpenReviews []struct{ Author struct{ Username string } }
+			}
+		}
+	}
+	if len(out.Errors) > 0 || json.Unmarshal(out.Data, &data) != nil {
+		t.Fatalf("unexpected response %s, %+v", out.Data, out.Errors)
+	}
+	reviews := 0
+	for _, m := range data.Team.Members {
+		reviews += len(m.OpenReviews)
+	}
+	if len(data.Team.Members) != len(members) || reviews < len(members) {
+		t.Fatalf("expected %d members with open reviews, got %+v", len(members), data.Team)
+	}
+
+	for name, got := range map[string]int32{
+		"GetTeamsByNames":        counting.teamsByNames.Load(),
+		"ListUsersByTeams":       counting.usersByTeams.Load(),
+		"ListOpenPRsByReviewers": counting.openPRsByReviewers.Load(),
+		"GetUsersByIDs":          counting.usersByIDs.Load(),
+	} {
+		if got != 1 {
+			t.Errorf("expected one batched %s call, got %d", name, got)
+		}
+	}
+	if n := counting.prsByIDs.Load() + counting.single.Load(); n != 0 {
+		t.Errorf("expected no per-object repository calls, got %d", n)
+	}
+}
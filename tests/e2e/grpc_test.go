@@ -0,0 +1,183 @@
+package e2e
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"PRService/internal/adapters/events"
+	grpcadapter "PRService/internal/adapters/grpc"
+	httphandler "PRService/internal/adapters/http"
+	"PRService/internal/domain"
+	"PRService/internal/services"
+	pb "PRService/pkg/pb/prservice/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCClient поднимает gRPC-сервер в памяти поверх отдельного сервиса с брокером событий.
+func newGRPCClient(t *testing.T) pb.PRServiceClient {
+	t.Helper()
+	broker := events.NewBroker(16)
+	svc := services.NewService(repo, services.WithEventPublisher(broker))
+
+	s := grpc.NewServer()
+	pb.RegisterPRServiceServer(s, &grpcadapter.Server{S: svc, Events: broker})
+	return dialGRPC(t, s)
+}
+
+// dialGRPC запускает s в памяти и возвращает клиента к нему.
+func dialGRPC(t *testing.T, s *grpc.Server) pb.PRServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial grpc: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewPRServiceClient(conn)
+}
+
+func TestGRPC(t *testing.T) {
+	ctx := context.Background()
+	c := newGRPCClient(t)
+
+	teamName := uniqueName("team")
+	author, reviewer1, reviewer2, reviewer3 := uniqueName("u"), uniqueName("u"), uniqueName("u"), uniqueName("u")
+	_, err := c.CreateTeam(ctx, &pb.CreateTeamRequest{Team: &pb.Team{
+		TeamName: teamName,
+		Members: []*pb.TeamMember{
+			{UserId: author, Username: "Alice", IsActive: true},
+			{UserId: reviewer1, Username: "Bob", IsActive: true},
+			{UserId: reviewer2, Username: "Carol", IsActive: true},
+			{UserId: reviewer3, Username: "Dave", IsActive: true},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("failed to create team: %v", err)
+	}
+	if _, err := c.CreateTeam(ctx, &pb.CreateTeamRequest{Team: &pb.Team{TeamName: teamName}}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", err)
+	}
+	if _, err := c.GetTeam(ctx, &pb.GetTeamRequest{TeamName: uniqueName("missing")}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	if team, err := c.GetTeam(ctx, &pb.GetTeamRequest{TeamName: teamName}); err != nil || len(team.Members) != 4 {
+		t.Fatalf("unexpected team %v, %v", team, err)
+	}
+
+	users, err := c.ListUsers(ctx, &pb.ListUsersRequest{TeamName: teamName, Limit: 2})
+	if err != nil || len(users.Users) != 2 || users.NextCursor == "" {
+		t.Fatalf("unexpected users page %v, %v", users, err)
+	}
+	if _, err := c.ListUsers(ctx, &pb.ListUsersRequest{Cursor: "broken"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	stream, err := c.WatchAssignmentEvents(watchCtx, &pb.WatchAssignmentEventsRequest{TeamName: teamName})
+	if err != nil {
+		t.Fatalf("failed to watch events: %v", err)
+	}
+	// сервер отправляет заголовки после подписки
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("failed to read stream header: %v", err)
+	}
+
+	prID := uniqueName("pr")
+	pr, err := c.CreatePullRequest(ctx, &pb.CreatePullRequestRequest{PullRequestId: prID, PullRequestName: "gRPC PR", AuthorId: author})
+	if err != nil || len(pr.AssignedReviewers) != 2 || pr.Status != pb.PullRequestStatus_PULL_REQUEST_STATUS_OPEN {
+		t.Fatalf("unexpected PR %v, %v", pr, err)
+	}
+	for range pr.AssignedReviewers {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("failed to receive event: %v", err)
+		}
+		if event.Type != pb.AssignmentEventType_ASSIGNMENT_EVENT_TYPE_ASSIGNED || event.PullRequestId != prID {
+			t.Fatalf("unexpected event %v", event)
+		}
+	}
+
+	old := pr.AssignedReviewers[0]
+	if _, err := c.ReassignReviewer(ctx, &pb.ReassignReviewerRequest{PullRequestId: prID, OldReviewerId: old, ExpectedVersion: pr.Version + 10}); status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted on version mismatch, got %v", err)
+	}
+	reassigned, err := c.ReassignReviewer(ctx, &pb.ReassignReviewerRequest{PullRequestId: prID, OldReviewerId: old})
+	if err != nil || reassigned.ReplacedBy == "" || reassigned.ReplacedBy == old {
+		t.Fatalf("unexpected reassign result %v, %v", reassigned, err)
+	}
+	event, err := stream.Recv()
+	if err != nil || event.Type != pb.AssignmentEventType_ASSIGNMENT_EVENT_TYPE_REASSIGNED ||
+		event.UserId != old || event.ReplacedBy != reassigned.ReplacedBy {
+		t.Fatalf("unexpected reassign event %v, %v", event, err)
+	}
+
+	merged, err := c.MergePullRequest(ctx, &pb.MergePullRequestRequest{PullRequestId: prID})
+	if err != nil || merged.Status != pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED || merged.MergedAt == nil {
+		t.Fatalf("unexpected merged PR %v, %v", merged, err)
+	}
+	if _, err := c.ReassignReviewer(ctx, &pb.ReassignReviewerRequest{PullRequestId: prID, OldReviewerId: reassigned.ReplacedBy}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition on merged PR, got %v", err)
+	}
+
+	prs, err := c.ListPullRequests(ctx, &pb.ListPullRequestsRequest{AuthorId: author, Status: pb.PullRequestStatus_PULL_REQUEST_STATUS_MERGED})
+	if err != nil || len(prs.PullRequests) != 1 || prs.PullRequests[0].PullRequestId != prID {
+		t.Fatalf("unexpected PR list %v, %v", prs, err)
+	}
+
+	stats, err := c.GetStats(ctx, &pb.GetStatsRequest{TeamName: teamName})
+	if err != nil || stats.Teams[teamName].GetMergedPullRequests() != 1 {
+		t.Fatalf("unexpected stats %v, %v", stats, err)
+	}
+
+	deactivated, err := c.DeactivateUsers(ctx, &pb.DeactivateUsersRequest{UserIds: []string{author}})
+	if err != nil || len(deactivated.Results) != 1 {
+		t.Fatalf("unexpected deactivate result %v, %v", deactivated, err)
+	}
+	if user, err := c.GetUser(ctx, &pb.GetUserRequest{UserId: author}); err != nil || user.IsActive {
+		t.Fatalf("expected inactive user, got %v, %v", user, err)
+	}
+	if _, err := c.GetUser(ctx, &pb.GetUserRequest{UserId: uniqueName("missing")}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGRPCInterceptors(t *testing.T) {
+	ctx := context.Background()
+	limits := httphandler.NewMemoryRateLimitStore()
+	perIP := domain.RateLimit{PerSecond: 0.1, Burst: 1}
+
+	// без сервиса любой вызов паникует, и recovery должен вернуть Internal, не уронив сервер
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcadapter.RecoveryUnary(), grpcadapter.RateLimitUnary(limits, domain.RateLimit{}, perIP)),
+		grpc.ChainStreamInterceptor(grpcadapter.RecoveryStream(), grpcadapter.RateLimitStream(limits, domain.RateLimit{}, perIP)),
+	)
+	pb.RegisterPRServiceServer(s, &grpcadapter.Server{})
+	c := dialGRPC(t, s)
+
+	if _, err := c.GetTeam(ctx, &pb.GetTeamRequest{TeamName: "any"}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal after panic, got %v", err)
+	}
+	var header metadata.MD
+	_, err := c.GetTeam(ctx, &pb.GetTeamRequest{TeamName: "any"}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted || status.Convert(err).Message() != "RATE_LIMITED" {
+		t.Fatalf("expected RATE_LIMITED after burst, got %v", err)
+	}
+	if retry := header.Get("retry-after"); len(retry) != 1 || retry[0] != "10" {
+		t.Fatalf("expected retry-after 10, got %v", retry)
+	}
+}
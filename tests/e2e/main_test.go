@@ -1,54 +1,133 @@
 package e2e
 
 import (
+	"context"
+	"encoding/json"
 	"log"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"PRService/internal/adapters/github"
+	"PRService/internal/adapters/graphql"
 	httphandler "PRService/internal/adapters/http"
 	"PRService/internal/adapters/postgres"
+	"PRService/internal/domain"
 	"PRService/internal/services"
-
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 )
 
 var server *httptest.Server
 
+// repo доступен тестам, которым нужен отдельный экземпляр сервиса (например, с подменённым временем).
+var repo *postgres.Repo
+
+var fakeGitHub = &fakeCodeHost{}
+
+const adminToken = "test-admin-token"
+
+// fakeCodeHost запоминает запросы к requested_reviewers, как их увидел бы GitHub.
+// Репозитории с префиксом rejected- он отклоняет с 422.
+type fakeCodeHost struct {
+	mu       sync.Mutex
+	requests []codeHostRequest
+}
+
+type codeHostRequest struct {
+	Method    string
+	Path      string
+	Reviewers []string
+}
+
+func (f *fakeCodeHost) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Reviewers []string `json:"reviewers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, codeHostRequest{Method: r.Method, Path: r.URL.Path, Reviewers: body.Reviewers})
+	f.mu.Unlock()
+	if strings.Contains(r.URL.Path, "/rejected-") {
+		http.Error(w, "Validation Failed", http.StatusUnprocessableEntity)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeCodeHost) count(method, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, req := range f.requests {
+		if req.Method == method && req.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeCodeHost) waitFor(method, path string, timeout time.Duration) (codeHostRequest, bool) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		f.mu.Lock()
+		for _, req := range f.requests {
+			if req.Method == method && req.Path == path {
+				f.mu.Unlock()
+				return req, true
+			}
+		}
+		f.mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+	}
+	return codeHostRequest{}, false
+}
+
 func TestMain(m *testing.M) {
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
 		log.Fatal("DATABASE_URL env variable is required")
 	}
 
-	repo := postgres.NewPostgresRepo(dbURL)
+	repo = postgres.NewPostgresRepo(dbURL)
 	if err := repo.Migrate(); err != nil {
 		log.Fatal("migration failed:", err)
 	}
 
-	service := services.NewService(repo)
-
-	handler := &httphandler.Handler{S: service}
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-
-	r.Post("/team/add", handler.CreateTeam)
-	r.Get("/team/get", handler.GetTeam)
+	gitHubServer := httptest.NewServer(fakeGitHub)
 
-	r.Post("/users/setIsActive", handler.SetUserActive)
-	r.Post("/users/deactivate", handler.DeactivateUsersHandler) // безопасная массовая деактивация
-	r.Get("/users/getReview", handler.GetUserPRs)
+	service := services.NewService(repo,
+		services.WithCodeHost(github.NewClient(gitHubServer.URL, "test-token")),
+		services.WithCodeHostRetry(3, 10*time.Millisecond),
+	)
 
-	r.Post("/pullRequest/create", handler.CreatePR)
-	r.Post("/pullRequest/merge", handler.MergePR)
-	r.Post("/pullRequest/reassign", handler.ReassignReviewer)
+	ctx, cancel := context.WithCancel(context.Background())
+	go service.RunUnavailabilityWatcher(ctx, 50*time.Millisecond)
 
-	r.Get("/stats", handler.GetStats)
+	handler := &httphandler.Handler{S: service, Migrations: repo}
+	// лимиты заведомо выше нагрузки тестов: middleware стоят, как в проде, но не мешают
+	r := httphandler.NewRouter(handler, httphandler.RouterConfig{
+		RateLimits: httphandler.NewMemoryRateLimitStore(),
+		RateLimit: httphandler.RateLimitConfig{
+			PerIP: domain.RateLimit{PerSecond: 1000, Burst: 1000},
+		},
+		MaxBodyBytes:   1 << 20,
+		Idempotency:    repo,
+		IdempotencyTTL: time.Hour,
+		AdminToken:     adminToken,
+		GraphQL:        graphql.NewHandler(service, repo),
+	})
 
 	server = httptest.NewServer(r)
 	code := m.Run()
+	cancel()
 	server.Close()
+	service.Close()
+	gitHubServer.Close()
 	os.Exit(code)
 }
@@ -0,0 +1,131 @@
+package e2e
+
+import (
+	"PRService/internal/domain"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// buildPrctl собирает утилиту, чтобы проверять её вывод и коды выхода как у оператора.
+func buildPrctl(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "prctl")
+	if out, err := exec.Command("go", "build", "-o", bin, "PRService/cmd/prctl").CombinedOutput(); err != nil {
+		t.Fatalf("failed to build prctl: %v\n%s", err, out)
+	}
+	return bin
+}
+
+// prctl запускает утилиту против тестового сервера и возвращает stdout, stderr и код выхода.
+func prctl(t *testing.T, bin string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(bin, append([]string{"-url", server.URL}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return stdout.String(), stderr.String(), exitErr.ExitCode()
+	case err != nil:
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), 0
+}
+
+func TestPrctl(t *testing.T) {
+	bin := buildPrctl(t)
+	teamName, members := createTeam(t)
+
+	t.Run("table", func(t *testing.T) {
+		out, stderr, code := prctl(t, bin, "teams", "get", teamName)
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+		}
+		if !strings.HasPrefix(out, "team: "+teamName+"\n") || !strings.Contains(out, "USER_ID") {
+			t.Fatalf("unexpected table output:\n%s", out)
+		}
+		for _, m := range members {
+			if !strings.Contains(out, m) {
+				t.Fatalf("member %s missing from table:\n%s", m, out)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		// флаги можно указывать после команды
+		out, stderr, code := prctl(t, bin, "teams", "get", teamName, "-o", "json")
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+		}
+		var team domain.Team
+		if err := json.Unmarshal([]byte(out), &team); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, out)
+		}
+		if team.TeamName != teamName || len(team.Members) != 2 {
+			t.Fatalf("unexpected team %+v", team)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		prID := uniqueName("pr_prctl")
+		out, stderr, code := prctl(t, bin, "-o", "yaml", "prs", "create", "-id", prID, "-name", "CLI", "-author", members[0])
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+		}
+		var pr struct {
+			PullRequestID     string   `yaml:"pull_request_id"`
+			Status            string   `yaml:"status"`
+			AssignedReviewers []string `yaml:"assigned_reviewers"`
+		}
+		if err := yaml.Unmarshal([]byte(out), &pr); err != nil {
+			t.Fatalf("invalid YAML output: %v\n%s", err, out)
+		}
+		if pr.PullRequestID != prID || pr.Status != string(domain.StatusOpen) || len(pr.AssignedReviewers) != 1 {
+			t.Fatalf("unexpected PR %+v", pr)
+		}
+
+		out, stderr, code = prctl(t, bin, "prs", "merge", prID)
+		if code != 0 || !strings.Contains(out, prID) || !strings.Contains(out, string(domain.StatusMerged)) {
+			t.Fatalf("merge failed with code %d: %s%s", code, out, stderr)
+		}
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		out, stderr, code := prctl(t, bin, "users", "get", uniqueName("ghost"))
+		if code != 1 || out != "" || !strings.Contains(stderr, "(404)") {
+			t.Fatalf("expected exit code 1 with 404 in stderr, got %d: %q %q", code, out, stderr)
+		}
+		if _, stderr, code := prctl(t, bin, "migrations", "status"); code != 1 || !strings.Contains(stderr, "(401)") {
+			t.Fatalf("expected 401 without admin token, got %d: %q", code, stderr)
+		}
+		out, stderr, code = prctl(t, bin, "-token", adminToken, "migrations", "status")
+		if code != 0 || !strings.Contains(out, "up to date") {
+			t.Fatalf("expected migration status, got %d: %q %q", code, out, stderr)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"unknown"},
+			{"teams"},
+			{"teams", "get"},
+			{"prs", "create", "-id", "x"},
+			{"teams", "get", teamName, "-bogus"},
+		} {
+			if _, stderr, code := prctl(t, bin, args...); code != 2 || !strings.Contains(stderr, "usage: prctl") && !strings.Contains(stderr, "flag provided but not defined") {
+				t.Fatalf("%v: expected exit code 2 with usage, got %d: %q", args, code, stderr)
+			}
+		}
+		if _, stderr, code := prctl(t, bin, "teams", "get", teamName, "-o", "xml"); code != 1 || !strings.Contains(stderr, "unknown output format") {
+			t.Fatalf("expected exit code 1 for unknown format, got %d: %q", code, stderr)
+		}
+	})
+}